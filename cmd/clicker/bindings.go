@@ -0,0 +1,168 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"clicker/internal/core/autoclicker"
+)
+
+type bindingConfig struct {
+	triggerCode uint16
+	outputCode  uint16
+	triggerRaw  string
+	outputRaw   string
+	cps         float64
+	downMS      float64
+	jitter      int
+}
+
+type bindingFlag []string
+
+func (f *bindingFlag) String() string {
+	return strings.Join(*f, ",")
+}
+
+func (f *bindingFlag) Set(value string) error {
+	*f = append(*f, value)
+	return nil
+}
+
+func parseBindingSpec(spec string, defaults bindingConfig) (bindingConfig, error) {
+	parts := strings.Split(strings.TrimSpace(spec), ":")
+	if len(parts) < 2 || len(parts) > 5 {
+		return bindingConfig{}, fmt.Errorf("invalid --bind %q (expected TRIGGER:OUTPUT[:CPS[:DOWN_MS[:JITTER]]])", spec)
+	}
+
+	binding := defaults
+	triggerCode, err := parseTriggerCode(parts[0])
+	if err != nil {
+		return bindingConfig{}, fmt.Errorf("invalid --bind %q: %w", spec, err)
+	}
+	outputCode, err := parseTriggerCode(parts[1])
+	if err != nil {
+		return bindingConfig{}, fmt.Errorf("invalid --bind %q: %w", spec, err)
+	}
+	binding.triggerCode = triggerCode
+	binding.outputCode = outputCode
+	binding.triggerRaw = formatCodeName(triggerCode)
+	binding.outputRaw = formatCodeName(outputCode)
+
+	if len(parts) > 2 && strings.TrimSpace(parts[2]) != "" {
+		binding.cps, err = strconv.ParseFloat(strings.TrimSpace(parts[2]), 64)
+		if err != nil || binding.cps <= 0 {
+			return bindingConfig{}, fmt.Errorf("invalid --bind %q: cps must be > 0", spec)
+		}
+	}
+	if len(parts) > 3 && strings.TrimSpace(parts[3]) != "" {
+		binding.downMS, err = strconv.ParseFloat(strings.TrimSpace(parts[3]), 64)
+		if err != nil || binding.downMS < 0 {
+			return bindingConfig{}, fmt.Errorf("invalid --bind %q: down-ms must be >= 0", spec)
+		}
+	}
+	if len(parts) > 4 && strings.TrimSpace(parts[4]) != "" {
+		binding.jitter, err = strconv.Atoi(strings.TrimSpace(parts[4]))
+		if err != nil || binding.jitter < 0 {
+			return bindingConfig{}, fmt.Errorf("invalid --bind %q: jitter must be >= 0", spec)
+		}
+	}
+	return binding, nil
+}
+
+func validateBindings(bindings []bindingConfig, toggleCode uint16) error {
+	for _, binding := range bindings {
+		if binding.triggerCode == toggleCode {
+			return fmt.Errorf("binding trigger %s must be different from toggle", formatCodeName(binding.triggerCode))
+		}
+		if binding.outputCode == toggleCode {
+			return fmt.Errorf("binding output %s must be different from toggle", formatCodeName(binding.outputCode))
+		}
+		if autoclicker.ChordModifiers(binding.outputCode) != 0 {
+			return fmt.Errorf("binding output %s cannot be a chord", formatCodeName(binding.outputCode))
+		}
+	}
+	return nil
+}
+
+func (cfg config) triggerCodes() []uint16 {
+	codes := []uint16{cfg.triggerCode}
+	for _, binding := range cfg.bindings {
+		codes = append(codes, binding.triggerCode)
+	}
+	return codes
+}
+
+func (cfg config) coreBindings() []autoclicker.Binding {
+	bindings := make([]autoclicker.Binding, 0, len(cfg.bindings))
+	for _, binding := range cfg.bindings {
+		bindings = append(bindings, autoclicker.Binding{
+			TriggerCode:  binding.triggerCode,
+			OutputCode:   binding.outputCode,
+			CPS:          binding.cps,
+			ClickDown:    time.Duration(math.Max(0, binding.downMS) * float64(time.Millisecond)),
+			JitterPixels: binding.jitter,
+		})
+	}
+	return bindings
+}
+
+func logBindings(logger *slog.Logger, bindings []bindingConfig) {
+	for _, binding := range bindings {
+		logger.Info(
+			"Binding",
+			"trigger", formatCodeName(binding.triggerCode),
+			"output", formatCodeName(binding.outputCode),
+			"cps", binding.cps,
+			"down_ms", binding.downMS,
+			"jitter", binding.jitter,
+		)
+	}
+}
+
+func settingsFromBindings(bindings []bindingConfig) []uiBinding {
+	out := make([]uiBinding, 0, len(bindings))
+	for _, binding := range bindings {
+		out = append(out, uiBinding{
+			Trigger: binding.triggerRaw,
+			Output:  binding.outputRaw,
+			CPS:     binding.cps,
+			DownMS:  binding.downMS,
+			Jitter:  binding.jitter,
+		})
+	}
+	return out
+}
+
+func bindingsFromSettings(stored []uiBinding, defaults bindingConfig) ([]bindingConfig, error) {
+	out := make([]bindingConfig, 0, len(stored))
+	for _, item := range stored {
+		triggerCode, err := parseTriggerCode(item.Trigger)
+		if err != nil {
+			return nil, err
+		}
+		outputCode, err := parseTriggerCode(item.Output)
+		if err != nil {
+			return nil, err
+		}
+		binding := defaults
+		binding.triggerCode = triggerCode
+		binding.outputCode = outputCode
+		binding.triggerRaw = formatCodeName(triggerCode)
+		binding.outputRaw = formatCodeName(outputCode)
+		if item.CPS > 0 {
+			binding.cps = item.CPS
+		}
+		if item.DownMS >= 0 {
+			binding.downMS = item.DownMS
+		}
+		if item.Jitter >= 0 {
+			binding.jitter = item.Jitter
+		}
+		out = append(out, binding)
+	}
+	return out, nil
+}
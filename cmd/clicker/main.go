@@ -8,28 +8,75 @@ import (
 	"fmt"
 	"io"
 	"log/slog"
+	"math"
 	"os"
 	"os/signal"
 	"strings"
 	"sync"
 	"syscall"
+	"time"
+
+	"clicker/internal/core/autoclicker"
 )
 
 type config struct {
-	triggerCode  uint16
-	toggleCode   uint16
-	triggerRaw   string
-	toggleRaw    string
-	backend      string
-	devicePath   string
-	cps          float64
-	downMS       float64
-	jitter       int
-	startEnabled bool
-	listDevices  bool
-	grabDevices  bool
-	ui           bool
-	logLevel     slog.Level
+	triggerCode   uint16
+	toggleCode    uint16
+	outputCode    uint16
+	panicCode     uint16
+	triggerRaw    string
+	toggleRaw     string
+	outputRaw     string
+	panicRaw      string
+	panicAction   autoclicker.PanicAction
+	pattern       autoclicker.Pattern
+	pauseCodes    []uint16
+	pauseMS       float64
+	pauseTyping   bool
+	typingPauseMS float64
+	toggleGesture autoclicker.ToggleGesture
+	doubleTap     time.Duration
+	longPress     time.Duration
+	backend       string
+	devicePath    string
+	cps           float64
+	timing        string
+	minCPS        float64
+	maxCPS        float64
+	stddevMS      float64
+	curve         string
+	curveAmp      float64
+	curvePeriod   time.Duration
+	curveFile     string
+	rhythmFile    string
+	rhythmPlay    autoclicker.RhythmPlayback
+	downMS        float64
+	downModel     string
+	downMaxMS     float64
+	downStddevMS  float64
+	jitter        int
+	triggerMode   autoclicker.TriggerMode
+	latchMS       float64
+	burst         int
+	burstCooldown time.Duration
+	maxCPSWindow  int
+	catchUp       autoclicker.CatchUpPolicy
+	rampMS        float64
+	rampDownMS    float64
+	rampStart     float64
+	tickRate      float64
+	tickPhaseMS   float64
+	perTick       int
+	maxHold       time.Duration
+	holdDelayMS   float64
+	debouncePress float64
+	debounceRel   float64
+	bindings      []bindingConfig
+	startEnabled  bool
+	listDevices   bool
+	grabDevices   bool
+	ui            bool
+	logLevel      slog.Level
 }
 
 type lineSinkWriter struct {
@@ -107,18 +154,72 @@ func parseConfig(args []string) (config, error) {
 
 	var triggerRaw string
 	var toggleRaw string
+	var outputRaw string
+	var patternRaw string
+	var panicRaw string
+	var panicActionRaw string
+	var pauseRaw string
+	var toggleGestureRaw string
 	var backendRaw string
 	var logLevelRaw string
+	var triggerModeRaw string
+	var timingRaw string
+	var curveRaw string
+	var rhythmPlayRaw string
+	var downModelRaw string
+	var catchUpRaw string
 	var noGrab bool
 	var cliMode bool
+	var bindSpecs bindingFlag
 
-	flags.StringVar(&triggerRaw, "trigger", "BTN_LEFT", "Trigger key/button code name (default: BTN_LEFT). Example: BTN_SIDE, KEY_LEFTALT.")
-	flags.StringVar(&toggleRaw, "toggle", "BTN_EXTRA", "Enable/disable autoclicker when pressed (default: BTN_EXTRA, usually mouse button 5).")
+	flags.StringVar(&triggerRaw, "trigger", "BTN_LEFT", "Trigger key/button code name (default: BTN_LEFT). Example: BTN_SIDE, KEY_LEFTALT, CTRL+KEY_F7.")
+	flags.StringVar(&toggleRaw, "toggle", "BTN_EXTRA", "Enable/disable autoclicker when pressed (default: BTN_EXTRA, usually mouse button 5). Chords like CTRL+SHIFT+KEY_F8 leave the bare key alone.")
+	flags.StringVar(&toggleGestureRaw, "toggle-gesture", "single", "How --toggle is pressed to toggle: single, double (double-tap) or long (long press).")
+	flags.DurationVar(&cfg.doubleTap, "double-tap", autoclicker.DefaultDoubleTapWindow, "Longest time between the presses of a double-tap toggle (default: 300ms).")
+	flags.DurationVar(&cfg.longPress, "long-press", autoclicker.DefaultLongPressThreshold, "How long --toggle is held for a long-press toggle (default: 500ms).")
+	flags.StringVar(&outputRaw, "output", "BTN_LEFT", "Key/button emitted on every click (default: BTN_LEFT). Example: BTN_RIGHT, KEY_SPACE.")
+	flags.StringVar(&patternRaw, "pattern", "", "Click a repeating sequence instead of --output: comma-separated CODE[+CODE...][:HOLD_MS[:GAP_MS]] steps, restarted every hold. Codes joined by + are pressed together; omitted hold and gap follow --down-ms and the rate. Example: BTN_LEFT,BTN_RIGHT or BTN_LEFT:30:70,BTN_LEFT+BTN_RIGHT:40:110.")
+	flags.StringVar(&panicRaw, "panic", "", "Panic key/button: stops clicking and releases every synthesized button at once, even while input is backed up. Example: KEY_PAUSE (default: none).")
+	flags.StringVar(&panicActionRaw, "panic-action", "disable", "What --panic does besides stopping: disable, ungrab (also release grabbed devices) or exit (also quit).")
+	flags.StringVar(&pauseRaw, "pause", "", "Comma-separated keys/buttons that suspend clicking while held and for --pause-ms after a press, e.g. to open chat. Example: KEY_ENTER,KEY_T,KEY_ESC (default: none).")
+	flags.Float64Var(&cfg.pauseMS, "pause-ms", 0, "Keep clicking suspended this many ms after a --pause key is pressed, even once released (0 only while held).")
+	flags.BoolVar(&cfg.pauseTyping, "pause-typing", false, "Suspend clicking for --typing-pause-ms after every key typed on a keyboard (not supported by the x11 backend).")
+	flags.Float64Var(&cfg.typingPauseMS, "typing-pause-ms", float64(autoclicker.DefaultTypingPause.Milliseconds()), "How long a typed key suspends clicking with --pause-typing (default: 1000).")
+	flags.Var(&bindSpecs, "bind", "Additional binding TRIGGER:OUTPUT[:CPS[:DOWN_MS[:JITTER]]] with its own click loop; repeatable. Example: BTN_EXTRA:BTN_RIGHT:12.")
 	flags.StringVar(&backendRaw, "backend", "auto", "Input backend. Linux: auto|wayland|x11. Windows: auto|windows.")
 	flags.StringVar(&cfg.devicePath, "device", "", "Input event device path to listen on, e.g. /dev/input/event4. Auto-detected if omitted.")
 	flags.Float64Var(&cfg.cps, "cps", 16.0, "Clicks per second while held.")
+	flags.StringVar(&timingRaw, "timing", "fixed", "Click interval model: fixed, uniform (--min-cps..--max-cps), gaussian or lognormal (--cps mean, --stddev-ms).")
+	flags.Float64Var(&cfg.minCPS, "min-cps", 0, "Lowest rate for --timing uniform (default: --cps).")
+	flags.Float64Var(&cfg.maxCPS, "max-cps", 0, "Highest rate for --timing uniform (default: --cps).")
+	flags.Float64Var(&cfg.stddevMS, "stddev-ms", 10.0, "Interval standard deviation in ms for --timing gaussian/lognormal (default: 10).")
+	flags.StringVar(&curveRaw, "curve", "none", "Rate curve over a hold: none, walk (bounded random walk), sine (periodic wobble) or schedule (--curve-file).")
+	flags.Float64Var(&cfg.curveAmp, "curve-amplitude", 0.15, "Largest rate change of --curve walk/sine as a fraction of the rate (default: 0.15).")
+	flags.DurationVar(&cfg.curvePeriod, "curve-period", 2*time.Second, "Cycle length of --curve sine, and how long --curve walk takes to drift the full amplitude (default: 2s).")
+	flags.StringVar(&cfg.curveFile, "curve-file", "", "Schedule for --curve schedule: one \"OFFSET CPS\" point per line, e.g. \"1.5s 12\".")
+	flags.StringVar(&cfg.rhythmFile, "rhythm", "", "Click with a rhythm profile made by record-rhythm (.csv or .json) instead of --timing and --down-model.")
+	flags.StringVar(&rhythmPlayRaw, "rhythm-playback", "replay", "How --rhythm is played back: replay (recorded order, looping) or resample (random recorded click each time).")
 	flags.Float64Var(&cfg.downMS, "down-ms", 10.0, "How long each synthetic click stays down in ms (default: 10).")
+	flags.StringVar(&downModelRaw, "down-model", "fixed", "Click down duration model: fixed, uniform (--down-ms..--down-max-ms), gaussian or lognormal (--down-ms mean, --down-stddev-ms).")
+	flags.Float64Var(&cfg.downMaxMS, "down-max-ms", 0, "Longest click down time in ms for --down-model uniform (default: --down-ms).")
+	flags.Float64Var(&cfg.downStddevMS, "down-stddev-ms", 3.0, "Click down standard deviation in ms for --down-model gaussian/lognormal (default: 3).")
 	flags.IntVar(&cfg.jitter, "jitter", 0, "Maximum random cursor jitter offset in pixels per click (0 disables).")
+	flags.StringVar(&triggerModeRaw, "trigger-mode", "hold", "How the trigger starts clicking: hold, latch (press to start, press again to stop) or hold-or-latch (short press latches).")
+	flags.IntVar(&cfg.burst, "burst", 0, "Clicks emitted per trigger press before stopping, even while held (0 clicks for as long as held).")
+	flags.DurationVar(&cfg.burstCooldown, "burst-cooldown", 0, "Minimum pause between bursts, e.g. 250ms (default: 0).")
+	flags.IntVar(&cfg.maxCPSWindow, "max-cps-window", 0, "Never emit more than this many clicks in any rolling second across all bindings; clicks over it wait for room (0 disables).")
+	flags.StringVar(&catchUpRaw, "catch-up", "skip", "What to do when clicking falls a full interval behind: skip (drop missed clicks) or compress (fire them back to back).")
+	flags.Float64Var(&cfg.rampMS, "ramp-ms", 0, "Ramp the rate up over this many ms after the trigger goes down (0 starts at full rate).")
+	flags.Float64Var(&cfg.rampDownMS, "ramp-down-ms", 0, "Keep clicking this many ms after release while easing the rate back down (0 stops on release).")
+	flags.Float64Var(&cfg.rampStart, "ramp-start", autoclicker.DefaultRampStart, "Fraction of the rate the ramps start and end at, in (0, 1] (default: 0.5).")
+	flags.Float64Var(&cfg.tickRate, "tick-rate", 0, "Align clicks to a game input tick of this many ticks per second, e.g. 20 for 50ms ticks; clicks over --clicks-per-tick wait for the next tick (0 disables).")
+	flags.Float64Var(&cfg.tickPhaseMS, "tick-phase-ms", 0, "Shift the --tick-rate tick boundaries by this many ms, within [0, tick period).")
+	flags.IntVar(&cfg.perTick, "clicks-per-tick", 1, "Most clicks started within one --tick-rate tick across all bindings (default: 1).")
+	flags.Float64Var(&cfg.holdDelayMS, "hold-delay-ms", 0, "Start clicking only once the trigger has been held this many ms; shorter presses pass through as ordinary clicks (0 clicks at once).")
+	flags.Float64Var(&cfg.debouncePress, "debounce-press-ms", 0, "Treat a trigger or toggle release within this many ms of its press as switch chatter (0 disables).")
+	flags.Float64Var(&cfg.debounceRel, "debounce-release-ms", 0, "Treat a trigger or toggle release followed by a press within this many ms as switch chatter; releases take effect this much later (0 disables).")
+	flags.DurationVar(&cfg.maxHold, "max-hold", 0, "Stop clicking once the trigger has been held this long, in case its release was missed, e.g. 30s (0 disables).")
+	flags.Float64Var(&cfg.latchMS, "latch-ms", 250.0, "Longest press in ms that latches in hold-or-latch mode (default: 250).")
 	flags.BoolVar(&cfg.listDevices, "list-devices", false, "Print available input devices and exit.")
 	flags.BoolVar(&cfg.grabDevices, "grab", false, "Grab source devices and suppress raw trigger events (recommended for BTN_LEFT on Wayland).")
 	flags.BoolVar(&noGrab, "no-grab", false, "Disable source device grabbing.")
@@ -138,6 +239,112 @@ func parseConfig(args []string) (config, error) {
 	if cfg.jitter < 0 {
 		return cfg, fmt.Errorf("--jitter must be >= 0")
 	}
+	timingKind, err := parseTimingKind(timingRaw)
+	if err != nil {
+		return cfg, err
+	}
+	cfg.timing = timingKind
+	if cfg.minCPS <= 0 {
+		cfg.minCPS = cfg.cps
+	}
+	if cfg.maxCPS <= 0 {
+		cfg.maxCPS = math.Max(cfg.cps, cfg.minCPS)
+	}
+	if cfg.maxCPS < cfg.minCPS {
+		return cfg, fmt.Errorf("--max-cps must be >= --min-cps")
+	}
+	if cfg.stddevMS < 0 {
+		return cfg, fmt.Errorf("--stddev-ms must be >= 0")
+	}
+	curveKind, err := parseCurveKind(curveRaw)
+	if err != nil {
+		return cfg, err
+	}
+	cfg.curve = curveKind
+	if _, err := cfg.rateCurve(); err != nil {
+		return cfg, err
+	}
+	rhythmPlay, err := autoclicker.ParseRhythmPlayback(rhythmPlayRaw)
+	if err != nil {
+		return cfg, fmt.Errorf("invalid --rhythm-playback: %w", err)
+	}
+	cfg.rhythmPlay = rhythmPlay
+	if cfg.rhythmFile != "" {
+		if _, err := loadRhythm(cfg.rhythmFile); err != nil {
+			return cfg, err
+		}
+	}
+	downModelKind, err := parseDownModelKind(downModelRaw)
+	if err != nil {
+		return cfg, err
+	}
+	cfg.downModel = downModelKind
+	if cfg.downMaxMS <= 0 {
+		cfg.downMaxMS = cfg.downMS
+	}
+	if cfg.downMaxMS < cfg.downMS {
+		return cfg, fmt.Errorf("--down-max-ms must be >= --down-ms")
+	}
+	if cfg.downStddevMS < 0 {
+		return cfg, fmt.Errorf("--down-stddev-ms must be >= 0")
+	}
+	if cfg.downModel == timingLogNormal && cfg.downMS <= 0 {
+		return cfg, fmt.Errorf("--down-model lognormal needs --down-ms > 0")
+	}
+	if cfg.burst < 0 {
+		return cfg, fmt.Errorf("--burst must be >= 0")
+	}
+	if cfg.burstCooldown < 0 {
+		return cfg, fmt.Errorf("--burst-cooldown must be >= 0")
+	}
+	if cfg.rampMS < 0 {
+		return cfg, fmt.Errorf("--ramp-ms must be >= 0")
+	}
+	if cfg.rampDownMS < 0 {
+		return cfg, fmt.Errorf("--ramp-down-ms must be >= 0")
+	}
+	if cfg.rampStart <= 0 || cfg.rampStart > 1 {
+		return cfg, fmt.Errorf("--ramp-start must be within (0, 1]")
+	}
+	if cfg.tickRate < 0 || cfg.tickRate > 1000 {
+		return cfg, fmt.Errorf("--tick-rate must be within [0, 1000]")
+	}
+	if cfg.tickPhaseMS < 0 || (cfg.tickRate > 0 && cfg.tickPhaseMS >= 1000/cfg.tickRate) {
+		return cfg, fmt.Errorf("--tick-phase-ms must be within [0, tick period)")
+	}
+	if cfg.perTick < 1 {
+		return cfg, fmt.Errorf("--clicks-per-tick must be >= 1")
+	}
+	if cfg.maxCPSWindow < 0 {
+		return cfg, fmt.Errorf("--max-cps-window must be >= 0")
+	}
+	if cfg.maxHold < 0 {
+		return cfg, fmt.Errorf("--max-hold must be >= 0")
+	}
+	if cfg.holdDelayMS < 0 {
+		return cfg, fmt.Errorf("--hold-delay-ms must be >= 0")
+	}
+	if cfg.debouncePress < 0 {
+		return cfg, fmt.Errorf("--debounce-press-ms must be >= 0")
+	}
+	if cfg.debounceRel < 0 {
+		return cfg, fmt.Errorf("--debounce-release-ms must be >= 0")
+	}
+	if cfg.pauseMS < 0 {
+		return cfg, fmt.Errorf("--pause-ms must be >= 0")
+	}
+	if cfg.typingPauseMS <= 0 {
+		return cfg, fmt.Errorf("--typing-pause-ms must be > 0")
+	}
+	if cfg.doubleTap <= 0 {
+		return cfg, fmt.Errorf("--double-tap must be > 0")
+	}
+	if cfg.longPress <= 0 {
+		return cfg, fmt.Errorf("--long-press must be > 0")
+	}
+	if cfg.latchMS <= 0 {
+		return cfg, fmt.Errorf("--latch-ms must be > 0")
+	}
 	if cfg.grabDevices && noGrab {
 		return cfg, fmt.Errorf("--grab and --no-grab are mutually exclusive")
 	}
@@ -156,9 +363,76 @@ func parseConfig(args []string) (config, error) {
 	if triggerCode == toggleCode {
 		return cfg, fmt.Errorf("--toggle must be different from --trigger")
 	}
+	outputCode, err := parseTriggerCode(outputRaw)
+	if err != nil {
+		return cfg, err
+	}
+	if outputCode == toggleCode {
+		return cfg, fmt.Errorf("--output must be different from --toggle")
+	}
+	if autoclicker.ChordModifiers(outputCode) != 0 {
+		return cfg, fmt.Errorf("--output cannot be a chord")
+	}
+	pattern, err := parsePatternSpec(patternRaw)
+	if err != nil {
+		return cfg, fmt.Errorf("invalid --pattern: %w", err)
+	}
+	for _, code := range pattern.Codes() {
+		if code == toggleCode {
+			return cfg, fmt.Errorf("--pattern must not press --toggle")
+		}
+	}
+
+	bindingDefaults := bindingConfig{cps: cfg.cps, downMS: cfg.downMS, jitter: cfg.jitter}
+	for _, spec := range bindSpecs {
+		binding, err := parseBindingSpec(spec, bindingDefaults)
+		if err != nil {
+			return cfg, err
+		}
+		cfg.bindings = append(cfg.bindings, binding)
+	}
+	if err := validateBindings(cfg.bindings, toggleCode); err != nil {
+		return cfg, err
+	}
+	var panicCode uint16
+	if strings.TrimSpace(panicRaw) != "" {
+		panicCode, err = parseTriggerCode(panicRaw)
+		if err != nil {
+			return cfg, err
+		}
+		check := config{triggerCode: triggerCode, toggleCode: toggleCode, outputCode: outputCode, bindings: cfg.bindings}
+		if err := check.validatePanicCode(panicCode); err != nil {
+			return cfg, fmt.Errorf("--panic %w", err)
+		}
+	}
+	check := config{triggerCode: triggerCode, toggleCode: toggleCode, outputCode: outputCode, panicCode: panicCode, bindings: cfg.bindings}
+	for _, raw := range strings.Split(pauseRaw, ",") {
+		if strings.TrimSpace(raw) == "" {
+			continue
+		}
+		code, err := parseTriggerCode(raw)
+		if err != nil {
+			return cfg, err
+		}
+		if err := check.validatePauseCode(code); err != nil {
+			return cfg, fmt.Errorf("--pause %s %w", formatCodeName(code), err)
+		}
+		cfg.pauseCodes = append(cfg.pauseCodes, code)
+	}
+	panicAction, err := autoclicker.ParsePanicAction(panicActionRaw)
+	if err != nil {
+		return cfg, fmt.Errorf("invalid --panic-action: %w", err)
+	}
+	toggleGesture, err := autoclicker.ParseToggleGesture(toggleGestureRaw)
+	if err != nil {
+		return cfg, fmt.Errorf("invalid --toggle-gesture: %w", err)
+	}
 
 	if !cfg.grabDevices && !noGrab {
 		cfg.grabDevices = defaultGrabForTrigger(triggerCode)
+		for _, binding := range cfg.bindings {
+			cfg.grabDevices = cfg.grabDevices || defaultGrabForTrigger(binding.triggerCode)
+		}
 	}
 
 	parsedLevel, err := parseLogLevel(logLevelRaw)
@@ -169,21 +443,97 @@ func parseConfig(args []string) (config, error) {
 	if err != nil {
 		return cfg, err
 	}
+	triggerMode, err := autoclicker.ParseTriggerMode(triggerModeRaw)
+	if err != nil {
+		return cfg, fmt.Errorf("invalid --trigger-mode: %w", err)
+	}
+	catchUp, err := autoclicker.ParseCatchUpPolicy(catchUpRaw)
+	if err != nil {
+		return cfg, fmt.Errorf("invalid --catch-up: %w", err)
+	}
 
 	cfg.triggerCode = triggerCode
 	cfg.toggleCode = toggleCode
+	cfg.outputCode = outputCode
+	cfg.pattern = pattern
+	cfg.panicCode = panicCode
 	cfg.triggerRaw = triggerRaw
 	cfg.toggleRaw = toggleRaw
+	cfg.outputRaw = outputRaw
+	cfg.panicRaw = strings.TrimSpace(panicRaw)
+	cfg.panicAction = panicAction
+	cfg.toggleGesture = toggleGesture
 	cfg.backend = backendChoice
+	cfg.triggerMode = triggerMode
+	cfg.catchUp = catchUp
 	cfg.logLevel = parsedLevel
 	return cfg, nil
 }
 
+func (cfg config) validatePanicCode(code uint16) error {
+	if autoclicker.ChordModifiers(code) != 0 {
+		return fmt.Errorf("cannot be a chord")
+	}
+	switch code {
+	case autoclicker.ChordKey(cfg.triggerCode):
+		return fmt.Errorf("must be different from trigger")
+	case autoclicker.ChordKey(cfg.toggleCode):
+		return fmt.Errorf("must be different from toggle")
+	case cfg.outputCode:
+		return fmt.Errorf("must be different from output")
+	}
+	for _, binding := range cfg.bindings {
+		if code == autoclicker.ChordKey(binding.triggerCode) || code == binding.outputCode {
+			return fmt.Errorf("must be different from binding %s:%s", formatCodeName(binding.triggerCode), formatCodeName(binding.outputCode))
+		}
+	}
+	return nil
+}
+
+func (cfg config) validatePauseCode(code uint16) error {
+	if autoclicker.ChordModifiers(code) != 0 {
+		return fmt.Errorf("cannot be a chord")
+	}
+	switch code {
+	case autoclicker.ChordKey(cfg.triggerCode):
+		return fmt.Errorf("must be different from trigger")
+	case autoclicker.ChordKey(cfg.toggleCode):
+		return fmt.Errorf("must be different from toggle")
+	case cfg.panicCode:
+		return fmt.Errorf("must be different from panic")
+	}
+	for _, binding := range cfg.bindings {
+		if code == autoclicker.ChordKey(binding.triggerCode) {
+			return fmt.Errorf("must be different from binding trigger %s", formatCodeName(binding.triggerCode))
+		}
+	}
+	return nil
+}
+
+func formatCodeNames(codes []uint16) string {
+	names := make([]string, 0, len(codes))
+	for _, code := range codes {
+		names = append(names, formatCodeName(code))
+	}
+	return strings.Join(names, ",")
+}
+
+func exitOnPanic(action autoclicker.PanicAction) {
+	if action == autoclicker.PanicExit {
+		fmt.Fprintln(os.Stderr, "panic key pressed, exiting")
+		os.Exit(1)
+	}
+}
+
 func isPermissionError(err error) bool {
 	return errors.Is(err, os.ErrPermission) || errors.Is(err, syscall.EPERM) || errors.Is(err, syscall.EACCES)
 }
 
 func run(args []string, stderr io.Writer) int {
+	if len(args) > 0 && args[0] == "record-rhythm" {
+		return runRecordRhythm(args[1:], stderr)
+	}
+
 	cfg, err := parseConfig(args)
 	if err != nil {
 		if errors.Is(err, flag.ErrHelp) {
@@ -223,10 +573,37 @@ func run(args []string, stderr io.Writer) int {
 
 	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
 	defer cancel()
-	<-ctx.Done()
+	printStatsUntilDone(ctx, runtime, stderr, logger)
 	return 0
 }
 
+func printStatsUntilDone(ctx context.Context, runtime clickerRuntime, stderr io.Writer, logger *slog.Logger) {
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	var lastClicks, lastBounces, lastDeferred int64
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			stats := runtime.Stats()
+			if stats.Clicks == lastClicks && stats.Bounces == lastBounces && stats.DeferredClicks == lastDeferred {
+				continue
+			}
+			lastClicks = stats.Clicks
+			lastBounces = stats.Bounces
+			lastDeferred = stats.DeferredClicks
+			fmt.Fprintln(stderr, formatStats(stats))
+			logger.Info(
+				"Timing",
+				"p99_late", stats.P99Lateness.Round(time.Microsecond),
+				"missed", stats.MissedDeadlines,
+			)
+		}
+	}
+}
+
 func main() {
 	os.Exit(run(os.Args[1:], os.Stderr))
 }
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"clicker/internal/core/autoclicker"
+)
+
+func parsePatternSpec(spec string) (autoclicker.Pattern, error) {
+	spec = strings.TrimSpace(spec)
+	if spec == "" {
+		return nil, nil
+	}
+	var pattern autoclicker.Pattern
+	for i, raw := range strings.Split(spec, ",") {
+		parts := strings.Split(strings.TrimSpace(raw), ":")
+		if len(parts) > 3 || strings.TrimSpace(parts[0]) == "" {
+			return nil, fmt.Errorf("invalid pattern step %q (expected CODE[+CODE...][:HOLD_MS[:GAP_MS]])", raw)
+		}
+
+		var step autoclicker.PatternStep
+		for _, name := range strings.Split(parts[0], "+") {
+			code, err := parseTriggerCode(strings.TrimSpace(name))
+			if err != nil {
+				return nil, fmt.Errorf("pattern step %d: %w", i+1, err)
+			}
+			step.Codes = append(step.Codes, code)
+		}
+		if len(parts) > 1 && strings.TrimSpace(parts[1]) != "" {
+			ms, err := strconv.ParseFloat(strings.TrimSpace(parts[1]), 64)
+			if err != nil || ms < 0 {
+				return nil, fmt.Errorf("pattern step %d: hold must be >= 0 ms", i+1)
+			}
+			step.Hold = time.Duration(ms * float64(time.Millisecond))
+		}
+		if len(parts) > 2 && strings.TrimSpace(parts[2]) != "" {
+			ms, err := strconv.ParseFloat(strings.TrimSpace(parts[2]), 64)
+			if err != nil || ms < 0 {
+				return nil, fmt.Errorf("pattern step %d: gap must be >= 0 ms", i+1)
+			}
+			step.Gap = time.Duration(ms * float64(time.Millisecond))
+		}
+		pattern = append(pattern, step)
+	}
+	return pattern, nil
+}
+
+func formatPatternSpec(pattern autoclicker.Pattern) string {
+	steps := make([]string, 0, len(pattern))
+	for _, step := range pattern {
+		names := make([]string, 0, len(step.Codes))
+		for _, code := range step.Codes {
+			names = append(names, formatCodeName(code))
+		}
+		text := strings.Join(names, "+")
+		hold := float64(step.Hold) / float64(time.Millisecond)
+		switch {
+		case step.Gap > 0:
+			text += fmt.Sprintf(":%g:%g", hold, float64(step.Gap)/float64(time.Millisecond))
+		case step.Hold > 0:
+			text += fmt.Sprintf(":%g", hold)
+		}
+		steps = append(steps, text)
+	}
+	return strings.Join(steps, ",")
+}
@@ -14,6 +14,7 @@ import (
 
 	"clicker/internal/adapters/linuxinput"
 	"clicker/internal/adapters/x11input"
+	"clicker/internal/core/autoclicker"
 )
 
 func parseTriggerCode(value string) (uint16, error) {
@@ -42,6 +43,15 @@ func captureNextCode(backend, devicePath string, timeout time.Duration) (uint16,
 	}
 }
 
+func watchKey(backend, devicePath string, code uint16, stop <-chan struct{}, fn func(down bool, at time.Time)) error {
+	switch resolveLinuxBackend(backend) {
+	case "x11":
+		return x11input.WatchKey(code, stop, fn)
+	default:
+		return linuxinput.WatchKey(devicePath, code, stop, fn)
+	}
+}
+
 func formatCodeName(code uint16) string {
 	return linuxinput.FormatCodeName(code)
 }
@@ -102,12 +112,123 @@ func startClickerFromConfig(cfg config, logger *slog.Logger) (clickerRuntime, er
 	}
 }
 
+func updateClickerFromConfig(runtime clickerRuntime, cfg config) error {
+	switch runtime := runtime.(type) {
+	case *linuxinput.Runtime:
+		runtimeCfg, err := waylandRuntimeConfig(cfg)
+		if err != nil {
+			return err
+		}
+		return runtime.UpdateConfig(runtimeCfg)
+	case *x11input.Runtime:
+		runtimeCfg, err := x11RuntimeConfig(cfg)
+		if err != nil {
+			return err
+		}
+		return runtime.UpdateConfig(runtimeCfg)
+	default:
+		return fmt.Errorf("runtime does not support config updates")
+	}
+}
+
+func waylandRuntimeConfig(cfg config) (linuxinput.RuntimeConfig, error) {
+	timing, clickDownModel, err := cfg.clickModels()
+	if err != nil {
+		return linuxinput.RuntimeConfig{}, err
+	}
+	curve, err := cfg.rateCurve()
+	if err != nil {
+		return linuxinput.RuntimeConfig{}, err
+	}
+	return linuxinput.RuntimeConfig{
+		TriggerCode:        cfg.triggerCode,
+		ToggleCode:         cfg.toggleCode,
+		OutputCode:         cfg.outputCode,
+		CPS:                cfg.cps,
+		Timing:             timing,
+		Curve:              curve,
+		ClickDown:          time.Duration(math.Max(0, cfg.downMS) * float64(time.Millisecond)),
+		ClickDownModel:     clickDownModel,
+		JitterPixels:       cfg.jitter,
+		Pattern:            cfg.pattern,
+		StartEnabled:       cfg.startEnabled,
+		TriggerMode:        cfg.triggerMode,
+		LatchThreshold:     time.Duration(cfg.latchMS * float64(time.Millisecond)),
+		BurstCount:         cfg.burst,
+		BurstCooldown:      cfg.burstCooldown,
+		MaxCPSWindow:       cfg.maxCPSWindow,
+		CatchUp:            cfg.catchUp,
+		Ramp:               cfg.ramp(),
+		Ticks:              cfg.ticks(),
+		MaxHold:            cfg.maxHold,
+		HoldDelay:          time.Duration(cfg.holdDelayMS * float64(time.Millisecond)),
+		DebouncePress:      time.Duration(cfg.debouncePress * float64(time.Millisecond)),
+		DebounceRelease:    time.Duration(cfg.debounceRel * float64(time.Millisecond)),
+		ToggleGesture:      cfg.toggleGesture,
+		DoubleTapWindow:    cfg.doubleTap,
+		LongPressThreshold: cfg.longPress,
+		PanicCode:          cfg.panicCode,
+		PauseCodes:         cfg.pauseCodes,
+		PauseFor:           time.Duration(cfg.pauseMS * float64(time.Millisecond)),
+		PauseWhileTyping:   cfg.pauseTyping,
+		TypingPause:        time.Duration(cfg.typingPauseMS * float64(time.Millisecond)),
+		PanicAction:        cfg.panicAction,
+		OnPanic:            exitOnPanic,
+		GrabDevices:        cfg.grabDevices,
+		PassThroughTrigger: cfg.ui,
+		Bindings:           cfg.coreBindings(),
+	}, nil
+}
+
+func x11RuntimeConfig(cfg config) (x11input.RuntimeConfig, error) {
+	timing, clickDownModel, err := cfg.clickModels()
+	if err != nil {
+		return x11input.RuntimeConfig{}, err
+	}
+	curve, err := cfg.rateCurve()
+	if err != nil {
+		return x11input.RuntimeConfig{}, err
+	}
+	return x11input.RuntimeConfig{
+		TriggerCode:        cfg.triggerCode,
+		ToggleCode:         cfg.toggleCode,
+		OutputCode:         cfg.outputCode,
+		CPS:                cfg.cps,
+		Timing:             timing,
+		Curve:              curve,
+		ClickDown:          time.Duration(math.Max(0, cfg.downMS) * float64(time.Millisecond)),
+		ClickDownModel:     clickDownModel,
+		JitterPixels:       cfg.jitter,
+		Pattern:            cfg.pattern,
+		StartEnabled:       cfg.startEnabled,
+		TriggerMode:        cfg.triggerMode,
+		LatchThreshold:     time.Duration(cfg.latchMS * float64(time.Millisecond)),
+		BurstCount:         cfg.burst,
+		BurstCooldown:      cfg.burstCooldown,
+		MaxCPSWindow:       cfg.maxCPSWindow,
+		CatchUp:            cfg.catchUp,
+		Ramp:               cfg.ramp(),
+		Ticks:              cfg.ticks(),
+		MaxHold:            cfg.maxHold,
+		HoldDelay:          time.Duration(cfg.holdDelayMS * float64(time.Millisecond)),
+		DebouncePress:      time.Duration(cfg.debouncePress * float64(time.Millisecond)),
+		DebounceRelease:    time.Duration(cfg.debounceRel * float64(time.Millisecond)),
+		ToggleGesture:      cfg.toggleGesture,
+		DoubleTapWindow:    cfg.doubleTap,
+		LongPressThreshold: cfg.longPress,
+		PanicCode:          cfg.panicCode,
+		PanicAction:        cfg.panicAction,
+		OnPanic:            exitOnPanic,
+		Bindings:           cfg.coreBindings(),
+	}, nil
+}
+
 func startWaylandClickerFromConfig(cfg config, logger *slog.Logger) (clickerRuntime, error) {
 	return startWaylandClickerFromConfigWithRetry(cfg, logger, true)
 }
 
 func startWaylandClickerFromConfigWithRetry(cfg config, logger *slog.Logger, allowNoGrabFallback bool) (clickerRuntime, error) {
-	selection, err := linuxinput.OpenSourceSelection(cfg.devicePath, cfg.triggerCode, cfg.toggleCode)
+	selection, err := linuxinput.OpenSourceSelection(cfg.devicePath, cfg.triggerCodes(), cfg.toggleCode, cfg.panicCode, cfg.pauseCodes, cfg.pauseTyping)
 	if err != nil {
 		return nil, err
 	}
@@ -117,21 +238,14 @@ func startWaylandClickerFromConfigWithRetry(cfg config, logger *slog.Logger, all
 		logger.Info("Using source device", "path", dev.Path(), "name", name)
 	}
 
-	clickDown := time.Duration(math.Max(0, cfg.downMS) * float64(time.Millisecond))
-	runtime, err := linuxinput.NewRuntime(
-		selection,
-		linuxinput.RuntimeConfig{
-			TriggerCode:        cfg.triggerCode,
-			ToggleCode:         cfg.toggleCode,
-			CPS:                cfg.cps,
-			ClickDown:          clickDown,
-			JitterPixels:       cfg.jitter,
-			StartEnabled:       cfg.startEnabled,
-			GrabDevices:        cfg.grabDevices,
-			PassThroughTrigger: cfg.ui,
-		},
-		logger,
-	)
+	runtimeCfg, err := waylandRuntimeConfig(cfg)
+	if err != nil {
+		for _, dev := range selection.Devices {
+			_ = dev.Close()
+		}
+		return nil, err
+	}
+	runtime, err := linuxinput.NewRuntime(selection, runtimeCfg, logger)
 	if err != nil {
 		for _, dev := range selection.Devices {
 			_ = dev.Close()
@@ -150,10 +264,47 @@ func startWaylandClickerFromConfigWithRetry(cfg config, logger *slog.Logger, all
 	}
 
 	logger.Info("Backend", "name", "wayland")
-	logger.Info("Trigger", "name", formatCodeName(cfg.triggerCode), "code", cfg.triggerCode)
+	logger.Info("Trigger", "name", formatCodeName(cfg.triggerCode), "code", cfg.triggerCode, "mode", cfg.triggerMode)
 	logger.Info("Toggle", "name", formatCodeName(cfg.toggleCode), "code", cfg.toggleCode)
-	logger.Info("Rate", "cps", cfg.cps)
+	logger.Info("Output", "name", formatCodeName(cfg.outputCode), "code", cfg.outputCode)
+	if len(cfg.pattern) > 0 {
+		logger.Info("Pattern", "steps", formatPatternSpec(cfg.pattern))
+	}
+	logger.Info("Rate", "timing", runtimeCfg.Timing, "catch_up", cfg.catchUp)
+	if runtimeCfg.Curve != nil {
+		logger.Info("Rate curve", "curve", runtimeCfg.Curve)
+	}
+	logger.Info("Click down", "model", runtimeCfg.ClickDownModel)
 	logger.Info("Jitter", "pixels", cfg.jitter)
+	logger.Info("Ramp", "ramp", runtimeCfg.Ramp)
+	if runtimeCfg.Ticks.Rate > 0 {
+		logger.Info("Ticks", "ticks", runtimeCfg.Ticks)
+	}
+	if cfg.maxCPSWindow > 0 {
+		logger.Info("Click cap", "clicks_per_second", cfg.maxCPSWindow)
+	}
+	if cfg.maxHold > 0 {
+		logger.Info("Max hold", "duration", cfg.maxHold)
+	}
+	if cfg.holdDelayMS > 0 {
+		logger.Info("Hold delay", "ms", cfg.holdDelayMS)
+	}
+	if cfg.debouncePress > 0 || cfg.debounceRel > 0 {
+		logger.Info("Debounce", "press_ms", cfg.debouncePress, "release_ms", cfg.debounceRel)
+	}
+	if len(cfg.pauseCodes) > 0 || cfg.pauseTyping {
+		logger.Info("Pause", "keys", formatCodeNames(cfg.pauseCodes), "ms", cfg.pauseMS, "typing", cfg.pauseTyping)
+	}
+	if cfg.toggleGesture != autoclicker.ToggleSingle {
+		logger.Info("Toggle gesture", "gesture", cfg.toggleGesture, "double_tap", cfg.doubleTap, "long_press", cfg.longPress)
+	}
+	if cfg.panicCode != 0 {
+		logger.Info("Panic", "name", formatCodeName(cfg.panicCode), "code", cfg.panicCode, "action", cfg.panicAction)
+	}
+	if cfg.burst > 0 {
+		logger.Info("Burst", "clicks", cfg.burst, "cooldown", cfg.burstCooldown)
+	}
+	logBindings(logger, cfg.bindings)
 	if runtime.GrabEnabled() {
 		logger.Info("Grab mode enabled")
 	} else {
@@ -167,7 +318,7 @@ func startWaylandClickerFromConfigWithRetry(cfg config, logger *slog.Logger, all
 	} else {
 		logger.Info("Initial state disabled (press toggle to enable/disable)")
 	}
-	logger.Info("Hold trigger to autoclick left mouse button. Press Ctrl+C to stop")
+	logger.Info("Hold trigger to autoclick output. Press Ctrl+C to stop")
 	return runtime, nil
 }
 
@@ -178,19 +329,15 @@ func startX11ClickerFromConfig(cfg config, logger *slog.Logger) (clickerRuntime,
 	if cfg.grabDevices {
 		logger.Warn("--grab is ignored on X11 backend")
 	}
+	if len(cfg.pauseCodes) > 0 || cfg.pauseTyping {
+		logger.Warn("--pause and --pause-typing are ignored on X11 backend")
+	}
 
-	clickDown := time.Duration(math.Max(0, cfg.downMS) * float64(time.Millisecond))
-	runtime, err := x11input.NewRuntime(
-		x11input.RuntimeConfig{
-			TriggerCode:  cfg.triggerCode,
-			ToggleCode:   cfg.toggleCode,
-			CPS:          cfg.cps,
-			ClickDown:    clickDown,
-			JitterPixels: cfg.jitter,
-			StartEnabled: cfg.startEnabled,
-		},
-		logger,
-	)
+	runtimeCfg, err := x11RuntimeConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+	runtime, err := x11input.NewRuntime(runtimeCfg, logger)
 	if err != nil {
 		return nil, err
 	}
@@ -201,16 +348,50 @@ func startX11ClickerFromConfig(cfg config, logger *slog.Logger) (clickerRuntime,
 	}
 
 	logger.Info("Backend", "name", "x11")
-	logger.Info("Trigger", "name", formatCodeName(cfg.triggerCode), "code", cfg.triggerCode)
+	logger.Info("Trigger", "name", formatCodeName(cfg.triggerCode), "code", cfg.triggerCode, "mode", cfg.triggerMode)
 	logger.Info("Toggle", "name", formatCodeName(cfg.toggleCode), "code", cfg.toggleCode)
-	logger.Info("Rate", "cps", cfg.cps)
+	logger.Info("Output", "name", formatCodeName(cfg.outputCode), "code", cfg.outputCode)
+	if len(cfg.pattern) > 0 {
+		logger.Info("Pattern", "steps", formatPatternSpec(cfg.pattern))
+	}
+	logger.Info("Rate", "timing", runtimeCfg.Timing, "catch_up", cfg.catchUp)
+	if runtimeCfg.Curve != nil {
+		logger.Info("Rate curve", "curve", runtimeCfg.Curve)
+	}
+	logger.Info("Click down", "model", runtimeCfg.ClickDownModel)
 	logger.Info("Jitter", "pixels", cfg.jitter)
+	logger.Info("Ramp", "ramp", runtimeCfg.Ramp)
+	if runtimeCfg.Ticks.Rate > 0 {
+		logger.Info("Ticks", "ticks", runtimeCfg.Ticks)
+	}
+	if cfg.maxCPSWindow > 0 {
+		logger.Info("Click cap", "clicks_per_second", cfg.maxCPSWindow)
+	}
+	if cfg.maxHold > 0 {
+		logger.Info("Max hold", "duration", cfg.maxHold)
+	}
+	if cfg.holdDelayMS > 0 {
+		logger.Info("Hold delay", "ms", cfg.holdDelayMS)
+	}
+	if cfg.debouncePress > 0 || cfg.debounceRel > 0 {
+		logger.Info("Debounce", "press_ms", cfg.debouncePress, "release_ms", cfg.debounceRel)
+	}
+	if cfg.toggleGesture != autoclicker.ToggleSingle {
+		logger.Info("Toggle gesture", "gesture", cfg.toggleGesture, "double_tap", cfg.doubleTap, "long_press", cfg.longPress)
+	}
+	if cfg.panicCode != 0 {
+		logger.Info("Panic", "name", formatCodeName(cfg.panicCode), "code", cfg.panicCode, "action", cfg.panicAction)
+	}
+	if cfg.burst > 0 {
+		logger.Info("Burst", "clicks", cfg.burst, "cooldown", cfg.burstCooldown)
+	}
+	logBindings(logger, cfg.bindings)
 	if cfg.startEnabled {
 		logger.Info("Initial state enabled (press toggle to disable/enable)")
 	} else {
 		logger.Info("Initial state disabled (press toggle to enable/disable)")
 	}
-	logger.Info("Hold trigger to autoclick left mouse button. Press Ctrl+C to stop")
+	logger.Info("Hold trigger to autoclick output. Press Ctrl+C to stop")
 	return runtime, nil
 }
 
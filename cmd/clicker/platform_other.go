@@ -25,6 +25,10 @@ func captureNextCode(_ string, _ string, timeout time.Duration) (uint16, error)
 	return 0, fmt.Errorf("unsupported platform")
 }
 
+func watchKey(_ string, _ string, _ uint16, _ <-chan struct{}, _ func(bool, time.Time)) error {
+	return fmt.Errorf("unsupported platform")
+}
+
 func formatCodeName(code uint16) string {
 	return fmt.Sprintf("%d", code)
 }
@@ -44,3 +48,7 @@ func permissionDeniedHint() string {
 func startClickerFromConfig(cfg config, logger *slog.Logger) (clickerRuntime, error) {
 	return nil, fmt.Errorf("clicker runtime is not supported on this platform")
 }
+
+func updateClickerFromConfig(_ clickerRuntime, _ config) error {
+	return fmt.Errorf("clicker runtime is not supported on this platform")
+}
@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"clicker/internal/adapters/wininput"
+	"clicker/internal/core/autoclicker"
 )
 
 func parseTriggerCode(value string) (uint16, error) {
@@ -33,6 +34,10 @@ func captureNextCode(_ string, _ string, timeout time.Duration) (uint16, error)
 	return wininput.CaptureNextKeyCode(timeout)
 }
 
+func watchKey(_ string, _ string, code uint16, stop <-chan struct{}, fn func(down bool, at time.Time)) error {
+	return wininput.WatchKey(code, stop, fn)
+}
+
 func formatCodeName(code uint16) string {
 	return wininput.FormatCodeName(code)
 }
@@ -64,6 +69,65 @@ func permissionDeniedHint() string {
 	return "Permission denied registering global input hooks. Run as Administrator and ensure input-hooking is allowed."
 }
 
+func updateClickerFromConfig(runtime clickerRuntime, cfg config) error {
+	winRuntime, ok := runtime.(*wininput.Runtime)
+	if !ok {
+		return fmt.Errorf("runtime does not support config updates")
+	}
+	runtimeCfg, err := windowsRuntimeConfig(cfg)
+	if err != nil {
+		return err
+	}
+	return winRuntime.UpdateConfig(runtimeCfg)
+}
+
+func windowsRuntimeConfig(cfg config) (wininput.RuntimeConfig, error) {
+	timing, clickDownModel, err := cfg.clickModels()
+	if err != nil {
+		return wininput.RuntimeConfig{}, err
+	}
+	curve, err := cfg.rateCurve()
+	if err != nil {
+		return wininput.RuntimeConfig{}, err
+	}
+	return wininput.RuntimeConfig{
+		TriggerCode:        cfg.triggerCode,
+		ToggleCode:         cfg.toggleCode,
+		OutputCode:         cfg.outputCode,
+		CPS:                cfg.cps,
+		Timing:             timing,
+		Curve:              curve,
+		ClickDown:          time.Duration(math.Max(0, cfg.downMS) * float64(time.Millisecond)),
+		ClickDownModel:     clickDownModel,
+		JitterPixels:       cfg.jitter,
+		Pattern:            cfg.pattern,
+		StartEnabled:       cfg.startEnabled,
+		TriggerMode:        cfg.triggerMode,
+		LatchThreshold:     time.Duration(cfg.latchMS * float64(time.Millisecond)),
+		BurstCount:         cfg.burst,
+		BurstCooldown:      cfg.burstCooldown,
+		MaxCPSWindow:       cfg.maxCPSWindow,
+		CatchUp:            cfg.catchUp,
+		Ramp:               cfg.ramp(),
+		Ticks:              cfg.ticks(),
+		MaxHold:            cfg.maxHold,
+		HoldDelay:          time.Duration(cfg.holdDelayMS * float64(time.Millisecond)),
+		DebouncePress:      time.Duration(cfg.debouncePress * float64(time.Millisecond)),
+		DebounceRelease:    time.Duration(cfg.debounceRel * float64(time.Millisecond)),
+		ToggleGesture:      cfg.toggleGesture,
+		DoubleTapWindow:    cfg.doubleTap,
+		LongPressThreshold: cfg.longPress,
+		PanicCode:          cfg.panicCode,
+		PauseCodes:         cfg.pauseCodes,
+		PauseFor:           time.Duration(cfg.pauseMS * float64(time.Millisecond)),
+		PauseWhileTyping:   cfg.pauseTyping,
+		TypingPause:        time.Duration(cfg.typingPauseMS * float64(time.Millisecond)),
+		PanicAction:        cfg.panicAction,
+		OnPanic:            exitOnPanic,
+		Bindings:           cfg.coreBindings(),
+	}, nil
+}
+
 func startClickerFromConfig(cfg config, logger *slog.Logger) (clickerRuntime, error) {
 	if cfg.devicePath != "" {
 		logger.Warn("--device is ignored on Windows; using global keyboard/mouse hooks")
@@ -72,18 +136,11 @@ func startClickerFromConfig(cfg config, logger *slog.Logger) (clickerRuntime, er
 		logger.Warn("--grab is not supported on Windows and will be ignored")
 	}
 
-	clickDown := time.Duration(math.Max(0, cfg.downMS) * float64(time.Millisecond))
-	runtime, err := wininput.NewRuntime(
-		wininput.RuntimeConfig{
-			TriggerCode:  cfg.triggerCode,
-			ToggleCode:   cfg.toggleCode,
-			CPS:          cfg.cps,
-			ClickDown:    clickDown,
-			JitterPixels: cfg.jitter,
-			StartEnabled: cfg.startEnabled,
-		},
-		logger,
-	)
+	runtimeCfg, err := windowsRuntimeConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+	runtime, err := wininput.NewRuntime(runtimeCfg, logger)
 	if err != nil {
 		return nil, err
 	}
@@ -93,16 +150,53 @@ func startClickerFromConfig(cfg config, logger *slog.Logger) (clickerRuntime, er
 		return nil, err
 	}
 
-	logger.Info("Trigger", "name", formatCodeName(cfg.triggerCode), "code", cfg.triggerCode)
+	logger.Info("Trigger", "name", formatCodeName(cfg.triggerCode), "code", cfg.triggerCode, "mode", cfg.triggerMode)
 	logger.Info("Toggle", "name", formatCodeName(cfg.toggleCode), "code", cfg.toggleCode)
-	logger.Info("Rate", "cps", cfg.cps)
+	logger.Info("Output", "name", formatCodeName(cfg.outputCode), "code", cfg.outputCode)
+	if len(cfg.pattern) > 0 {
+		logger.Info("Pattern", "steps", formatPatternSpec(cfg.pattern))
+	}
+	logger.Info("Rate", "timing", runtimeCfg.Timing, "catch_up", cfg.catchUp)
+	if runtimeCfg.Curve != nil {
+		logger.Info("Rate curve", "curve", runtimeCfg.Curve)
+	}
+	logger.Info("Click down", "model", runtimeCfg.ClickDownModel)
 	logger.Info("Jitter", "pixels", cfg.jitter)
+	logger.Info("Ramp", "ramp", runtimeCfg.Ramp)
+	if runtimeCfg.Ticks.Rate > 0 {
+		logger.Info("Ticks", "ticks", runtimeCfg.Ticks)
+	}
+	if cfg.maxCPSWindow > 0 {
+		logger.Info("Click cap", "clicks_per_second", cfg.maxCPSWindow)
+	}
+	if cfg.maxHold > 0 {
+		logger.Info("Max hold", "duration", cfg.maxHold)
+	}
+	if cfg.holdDelayMS > 0 {
+		logger.Info("Hold delay", "ms", cfg.holdDelayMS)
+	}
+	if cfg.debouncePress > 0 || cfg.debounceRel > 0 {
+		logger.Info("Debounce", "press_ms", cfg.debouncePress, "release_ms", cfg.debounceRel)
+	}
+	if len(cfg.pauseCodes) > 0 || cfg.pauseTyping {
+		logger.Info("Pause", "keys", formatCodeNames(cfg.pauseCodes), "ms", cfg.pauseMS, "typing", cfg.pauseTyping)
+	}
+	if cfg.toggleGesture != autoclicker.ToggleSingle {
+		logger.Info("Toggle gesture", "gesture", cfg.toggleGesture, "double_tap", cfg.doubleTap, "long_press", cfg.longPress)
+	}
+	if cfg.panicCode != 0 {
+		logger.Info("Panic", "name", formatCodeName(cfg.panicCode), "code", cfg.panicCode, "action", cfg.panicAction)
+	}
+	if cfg.burst > 0 {
+		logger.Info("Burst", "clicks", cfg.burst, "cooldown", cfg.burstCooldown)
+	}
+	logBindings(logger, cfg.bindings)
 	logger.Info("Input mode", "mode", "windows-global-hooks")
 	if cfg.startEnabled {
 		logger.Info("Initial state enabled (press toggle to disable/enable)")
 	} else {
 		logger.Info("Initial state disabled (press toggle to enable/disable)")
 	}
-	logger.Info("Hold trigger to autoclick left mouse button. Press Ctrl+C to stop")
+	logger.Info("Hold trigger to autoclick output. Press Ctrl+C to stop")
 	return runtime, nil
 }
@@ -0,0 +1,186 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"clicker/internal/core/autoclicker"
+)
+
+func loadRhythm(path string) (autoclicker.Rhythm, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return autoclicker.Rhythm{}, fmt.Errorf("failed to open rhythm profile: %w", err)
+	}
+	defer file.Close()
+
+	var rhythm autoclicker.Rhythm
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".csv":
+		rhythm, err = autoclicker.ReadRhythmCSV(file)
+	case ".json":
+		rhythm, err = autoclicker.ReadRhythmJSON(file)
+	default:
+		return autoclicker.Rhythm{}, fmt.Errorf("rhythm profile %s must end in .csv or .json", path)
+	}
+	if err != nil {
+		return autoclicker.Rhythm{}, fmt.Errorf("%s: %w", path, err)
+	}
+	return rhythm, nil
+}
+
+func saveRhythm(path string, rhythm autoclicker.Rhythm) error {
+	var write func(io.Writer) error
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".csv":
+		write = rhythm.WriteCSV
+	case ".json":
+		write = rhythm.WriteJSON
+	default:
+		return fmt.Errorf("rhythm profile %s must end in .csv or .json", path)
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create rhythm profile: %w", err)
+	}
+	if err := write(file); err != nil {
+		_ = file.Close()
+		return fmt.Errorf("failed to write rhythm profile: %w", err)
+	}
+	return file.Close()
+}
+
+func runRecordRhythm(args []string, stderr io.Writer) int {
+	flags := flag.NewFlagSet("clicker record-rhythm", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+
+	var outPath string
+	var importPath string
+	var triggerRaw string
+	var backendRaw string
+	var devicePath string
+	var clicks int
+	var maxGap time.Duration
+
+	flags.StringVar(&outPath, "out", "", "Profile file to write; .csv or .json picks the format.")
+	flags.StringVar(&importPath, "import", "", "Convert this existing .csv or .json profile to --out instead of recording.")
+	flags.StringVar(&triggerRaw, "trigger", "BTN_LEFT", "Key/button to record (default: BTN_LEFT).")
+	flags.StringVar(&backendRaw, "backend", "auto", "Input backend. Linux: auto|wayland|x11. Windows: auto|windows.")
+	flags.StringVar(&devicePath, "device", "", "Input event device path to listen on, e.g. /dev/input/event4. All devices if omitted.")
+	flags.IntVar(&clicks, "clicks", 0, "Stop after recording this many clicks (0 records until interrupted).")
+	flags.DurationVar(&maxGap, "max-gap", autoclicker.DefaultRhythmMaxGap, "Longer pauses between clicks are left out of the rhythm (default: 1s).")
+
+	if err := flags.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		return 2
+	}
+	if flags.NArg() > 0 {
+		fmt.Fprintf(stderr, "unexpected arguments: %s\n", strings.Join(flags.Args(), " "))
+		return 2
+	}
+	if outPath == "" {
+		fmt.Fprintln(stderr, "--out is required")
+		return 2
+	}
+	if clicks < 0 {
+		fmt.Fprintln(stderr, "--clicks must be >= 0")
+		return 2
+	}
+	if maxGap <= 0 {
+		fmt.Fprintln(stderr, "--max-gap must be > 0")
+		return 2
+	}
+
+	if importPath != "" {
+		rhythm, err := loadRhythm(importPath)
+		if err == nil {
+			err = saveRhythm(outPath, rhythm)
+		}
+		if err != nil {
+			fmt.Fprintln(stderr, err)
+			return 1
+		}
+		fmt.Fprintf(stderr, "Wrote %d clicks to %s\n", len(rhythm.Samples), outPath)
+		return 0
+	}
+
+	triggerCode, err := parseTriggerCode(triggerRaw)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 2
+	}
+	backend, err := parseBackendChoice(backendRaw)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 2
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	var mu sync.Mutex
+	recorder := autoclicker.RhythmRecorder{MaxGap: maxGap}
+	stop := make(chan struct{})
+	watchErr := make(chan error, 1)
+	go func() {
+		watchErr <- watchKey(backend, devicePath, triggerCode, stop, func(down bool, at time.Time) {
+			mu.Lock()
+			defer mu.Unlock()
+			if down {
+				recorder.Press(at)
+			} else {
+				recorder.Release(at)
+			}
+			if clicks > 0 && recorder.Len() >= clicks {
+				cancel()
+			}
+		})
+	}()
+
+	if clicks > 0 {
+		fmt.Fprintf(stderr, "Recording %d clicks of %s...\n", clicks, formatCodeName(triggerCode))
+	} else {
+		fmt.Fprintf(stderr, "Recording clicks of %s, press Ctrl+C to stop...\n", formatCodeName(triggerCode))
+	}
+	select {
+	case <-ctx.Done():
+		close(stop)
+		err = <-watchErr
+	case err = <-watchErr:
+	}
+	if err != nil {
+		if isPermissionError(err) {
+			fmt.Fprintln(stderr, permissionDeniedHint())
+			return 1
+		}
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+
+	mu.Lock()
+	rhythm := recorder.Rhythm()
+	mu.Unlock()
+	if len(rhythm.Samples) == 0 {
+		fmt.Fprintln(stderr, "no clicks recorded")
+		return 1
+	}
+	if err := saveRhythm(outPath, rhythm); err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+	fmt.Fprintf(stderr, "Wrote %d clicks to %s\n", len(rhythm.Samples), outPath)
+	return 0
+}
@@ -8,12 +8,46 @@ import (
 )
 
 type uiSettings struct {
-	MinCPS  float64 `json:"min_cps"`
-	MaxCPS  float64 `json:"max_cps"`
-	Jitter  int     `json:"jitter"`
+	MinCPS        float64     `json:"min_cps"`
+	MaxCPS        float64     `json:"max_cps"`
+	Jitter        int         `json:"jitter"`
+	MinDownMS     float64     `json:"min_down_ms"`
+	MaxDownMS     float64     `json:"max_down_ms"`
+	Trigger       string      `json:"trigger"`
+	Toggle        string      `json:"toggle"`
+	Output        string      `json:"output"`
+	Pattern       string      `json:"pattern,omitempty"`
+	Panic         string      `json:"panic,omitempty"`
+	PanicAction   string      `json:"panic_action,omitempty"`
+	Pause         []string    `json:"pause,omitempty"`
+	PauseMS       float64     `json:"pause_ms"`
+	PauseTyping   *bool       `json:"pause_typing,omitempty"`
+	ToggleGesture string      `json:"toggle_gesture,omitempty"`
+	TriggerMode   string      `json:"trigger_mode,omitempty"`
+	Timing        string      `json:"timing,omitempty"`
+	Curve         string      `json:"curve,omitempty"`
+	CurveAmp      float64     `json:"curve_amplitude,omitempty"`
+	CurvePeriod   float64     `json:"curve_period_ms,omitempty"`
+	CurveFile     string      `json:"curve_file,omitempty"`
+	Burst         int         `json:"burst"`
+	BurstCoolMS   float64     `json:"burst_cooldown_ms"`
+	MaxCPSWindow  int         `json:"max_cps_window"`
+	RampUpMS      float64     `json:"ramp_up_ms"`
+	RampDownMS    float64     `json:"ramp_down_ms"`
+	MaxHoldMS     float64     `json:"max_hold_ms"`
+	HoldDelayMS   float64     `json:"hold_delay_ms"`
+	DebounceDown  float64     `json:"debounce_press_ms"`
+	DebounceUp    float64     `json:"debounce_release_ms"`
+	Enabled       bool        `json:"enabled"`
+	Bindings      []uiBinding `json:"bindings"`
+}
+
+type uiBinding struct {
 	Trigger string  `json:"trigger"`
-	Toggle  string  `json:"toggle"`
-	Enabled bool    `json:"enabled"`
+	Output  string  `json:"output"`
+	CPS     float64 `json:"cps"`
+	DownMS  float64 `json:"down_ms"`
+	Jitter  int     `json:"jitter"`
 }
 
 func uiSettingsPath() (string, error) {
@@ -0,0 +1,176 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"strings"
+	"time"
+
+	"clicker/internal/core/autoclicker"
+)
+
+const (
+	timingFixed     = "fixed"
+	timingUniform   = "uniform"
+	timingGaussian  = "gaussian"
+	timingLogNormal = "lognormal"
+)
+
+var timingKinds = []string{timingFixed, timingUniform, timingGaussian, timingLogNormal}
+
+const (
+	curveNone     = "none"
+	curveWalk     = "walk"
+	curveSine     = "sine"
+	curveSchedule = "schedule"
+)
+
+var curveKinds = []string{curveNone, curveWalk, curveSine, curveSchedule}
+
+func parseTimingKind(raw string) (string, error) {
+	value := strings.ToLower(strings.TrimSpace(raw))
+	switch value {
+	case "", timingFixed:
+		return timingFixed, nil
+	case timingUniform, timingGaussian:
+		return value, nil
+	case timingLogNormal, "log-normal":
+		return timingLogNormal, nil
+	default:
+		return "", fmt.Errorf("invalid --timing %q (expected %s)", raw, strings.Join(timingKinds, "|"))
+	}
+}
+
+func parseCurveKind(raw string) (string, error) {
+	value := strings.ToLower(strings.TrimSpace(raw))
+	switch value {
+	case "", curveNone, "off":
+		return curveNone, nil
+	case curveWalk, "random-walk":
+		return curveWalk, nil
+	case curveSine, curveSchedule:
+		return value, nil
+	default:
+		return "", fmt.Errorf("invalid --curve %q (expected %s)", raw, strings.Join(curveKinds, "|"))
+	}
+}
+
+func (cfg config) rateCurve() (autoclicker.RateCurve, error) {
+	switch cfg.curve {
+	case curveWalk:
+		return autoclicker.RandomWalkCurve(cfg.curveAmp, cfg.curvePeriod)
+	case curveSine:
+		return autoclicker.SineCurve(cfg.curveAmp, cfg.curvePeriod)
+	case curveSchedule:
+		if cfg.curveFile == "" {
+			return nil, fmt.Errorf("--curve schedule needs --curve-file")
+		}
+		file, err := os.Open(cfg.curveFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open curve schedule: %w", err)
+		}
+		defer file.Close()
+		curve, err := autoclicker.ParseRateSchedule(file)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", cfg.curveFile, err)
+		}
+		return curve, nil
+	default:
+		return nil, nil
+	}
+}
+
+func (cfg config) timingModel() (autoclicker.TimingModel, error) {
+	if cfg.ui {
+		return rangeTimingModel(cfg.timing, cfg.minCPS, cfg.maxCPS)
+	}
+	stddev := time.Duration(cfg.stddevMS * float64(time.Millisecond))
+	switch cfg.timing {
+	case timingUniform:
+		return autoclicker.UniformTiming(cfg.minCPS, cfg.maxCPS)
+	case timingGaussian:
+		return autoclicker.GaussianTiming(cfg.cps, stddev)
+	case timingLogNormal:
+		return autoclicker.LogNormalTiming(cfg.cps, stddev)
+	default:
+		return autoclicker.FixedTiming(cfg.cps)
+	}
+}
+
+func (cfg config) clickModels() (autoclicker.TimingModel, autoclicker.ClickDownModel, error) {
+	if cfg.rhythmFile != "" {
+		rhythm, err := loadRhythm(cfg.rhythmFile)
+		if err != nil {
+			return nil, nil, err
+		}
+		return autoclicker.RhythmModels(rhythm, cfg.rhythmPlay)
+	}
+	timing, err := cfg.timingModel()
+	if err != nil {
+		return nil, nil, err
+	}
+	down, err := cfg.clickDownModel()
+	if err != nil {
+		return nil, nil, err
+	}
+	return timing, down, nil
+}
+
+func parseDownModelKind(raw string) (string, error) {
+	kind, err := parseTimingKind(raw)
+	if err != nil {
+		return "", fmt.Errorf("invalid --down-model %q (expected %s)", raw, strings.Join(timingKinds, "|"))
+	}
+	return kind, nil
+}
+
+func (cfg config) clickDownModel() (autoclicker.ClickDownModel, error) {
+	down := time.Duration(math.Max(0, cfg.downMS) * float64(time.Millisecond))
+	downMax := time.Duration(math.Max(0, cfg.downMaxMS) * float64(time.Millisecond))
+	stddev := time.Duration(cfg.downStddevMS * float64(time.Millisecond))
+	switch cfg.downModel {
+	case timingUniform:
+		return autoclicker.UniformClickDown(down, downMax)
+	case timingGaussian:
+		return autoclicker.GaussianClickDown(down, stddev)
+	case timingLogNormal:
+		return autoclicker.LogNormalClickDown(down, stddev)
+	default:
+		return autoclicker.FixedClickDown(down)
+	}
+}
+
+func rangeTimingModel(kind string, minCPS, maxCPS float64) (autoclicker.TimingModel, error) {
+	if maxCPS < minCPS {
+		minCPS, maxCPS = maxCPS, minCPS
+	}
+	mid := (minCPS + maxCPS) / 2
+	stddev := (autoclicker.CPSInterval(minCPS) - autoclicker.CPSInterval(maxCPS)) / 4
+	switch kind {
+	case timingFixed:
+		return autoclicker.FixedTiming(mid)
+	case timingGaussian:
+		return autoclicker.GaussianTiming(mid, stddev)
+	case timingLogNormal:
+		return autoclicker.LogNormalTiming(mid, stddev)
+	default:
+		return autoclicker.UniformTiming(minCPS, maxCPS)
+	}
+}
+
+func (cfg config) ramp() autoclicker.Ramp {
+	return autoclicker.Ramp{
+		Up:    time.Duration(math.Max(0, cfg.rampMS) * float64(time.Millisecond)),
+		Down:  time.Duration(math.Max(0, cfg.rampDownMS) * float64(time.Millisecond)),
+		Start: cfg.rampStart,
+	}
+}
+
+func (cfg config) ticks() autoclicker.Ticks {
+	return autoclicker.Ticks{
+		Rate:    cfg.tickRate,
+		Phase:   time.Duration(cfg.tickPhaseMS * float64(time.Millisecond)),
+		PerTick: cfg.perTick,
+	}
+}
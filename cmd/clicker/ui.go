@@ -6,9 +6,10 @@ import (
 	"fmt"
 	"image/color"
 	"math"
-	"math/rand"
 	"os"
 	"os/signal"
+	"path/filepath"
+	"slices"
 	"strings"
 	"sync"
 	"syscall"
@@ -20,15 +21,31 @@ import (
 	"fyne.io/fyne/v2/container"
 	"fyne.io/fyne/v2/theme"
 	"fyne.io/fyne/v2/widget"
+
+	"clicker/internal/core/autoclicker"
 )
 
 type clickerRuntime interface {
 	SetEnabled(enabled bool)
 	IsEnabled() bool
+	Stats() autoclicker.Stats
+	Subscribe(buffer int) (<-chan autoclicker.StateEvent, func())
 	SetCPS(cps float64) error
+	SetTimingModel(model autoclicker.TimingModel) error
+	SetRateCurve(curve autoclicker.RateCurve)
+	SetClickDownModel(model autoclicker.ClickDownModel) error
 	SetJitter(pixels int) error
 	SetTriggerCode(code uint16)
 	SetToggleCode(code uint16)
+	SetOutputCode(code uint16) error
+	SetTriggerMode(mode autoclicker.TriggerMode) error
+	SetBurst(count int, cooldown time.Duration) error
+	SetRamp(ramp autoclicker.Ramp) error
+	SetMaxHold(d time.Duration) error
+	SetHoldDelay(d time.Duration) error
+	SetMaxCPSWindow(limit int) error
+	SetDebounce(press, release time.Duration) error
+	SetBindings(bindings []autoclicker.Binding) error
 	CaptureNextKeyCode(timeout time.Duration) (uint16, error)
 	Stop()
 }
@@ -121,11 +138,41 @@ func normalizeCodeName(raw string, fallback string) string {
 	return formatCodeName(code)
 }
 
+func displayCodeNames(codes []uint16) string {
+	if len(codes) == 0 {
+		return "-"
+	}
+	names := make([]string, 0, len(codes))
+	for _, code := range codes {
+		names = append(names, displayCodeName(formatCodeName(code)))
+	}
+	return strings.Join(names, ", ")
+}
+
+func settingsFromCodes(codes []uint16) []string {
+	names := make([]string, 0, len(codes))
+	for _, code := range codes {
+		names = append(names, formatCodeName(code))
+	}
+	return names
+}
+
 func displayCodeName(raw string) string {
 	name := strings.ToUpper(strings.TrimSpace(raw))
 	if name == "" {
 		return "-"
 	}
+	if i := strings.LastIndex(name, "+"); i > 0 {
+		words := make([]string, 0, 4)
+		for _, mod := range strings.Split(name[:i], "+") {
+			words = append(words, humanizeInputToken(mod))
+		}
+		key := strings.TrimSpace(name[i+1:])
+		if strings.HasPrefix(key, "KEY_") {
+			return strings.Join(append(words, humanizeInputToken(strings.TrimPrefix(key, "KEY_"))), "+")
+		}
+		return strings.Join(append(words, displayCodeName(key)), "+")
+	}
 
 	switch name {
 	case "BTN_LEFT":
@@ -240,12 +287,33 @@ func isDigits(value string) bool {
 	return true
 }
 
+func formatStats(stats autoclicker.Stats) string {
+	text := fmt.Sprintf(
+		"Achieved: %.2f CPS · %d clicks (%d this hold) · %d holds · %s clicking",
+		stats.WindowCPS,
+		stats.Clicks,
+		stats.ClicksInHold,
+		stats.Holds,
+		stats.ClickingTime.Round(100*time.Millisecond),
+	)
+	if stats.InjectorErrors > 0 {
+		text += fmt.Sprintf(" · %d errors", stats.InjectorErrors)
+	}
+	if stats.Bounces > 0 {
+		text += fmt.Sprintf(" · %d bounces filtered", stats.Bounces)
+	}
+	if stats.DeferredClicks > 0 {
+		text += fmt.Sprintf(" · %d clicks deferred by cap", stats.DeferredClicks)
+	}
+	return text
+}
+
 func runUI(baseCfg config) error {
 	fApp := app.New()
 	fApp.Settings().SetTheme(newClickerTheme())
 
 	window := fApp.NewWindow("Auto-Clicker")
-	window.Resize(fyne.NewSize(760, 470))
+	window.Resize(fyne.NewSize(760, 640))
 	window.SetFixedSize(true)
 	window.CenterOnScreen()
 
@@ -265,6 +333,17 @@ func runUI(baseCfg config) error {
 	minDefault := math.Max(1, baseCfg.cps-4)
 	maxDefault := math.Max(minDefault, baseCfg.cps)
 	jitterDefault := clamp(float64(baseCfg.jitter), 0, 12)
+	minDownDefault := clamp(baseCfg.downMS, 0, 80)
+	maxDownDefault := clamp(math.Max(baseCfg.downMS, baseCfg.downMaxMS), minDownDefault, 80)
+	burstDefault := clamp(float64(baseCfg.burst), 0, 20)
+	burstCooldownDefault := clamp(float64(baseCfg.burstCooldown.Milliseconds()), 0, 2000)
+	rampUpDefault := clamp(baseCfg.rampMS, 0, 2000)
+	rampDownDefault := clamp(baseCfg.rampDownMS, 0, 2000)
+	maxHoldDefault := clamp(baseCfg.maxHold.Seconds(), 0, 120)
+	cpsCapDefault := clamp(float64(baseCfg.maxCPSWindow), 0, 50)
+	holdDelayDefault := clamp(baseCfg.holdDelayMS, 0, 500)
+	debounceDownDefault := clamp(baseCfg.debouncePress, 0, 50)
+	debounceUpDefault := clamp(baseCfg.debounceRel, 0, 50)
 
 	triggerRaw := strings.TrimSpace(baseCfg.triggerRaw)
 	if triggerRaw == "" {
@@ -274,6 +353,29 @@ func runUI(baseCfg config) error {
 	if toggleRaw == "" {
 		toggleRaw = "BTN_EXTRA"
 	}
+	outputRaw := strings.TrimSpace(baseCfg.outputRaw)
+	if outputRaw == "" {
+		outputRaw = "BTN_LEFT"
+	}
+	panicRaw := strings.TrimSpace(baseCfg.panicRaw)
+	panicAction := baseCfg.panicAction
+	pattern := baseCfg.pattern
+	pauseCodes := baseCfg.pauseCodes
+	pauseMS := baseCfg.pauseMS
+	pauseTyping := baseCfg.pauseTyping
+	toggleGesture := baseCfg.toggleGesture
+	bindings := baseCfg.bindings
+	triggerMode := baseCfg.triggerMode
+	// The UI has always varied the rate across the min/max range.
+	timingKind := timingUniform
+	if baseCfg.timing != "" && baseCfg.timing != timingFixed {
+		timingKind = baseCfg.timing
+	}
+
+	curveKind := baseCfg.curve
+	curveAmp := baseCfg.curveAmp
+	curvePeriod := baseCfg.curvePeriod
+	curveFile := baseCfg.curveFile
 
 	stored, err := loadUISettings()
 	if err != nil {
@@ -305,10 +407,146 @@ func runUI(baseCfg config) error {
 				settingsLoadWarning = fmt.Sprintf("Saved toggle is invalid (%s); using default.", value)
 			}
 		}
+		if value := strings.TrimSpace(stored.Output); value != "" {
+			if _, parseErr := parseTriggerCode(value); parseErr == nil {
+				outputRaw = value
+			} else if settingsLoadWarning == "" {
+				settingsLoadWarning = fmt.Sprintf("Saved output is invalid (%s); using default.", value)
+			}
+		}
+		if value := strings.TrimSpace(stored.Pattern); value != "" {
+			if parsed, parseErr := parsePatternSpec(value); parseErr == nil {
+				pattern = parsed
+			} else if settingsLoadWarning == "" {
+				settingsLoadWarning = fmt.Sprintf("Saved pattern is invalid (%v); using default.", parseErr)
+			}
+		}
+		if value := strings.TrimSpace(stored.Panic); value != "" {
+			if _, parseErr := parseTriggerCode(value); parseErr == nil {
+				panicRaw = value
+			} else if settingsLoadWarning == "" {
+				settingsLoadWarning = fmt.Sprintf("Saved panic key is invalid (%s); using default.", value)
+			}
+		}
+		if len(stored.Pause) > 0 {
+			codes := make([]uint16, 0, len(stored.Pause))
+			for _, value := range stored.Pause {
+				code, parseErr := parseTriggerCode(strings.TrimSpace(value))
+				if parseErr != nil {
+					if settingsLoadWarning == "" {
+						settingsLoadWarning = fmt.Sprintf("Saved pause key is invalid (%s); using default.", value)
+					}
+					codes = pauseCodes
+					break
+				}
+				codes = append(codes, code)
+			}
+			pauseCodes = codes
+		}
+		if stored.PauseMS > 0 {
+			pauseMS = stored.PauseMS
+		}
+		if stored.PauseTyping != nil {
+			pauseTyping = *stored.PauseTyping
+		}
+		if value := strings.TrimSpace(stored.PanicAction); value != "" {
+			if action, parseErr := autoclicker.ParsePanicAction(value); parseErr == nil {
+				panicAction = action
+			} else if settingsLoadWarning == "" {
+				settingsLoadWarning = fmt.Sprintf("Saved panic action is invalid (%s); using default.", value)
+			}
+		}
+		if value := strings.TrimSpace(stored.ToggleGesture); value != "" {
+			if gesture, parseErr := autoclicker.ParseToggleGesture(value); parseErr == nil {
+				toggleGesture = gesture
+			} else if settingsLoadWarning == "" {
+				settingsLoadWarning = fmt.Sprintf("Saved toggle gesture is invalid (%s); using default.", value)
+			}
+		}
+		if stored.MaxDownMS > 0 {
+			maxDownDefault = clamp(stored.MaxDownMS, 0, 80)
+			minDownDefault = clamp(stored.MinDownMS, 0, maxDownDefault)
+		}
+		if stored.Burst > 0 {
+			burstDefault = clamp(float64(stored.Burst), 0, 20)
+		}
+		if stored.BurstCoolMS > 0 {
+			burstCooldownDefault = clamp(stored.BurstCoolMS, 0, 2000)
+		}
+		if stored.RampUpMS > 0 {
+			rampUpDefault = clamp(stored.RampUpMS, 0, 2000)
+		}
+		if stored.RampDownMS > 0 {
+			rampDownDefault = clamp(stored.RampDownMS, 0, 2000)
+		}
+		if stored.MaxHoldMS > 0 {
+			maxHoldDefault = clamp(stored.MaxHoldMS/1000, 0, 120)
+		}
+		if stored.HoldDelayMS > 0 {
+			holdDelayDefault = clamp(stored.HoldDelayMS, 0, 500)
+		}
+		if stored.MaxCPSWindow > 0 {
+			cpsCapDefault = clamp(float64(stored.MaxCPSWindow), 0, 50)
+		}
+		if stored.DebounceDown > 0 {
+			debounceDownDefault = clamp(stored.DebounceDown, 0, 50)
+		}
+		if stored.DebounceUp > 0 {
+			debounceUpDefault = clamp(stored.DebounceUp, 0, 50)
+		}
+		if value := strings.TrimSpace(stored.TriggerMode); value != "" {
+			if mode, parseErr := autoclicker.ParseTriggerMode(value); parseErr == nil {
+				triggerMode = mode
+			} else if settingsLoadWarning == "" {
+				settingsLoadWarning = fmt.Sprintf("Saved trigger mode is invalid (%s); using default.", value)
+			}
+		}
+		if value := strings.TrimSpace(stored.Timing); value != "" {
+			if kind, parseErr := parseTimingKind(value); parseErr == nil {
+				timingKind = kind
+			} else if settingsLoadWarning == "" {
+				settingsLoadWarning = fmt.Sprintf("Saved timing is invalid (%s); using default.", value)
+			}
+		}
+		if value := strings.TrimSpace(stored.Curve); value != "" {
+			if kind, parseErr := parseCurveKind(value); parseErr == nil {
+				curveKind = kind
+			} else if settingsLoadWarning == "" {
+				settingsLoadWarning = fmt.Sprintf("Saved curve is invalid (%s); using default.", value)
+			}
+		}
+		if stored.CurveAmp > 0 && stored.CurveAmp < 1 {
+			curveAmp = stored.CurveAmp
+		}
+		if stored.CurvePeriod > 0 {
+			curvePeriod = time.Duration(stored.CurvePeriod * float64(time.Millisecond))
+		}
+		if value := strings.TrimSpace(stored.CurveFile); value != "" {
+			curveFile = value
+		}
+		if stored.Bindings != nil {
+			defaults := bindingConfig{cps: baseCfg.cps, downMS: baseCfg.downMS, jitter: baseCfg.jitter}
+			if loaded, parseErr := bindingsFromSettings(stored.Bindings, defaults); parseErr == nil {
+				bindings = loaded
+			} else if settingsLoadWarning == "" {
+				settingsLoadWarning = fmt.Sprintf("Saved bindings are invalid (%v); using defaults.", parseErr)
+			}
+		}
 		startupEnabled = stored.Enabled
 	}
+	curveCheck := config{curve: curveKind, curveAmp: curveAmp, curvePeriod: curvePeriod, curveFile: curveFile}
+	if _, curveErr := curveCheck.rateCurve(); curveErr != nil {
+		if settingsLoadWarning == "" {
+			settingsLoadWarning = fmt.Sprintf("Saved curve is unusable (%v); using none.", curveErr)
+		}
+		curveKind = curveNone
+	}
 	triggerRaw = normalizeCodeName(triggerRaw, "BTN_LEFT")
 	toggleRaw = normalizeCodeName(toggleRaw, "BTN_EXTRA")
+	outputRaw = normalizeCodeName(outputRaw, "BTN_LEFT")
+	if panicRaw != "" {
+		panicRaw = normalizeCodeName(panicRaw, "")
+	}
 
 	minSlider := widget.NewSlider(1, 30)
 	minSlider.Step = 0
@@ -322,29 +560,157 @@ func runUI(baseCfg config) error {
 	jitterSlider.Step = 0
 	jitterSlider.SetValue(jitterDefault)
 
+	minDownSlider := widget.NewSlider(0, 80)
+	minDownSlider.Step = 1
+	minDownSlider.SetValue(minDownDefault)
+
+	maxDownSlider := widget.NewSlider(0, 80)
+	maxDownSlider.Step = 1
+	maxDownSlider.SetValue(maxDownDefault)
+
+	burstSlider := widget.NewSlider(0, 20)
+	burstSlider.Step = 1
+	burstSlider.SetValue(burstDefault)
+
+	burstCooldownSlider := widget.NewSlider(0, 2000)
+	burstCooldownSlider.Step = 50
+	burstCooldownSlider.SetValue(burstCooldownDefault)
+
+	rampUpSlider := widget.NewSlider(0, 2000)
+	rampUpSlider.Step = 50
+	rampUpSlider.SetValue(rampUpDefault)
+
+	rampDownSlider := widget.NewSlider(0, 2000)
+	rampDownSlider.Step = 50
+	rampDownSlider.SetValue(rampDownDefault)
+
+	maxHoldSlider := widget.NewSlider(0, 120)
+	maxHoldSlider.Step = 5
+	maxHoldSlider.SetValue(maxHoldDefault)
+
+	cpsCapSlider := widget.NewSlider(0, 50)
+	cpsCapSlider.Step = 1
+	cpsCapSlider.SetValue(cpsCapDefault)
+
+	holdDelaySlider := widget.NewSlider(0, 500)
+	holdDelaySlider.Step = 10
+	holdDelaySlider.SetValue(holdDelayDefault)
+
+	debounceDownSlider := widget.NewSlider(0, 50)
+	debounceDownSlider.Step = 1
+	debounceDownSlider.SetValue(debounceDownDefault)
+
+	debounceUpSlider := widget.NewSlider(0, 50)
+	debounceUpSlider.Step = 1
+	debounceUpSlider.SetValue(debounceUpDefault)
+
 	minValue := widget.NewLabel("")
 	maxValue := widget.NewLabel("")
 	jitterValue := widget.NewLabel("")
+	minDownValue := widget.NewLabel("")
+	maxDownValue := widget.NewLabel("")
+	burstValue := widget.NewLabel("")
+	burstCooldownValue := widget.NewLabel("")
+	rampUpValue := widget.NewLabel("")
+	rampDownValue := widget.NewLabel("")
+	maxHoldValue := widget.NewLabel("")
+	holdDelayValue := widget.NewLabel("")
+	cpsCapValue := widget.NewLabel("")
+	debounceDownValue := widget.NewLabel("")
+	debounceUpValue := widget.NewLabel("")
 	minValue.Alignment = fyne.TextAlignTrailing
 	maxValue.Alignment = fyne.TextAlignTrailing
 	jitterValue.Alignment = fyne.TextAlignTrailing
+	minDownValue.Alignment = fyne.TextAlignTrailing
+	maxDownValue.Alignment = fyne.TextAlignTrailing
+	burstValue.Alignment = fyne.TextAlignTrailing
+	burstCooldownValue.Alignment = fyne.TextAlignTrailing
+	rampUpValue.Alignment = fyne.TextAlignTrailing
+	rampDownValue.Alignment = fyne.TextAlignTrailing
+	maxHoldValue.Alignment = fyne.TextAlignTrailing
+	holdDelayValue.Alignment = fyne.TextAlignTrailing
+	cpsCapValue.Alignment = fyne.TextAlignTrailing
+	debounceDownValue.Alignment = fyne.TextAlignTrailing
+	debounceUpValue.Alignment = fyne.TextAlignTrailing
 	minValue.TextStyle = fyne.TextStyle{Bold: true}
 	maxValue.TextStyle = fyne.TextStyle{Bold: true}
 	jitterValue.TextStyle = fyne.TextStyle{Bold: true}
+	minDownValue.TextStyle = fyne.TextStyle{Bold: true}
+	maxDownValue.TextStyle = fyne.TextStyle{Bold: true}
+	burstValue.TextStyle = fyne.TextStyle{Bold: true}
+	burstCooldownValue.TextStyle = fyne.TextStyle{Bold: true}
+	rampUpValue.TextStyle = fyne.TextStyle{Bold: true}
+	rampDownValue.TextStyle = fyne.TextStyle{Bold: true}
+	maxHoldValue.TextStyle = fyne.TextStyle{Bold: true}
+	holdDelayValue.TextStyle = fyne.TextStyle{Bold: true}
+	cpsCapValue.TextStyle = fyne.TextStyle{Bold: true}
+	debounceDownValue.TextStyle = fyne.TextStyle{Bold: true}
+	debounceUpValue.TextStyle = fyne.TextStyle{Bold: true}
 	updateControlText := func() {
 		minValue.SetText(fmt.Sprintf("%.2f", minSlider.Value))
 		maxValue.SetText(fmt.Sprintf("%.2f", maxSlider.Value))
 		jitterValue.SetText(fmt.Sprintf("%.2f px", jitterSlider.Value))
+		minDownValue.SetText(fmt.Sprintf("%.0f ms", minDownSlider.Value))
+		maxDownValue.SetText(fmt.Sprintf("%.0f ms", maxDownSlider.Value))
+		if burstSlider.Value < 1 {
+			burstValue.SetText("off")
+		} else {
+			burstValue.SetText(fmt.Sprintf("%.0f clicks", burstSlider.Value))
+		}
+		burstCooldownValue.SetText(fmt.Sprintf("%.0f ms", burstCooldownSlider.Value))
+		if rampUpSlider.Value < 1 {
+			rampUpValue.SetText("off")
+		} else {
+			rampUpValue.SetText(fmt.Sprintf("%.0f ms", rampUpSlider.Value))
+		}
+		if rampDownSlider.Value < 1 {
+			rampDownValue.SetText("off")
+		} else {
+			rampDownValue.SetText(fmt.Sprintf("%.0f ms", rampDownSlider.Value))
+		}
+		if maxHoldSlider.Value < 1 {
+			maxHoldValue.SetText("off")
+		} else {
+			maxHoldValue.SetText(fmt.Sprintf("%.0f s", maxHoldSlider.Value))
+		}
+		if holdDelaySlider.Value < 1 {
+			holdDelayValue.SetText("off")
+		} else {
+			holdDelayValue.SetText(fmt.Sprintf("%.0f ms", holdDelaySlider.Value))
+		}
+		if cpsCapSlider.Value < 1 {
+			cpsCapValue.SetText("off")
+		} else {
+			cpsCapValue.SetText(fmt.Sprintf("%.0f CPS", cpsCapSlider.Value))
+		}
+		if debounceDownSlider.Value < 1 {
+			debounceDownValue.SetText("off")
+		} else {
+			debounceDownValue.SetText(fmt.Sprintf("%.0f ms", debounceDownSlider.Value))
+		}
+		if debounceUpSlider.Value < 1 {
+			debounceUpValue.SetText("off")
+		} else {
+			debounceUpValue.SetText(fmt.Sprintf("%.0f ms", debounceUpSlider.Value))
+		}
 	}
 	updateControlText()
 
 	persistUISettings := func() {}
+	applyTimingModel := func() {}
+
+	timingSelect := widget.NewSelect(timingKinds, nil)
+	timingSelect.SetSelected(timingKind)
+
+	curveSelect := widget.NewSelect(curveKinds, nil)
+	curveSelect.SetSelected(curveKind)
 
 	minSlider.OnChanged = func(v float64) {
 		if v > maxSlider.Value {
 			maxSlider.SetValue(v)
 		}
 		updateControlText()
+		applyTimingModel()
 		persistUISettings()
 	}
 	maxSlider.OnChanged = func(v float64) {
@@ -352,19 +718,52 @@ func runUI(baseCfg config) error {
 			minSlider.SetValue(v)
 		}
 		updateControlText()
+		applyTimingModel()
 		persistUISettings()
 	}
 
 	triggerCaptureBtn := widget.NewButton(displayCodeName(triggerRaw), nil)
 	toggleCaptureBtn := widget.NewButton(displayCodeName(toggleRaw), nil)
+	outputCaptureBtn := widget.NewButton(displayCodeName(outputRaw), nil)
+	panicCaptureBtn := widget.NewButton(displayCodeName(panicRaw), nil)
+	panicClearBtn := widget.NewButtonWithIcon("", theme.ContentClearIcon(), nil)
+	patternEntry := widget.NewEntry()
+	patternEntry.SetPlaceHolder("Output only, e.g. BTN_LEFT,BTN_RIGHT")
+	patternEntry.SetText(formatPatternSpec(pattern))
+	pauseCaptureBtn := widget.NewButton(displayCodeNames(pauseCodes), nil)
+	pauseClearBtn := widget.NewButtonWithIcon("", theme.ContentClearIcon(), nil)
+	pauseTypingCheck := widget.NewCheck("Pause while typing", nil)
+	pauseTypingCheck.SetChecked(pauseTyping)
+	panicActionSelect := widget.NewSelect([]string{
+		autoclicker.PanicDisable.String(),
+		autoclicker.PanicUngrab.String(),
+		autoclicker.PanicExit.String(),
+	}, nil)
+	panicActionSelect.SetSelected(panicAction.String())
+	toggleGestureSelect := widget.NewSelect([]string{
+		autoclicker.ToggleSingle.String(),
+		autoclicker.ToggleDoubleTap.String(),
+		autoclicker.ToggleLongPress.String(),
+	}, nil)
+	toggleGestureSelect.SetSelected(toggleGesture.String())
+	addBindingBtn := widget.NewButton("Add binding", nil)
+	triggerModeSelect := widget.NewSelect([]string{
+		autoclicker.TriggerModeHold.String(),
+		autoclicker.TriggerModeLatch.String(),
+		autoclicker.TriggerModeHoldOrLatch.String(),
+	}, nil)
+	triggerModeSelect.SetSelected(triggerMode.String())
+	bindingsBox := container.NewVBox()
+	refreshBindingRows := func([]bindingConfig) {}
 
 	errorText := canvas.NewText("", nil)
 	errorText.Color = theme.Color(theme.ColorNameError)
 	if settingsLoadWarning != "" {
 		errorText.Text = settingsLoadWarning
 	}
-	currentCPSText := widget.NewLabel("Current CPS: -")
+	currentCPSText := widget.NewLabel("Timing: -")
 	currentCPSText.TextStyle = fyne.TextStyle{Bold: true}
+	statsText := widget.NewLabel(formatStats(autoclicker.Stats{}))
 	logGrid := widget.NewTextGrid()
 	logGrid.SetText("")
 	logScroll := container.NewVScroll(logGrid)
@@ -405,6 +804,12 @@ func runUI(baseCfg config) error {
 	enableToggleBtn.Importance = widget.HighImportance
 	triggerCaptureBtn.Importance = widget.MediumImportance
 	toggleCaptureBtn.Importance = widget.MediumImportance
+	outputCaptureBtn.Importance = widget.MediumImportance
+	panicCaptureBtn.Importance = widget.MediumImportance
+	panicClearBtn.Importance = widget.LowImportance
+	pauseCaptureBtn.Importance = widget.MediumImportance
+	pauseClearBtn.Importance = widget.LowImportance
+	addBindingBtn.Importance = widget.LowImportance
 	initProgress := widget.NewProgressBarInfinite()
 	initProgress.Hide()
 
@@ -418,6 +823,35 @@ func runUI(baseCfg config) error {
 
 	var stateMu sync.Mutex
 	currentCfg := baseCfg
+	currentCfg.triggerRaw = triggerRaw
+	currentCfg.toggleRaw = toggleRaw
+	currentCfg.outputRaw = outputRaw
+	currentCfg.panicRaw = panicRaw
+	currentCfg.panicAction = panicAction
+	currentCfg.pattern = pattern
+	currentCfg.pauseCodes = pauseCodes
+	currentCfg.pauseMS = pauseMS
+	currentCfg.pauseTyping = pauseTyping
+	currentCfg.toggleGesture = toggleGesture
+	currentCfg.bindings = bindings
+	currentCfg.triggerMode = triggerMode
+	currentCfg.timing = timingKind
+	currentCfg.curve = curveKind
+	currentCfg.curveAmp = curveAmp
+	currentCfg.curvePeriod = curvePeriod
+	currentCfg.curveFile = curveFile
+	currentCfg.downModel = timingUniform
+	currentCfg.downMS = minDownDefault
+	currentCfg.downMaxMS = maxDownDefault
+	currentCfg.burst = int(math.Round(burstDefault))
+	currentCfg.burstCooldown = time.Duration(burstCooldownDefault * float64(time.Millisecond))
+	currentCfg.rampMS = rampUpDefault
+	currentCfg.rampDownMS = rampDownDefault
+	currentCfg.maxHold = time.Duration(maxHoldDefault * float64(time.Second))
+	currentCfg.holdDelayMS = holdDelayDefault
+	currentCfg.maxCPSWindow = int(cpsCapDefault)
+	currentCfg.debouncePress = debounceDownDefault
+	currentCfg.debounceRel = debounceUpDefault
 	var runningClicker clickerRuntime
 	var runtimeStop chan struct{}
 	initializing := false
@@ -456,6 +890,150 @@ func runUI(baseCfg config) error {
 		persistUISettings()
 	}
 
+	// applyClickDown draws each click's down time uniformly from the min/max
+	// down sliders; the service still caps it at the click's interval.
+	applyClickDown := func() {
+		updateControlText()
+		clicker, cfg, _ := getState()
+		cfg.downModel = timingUniform
+		cfg.downMS = minDownSlider.Value
+		cfg.downMaxMS = maxDownSlider.Value
+		setCurrentCfg(cfg)
+		if cfg.rhythmFile != "" {
+			// The rhythm profile's holds stay in charge.
+			persistUISettings()
+			return
+		}
+		model, err := cfg.clickDownModel()
+		if err == nil && clicker != nil {
+			err = clicker.SetClickDownModel(model)
+		}
+		if err != nil {
+			errorText.Text = err.Error()
+			errorText.Refresh()
+			appendLogLine("ERROR " + err.Error())
+		}
+		persistUISettings()
+	}
+	minDownSlider.OnChanged = func(v float64) {
+		if v > maxDownSlider.Value {
+			maxDownSlider.SetValue(v)
+		}
+		applyClickDown()
+	}
+	maxDownSlider.OnChanged = func(v float64) {
+		if v < minDownSlider.Value {
+			minDownSlider.SetValue(v)
+		}
+		applyClickDown()
+	}
+
+	applyBurst := func() {
+		updateControlText()
+		count := int(math.Round(burstSlider.Value))
+		cooldown := time.Duration(burstCooldownSlider.Value * float64(time.Millisecond))
+		clicker, cfg, _ := getState()
+		cfg.burst = count
+		cfg.burstCooldown = cooldown
+		setCurrentCfg(cfg)
+		if clicker != nil {
+			if err := clicker.SetBurst(count, cooldown); err != nil {
+				errorText.Text = err.Error()
+				errorText.Refresh()
+				appendLogLine("ERROR " + err.Error())
+			}
+		}
+		persistUISettings()
+	}
+	burstSlider.OnChanged = func(float64) { applyBurst() }
+	burstCooldownSlider.OnChanged = func(float64) { applyBurst() }
+
+	// applyRamp hands the ramp sliders to the running clicker; a ramp in
+	// progress follows the new curve from its next click.
+	applyRamp := func() {
+		updateControlText()
+		clicker, cfg, _ := getState()
+		cfg.rampMS = rampUpSlider.Value
+		cfg.rampDownMS = rampDownSlider.Value
+		setCurrentCfg(cfg)
+		if clicker != nil {
+			if err := clicker.SetRamp(cfg.ramp()); err != nil {
+				errorText.Text = err.Error()
+				errorText.Refresh()
+				appendLogLine("ERROR " + err.Error())
+			}
+		}
+		persistUISettings()
+	}
+	rampUpSlider.OnChanged = func(float64) { applyRamp() }
+	rampDownSlider.OnChanged = func(float64) { applyRamp() }
+
+	maxHoldSlider.OnChanged = func(v float64) {
+		updateControlText()
+		maxHold := time.Duration(v * float64(time.Second))
+		clicker, cfg, _ := getState()
+		cfg.maxHold = maxHold
+		setCurrentCfg(cfg)
+		if clicker != nil {
+			if err := clicker.SetMaxHold(maxHold); err != nil {
+				errorText.Text = err.Error()
+				errorText.Refresh()
+				appendLogLine("ERROR " + err.Error())
+			}
+		}
+		persistUISettings()
+	}
+
+	holdDelaySlider.OnChanged = func(v float64) {
+		updateControlText()
+		clicker, cfg, _ := getState()
+		cfg.holdDelayMS = v
+		setCurrentCfg(cfg)
+		if clicker != nil {
+			if err := clicker.SetHoldDelay(time.Duration(v * float64(time.Millisecond))); err != nil {
+				errorText.Text = err.Error()
+				errorText.Refresh()
+				appendLogLine("ERROR " + err.Error())
+			}
+		}
+		persistUISettings()
+	}
+
+	cpsCapSlider.OnChanged = func(v float64) {
+		updateControlText()
+		clicker, cfg, _ := getState()
+		cfg.maxCPSWindow = int(math.Round(v))
+		setCurrentCfg(cfg)
+		if clicker != nil {
+			if err := clicker.SetMaxCPSWindow(cfg.maxCPSWindow); err != nil {
+				errorText.Text = err.Error()
+				errorText.Refresh()
+				appendLogLine("ERROR " + err.Error())
+			}
+		}
+		persistUISettings()
+	}
+
+	applyDebounce := func() {
+		updateControlText()
+		clicker, cfg, _ := getState()
+		cfg.debouncePress = debounceDownSlider.Value
+		cfg.debounceRel = debounceUpSlider.Value
+		setCurrentCfg(cfg)
+		if clicker != nil {
+			press := time.Duration(cfg.debouncePress * float64(time.Millisecond))
+			release := time.Duration(cfg.debounceRel * float64(time.Millisecond))
+			if err := clicker.SetDebounce(press, release); err != nil {
+				errorText.Text = err.Error()
+				errorText.Refresh()
+				appendLogLine("ERROR " + err.Error())
+			}
+		}
+		persistUISettings()
+	}
+	debounceDownSlider.OnChanged = func(float64) { applyDebounce() }
+	debounceUpSlider.OnChanged = func(float64) { applyDebounce() }
+
 	setInitializingUI := func(v bool) {
 		if v {
 			initProgress.Show()
@@ -480,50 +1058,114 @@ func runUI(baseCfg config) error {
 		}
 	}
 
-	runRuntimeLoops := func(c clickerRuntime, stopCh <-chan struct{}) {
-		rng := rand.New(rand.NewSource(time.Now().UnixNano()))
-		cpsTicker := time.NewTicker(time.Second)
-		stateTicker := time.NewTicker(150 * time.Millisecond)
-		lastEnabled := c.IsEnabled()
-		defer cpsTicker.Stop()
-		defer stateTicker.Stop()
-
-		applyCPS := func() {
-			var min, max float64
-			fyne.DoAndWait(func() {
-				min = minSlider.Value
-				max = maxSlider.Value
-			})
-			if max < min {
-				min, max = max, min
-			}
-			cps := min
-			if max > min {
-				cps = min + rng.Float64()*(max-min)
-			}
-			if err := c.SetCPS(cps); err != nil {
-				return
-			}
-			fyne.Do(func() {
-				currentCPSText.SetText(fmt.Sprintf("Current CPS: %.2f", cps))
-			})
+	// applyTimingModel hands the rate range and distribution chosen in the
+	// rate card to the running clicker, which draws a new interval per click.
+	applyTimingModel = func() {
+		clicker, cfg, _ := getState()
+		cfg.timing = timingSelect.Selected
+		cfg.minCPS = minSlider.Value
+		cfg.maxCPS = maxSlider.Value
+		setCurrentCfg(cfg)
+		if cfg.rhythmFile != "" {
+			currentCPSText.SetText("Timing: rhythm " + filepath.Base(cfg.rhythmFile))
+			return
+		}
+
+		model, err := rangeTimingModel(cfg.timing, cfg.minCPS, cfg.maxCPS)
+		if err != nil {
+			errorText.Text = err.Error()
+			errorText.Refresh()
+			return
+		}
+		currentCPSText.SetText("Timing: " + model.String())
+		if clicker == nil {
+			return
+		}
+		if err := clicker.SetTimingModel(model); err != nil {
+			errorText.Text = err.Error()
+			errorText.Refresh()
+			appendLogLine("ERROR " + err.Error())
+		}
+	}
+	timingSelect.OnChanged = func(string) {
+		applyTimingModel()
+		persistUISettings()
+	}
+
+	// applyRateCurve hands the curve chosen in the rate card to the running
+	// clicker; the amplitude, period and schedule file come from the flags
+	// or saved settings.
+	applyRateCurve := func() {
+		clicker, cfg, _ := getState()
+		cfg.curve = curveSelect.Selected
+		curve, err := cfg.rateCurve()
+		if err != nil {
+			errorText.Text = err.Error()
+			errorText.Refresh()
+			appendLogLine("ERROR " + err.Error())
+			return
 		}
+		setCurrentCfg(cfg)
+		if clicker != nil {
+			clicker.SetRateCurve(curve)
+		}
+		appendLogLine("INFO Rate curve " + cfg.curve)
+	}
+	curveSelect.OnChanged = func(string) {
+		applyRateCurve()
+		persistUISettings()
+	}
 
-		applyCPS()
+	runRuntimeLoops := func(c clickerRuntime, stopCh <-chan struct{}) {
+		events, cancel := c.Subscribe(32)
+		statsTicker := time.NewTicker(500 * time.Millisecond)
+		defer cancel()
+		defer statsTicker.Stop()
+
+		fyne.DoAndWait(applyTimingModel)
+		// Catch up on any toggle that happened before the subscription.
+		enabled := c.IsEnabled()
+		fyne.Do(func() {
+			setEnabledStateUI(enabled)
+		})
 		for {
 			select {
 			case <-stopCh:
 				return
-			case <-cpsTicker.C:
-				applyCPS()
-			case <-stateTicker.C:
-				enabled := c.IsEnabled()
+			case <-statsTicker.C:
+				text := formatStats(c.Stats())
+				fyne.Do(func() {
+					statsText.SetText(text)
+				})
+			case event, ok := <-events:
+				if !ok {
+					return
+				}
+				if event.Kind == autoclicker.StatePanic {
+					warning := "Panic key pressed; clicking disabled and every button released."
+					fyne.Do(func() {
+						errorText.Text = warning
+						errorText.Refresh()
+						appendLogLine("WARNING " + warning)
+					})
+					continue
+				}
+				if event.Kind == autoclicker.StateHoldCutoff {
+					warning := fmt.Sprintf("%s held longer than the max hold; clicking stopped until it is pressed again.", displayCodeName(formatCodeName(event.Code)))
+					fyne.Do(func() {
+						errorText.Text = warning
+						errorText.Refresh()
+						appendLogLine("WARNING " + warning)
+					})
+					continue
+				}
+				if event.Kind != autoclicker.StateEnabled && event.Kind != autoclicker.StateDisabled {
+					continue
+				}
+				enabled := event.Kind == autoclicker.StateEnabled
 				fyne.Do(func() {
 					setEnabledStateUI(enabled)
-					if enabled != lastEnabled {
-						lastEnabled = enabled
-						persistUISettings()
-					}
+					persistUISettings()
 				})
 			}
 		}
@@ -551,6 +1193,10 @@ func runUI(baseCfg config) error {
 			setEnabledStateUI(clicker.IsEnabled())
 			triggerCaptureBtn.SetText(displayCodeName(cfg.triggerRaw))
 			toggleCaptureBtn.SetText(displayCodeName(cfg.toggleRaw))
+			outputCaptureBtn.SetText(displayCodeName(cfg.outputRaw))
+			panicCaptureBtn.SetText(displayCodeName(cfg.panicRaw))
+			pauseCaptureBtn.SetText(displayCodeNames(cfg.pauseCodes))
+			refreshBindingRows(cfg.bindings)
 		})
 		return nil
 	}
@@ -601,12 +1247,16 @@ func runUI(baseCfg config) error {
 
 		trigger := strings.TrimSpace(cfg.triggerRaw)
 		toggle := strings.TrimSpace(cfg.toggleRaw)
+		output := strings.TrimSpace(cfg.outputRaw)
 		if trigger == "" {
 			trigger = "BTN_LEFT"
 		}
 		if toggle == "" {
 			toggle = "BTN_EXTRA"
 		}
+		if output == "" {
+			output = "BTN_LEFT"
+		}
 
 		triggerCode, err := parseTriggerCode(trigger)
 		if err != nil {
@@ -619,14 +1269,40 @@ func runUI(baseCfg config) error {
 		if triggerCode == toggleCode {
 			return cfg, fmt.Errorf("toggle must be different from trigger")
 		}
+		outputCode, err := parseTriggerCode(output)
+		if err != nil {
+			return cfg, err
+		}
+		if outputCode == toggleCode {
+			return cfg, fmt.Errorf("output must be different from toggle")
+		}
+		if autoclicker.ChordModifiers(outputCode) != 0 {
+			return cfg, fmt.Errorf("output cannot be a chord")
+		}
+		if err := validateBindings(cfg.bindings, toggleCode); err != nil {
+			return cfg, err
+		}
 
 		cfg.triggerRaw = trigger
 		cfg.toggleRaw = toggle
+		cfg.outputRaw = output
 		cfg.triggerCode = triggerCode
 		cfg.toggleCode = toggleCode
-		cfg.cps = minSlider.Value
-		cfg.jitter = int(math.Round(jitterSlider.Value))
-		return cfg, nil
+		cfg.outputCode = outputCode
+		cfg.panicCode = 0
+		if panicName := strings.TrimSpace(cfg.panicRaw); panicName != "" {
+			panicCode, err := parseTriggerCode(panicName)
+			if err != nil {
+				return cfg, err
+			}
+			if err := cfg.validatePanicCode(panicCode); err != nil {
+				return cfg, fmt.Errorf("panic key %w", err)
+			}
+			cfg.panicCode = panicCode
+		}
+		cfg.cps = minSlider.Value
+		cfg.jitter = int(math.Round(jitterSlider.Value))
+		return cfg, nil
 	}
 
 	persistUISettings = func() {
@@ -637,12 +1313,38 @@ func runUI(baseCfg config) error {
 		}
 
 		settings := uiSettings{
-			MinCPS:  minSlider.Value,
-			MaxCPS:  maxSlider.Value,
-			Jitter:  int(math.Round(jitterSlider.Value)),
-			Trigger: strings.TrimSpace(cfg.triggerRaw),
-			Toggle:  strings.TrimSpace(cfg.toggleRaw),
-			Enabled: enabled,
+			MinCPS:        minSlider.Value,
+			MaxCPS:        maxSlider.Value,
+			Jitter:        int(math.Round(jitterSlider.Value)),
+			MinDownMS:     minDownSlider.Value,
+			MaxDownMS:     maxDownSlider.Value,
+			Trigger:       strings.TrimSpace(cfg.triggerRaw),
+			Toggle:        strings.TrimSpace(cfg.toggleRaw),
+			Output:        strings.TrimSpace(cfg.outputRaw),
+			Pattern:       formatPatternSpec(cfg.pattern),
+			Panic:         strings.TrimSpace(cfg.panicRaw),
+			PanicAction:   cfg.panicAction.String(),
+			Pause:         settingsFromCodes(cfg.pauseCodes),
+			PauseMS:       cfg.pauseMS,
+			PauseTyping:   &cfg.pauseTyping,
+			ToggleGesture: cfg.toggleGesture.String(),
+			TriggerMode:   cfg.triggerMode.String(),
+			Timing:        timingSelect.Selected,
+			Curve:         cfg.curve,
+			CurveAmp:      cfg.curveAmp,
+			CurvePeriod:   float64(cfg.curvePeriod) / float64(time.Millisecond),
+			CurveFile:     cfg.curveFile,
+			Burst:         int(math.Round(burstSlider.Value)),
+			BurstCoolMS:   burstCooldownSlider.Value,
+			RampUpMS:      rampUpSlider.Value,
+			RampDownMS:    rampDownSlider.Value,
+			MaxHoldMS:     maxHoldSlider.Value * 1000,
+			HoldDelayMS:   holdDelaySlider.Value,
+			MaxCPSWindow:  int(math.Round(cpsCapSlider.Value)),
+			DebounceDown:  debounceDownSlider.Value,
+			DebounceUp:    debounceUpSlider.Value,
+			Bindings:      settingsFromBindings(cfg.bindings),
+			Enabled:       enabled,
 		}
 
 		if err := saveUISettings(settings); err != nil {
@@ -702,6 +1404,12 @@ func runUI(baseCfg config) error {
 				}
 				return fmt.Errorf("captured trigger %s matches toggle; choose a different key/button", formatCodeName(code))
 			}
+			if code == cfg.panicCode {
+				if !capturedFromRuntime {
+					_ = startRuntime(prevCfg)
+				}
+				return fmt.Errorf("captured trigger %s matches panic key; choose a different key/button", formatCodeName(code))
+			}
 
 			cfg.triggerCode = code
 			cfg.triggerRaw = formatCodeName(code)
@@ -774,6 +1482,24 @@ func runUI(baseCfg config) error {
 				}
 				return fmt.Errorf("captured toggle %s matches trigger; choose a different key/button", formatCodeName(code))
 			}
+			if code == cfg.outputCode {
+				if !capturedFromRuntime {
+					_ = startRuntime(prevCfg)
+				}
+				return fmt.Errorf("captured toggle %s matches output; choose a different key/button", formatCodeName(code))
+			}
+			if code == cfg.panicCode {
+				if !capturedFromRuntime {
+					_ = startRuntime(prevCfg)
+				}
+				return fmt.Errorf("captured toggle %s matches panic key; choose a different key/button", formatCodeName(code))
+			}
+			if err := validateBindings(cfg.bindings, code); err != nil {
+				if !capturedFromRuntime {
+					_ = startRuntime(prevCfg)
+				}
+				return err
+			}
 
 			cfg.toggleCode = code
 			cfg.toggleRaw = formatCodeName(code)
@@ -805,6 +1531,563 @@ func runUI(baseCfg config) error {
 		})
 	}
 
+	outputCaptureBtn.OnTapped = func() {
+		clicker, _, _ := getState()
+		if clicker == nil {
+			return
+		}
+
+		cfg, err := buildCfgFromUI()
+		if err != nil {
+			errorText.Text = err.Error()
+			errorText.Refresh()
+			appendLogLine("ERROR " + err.Error())
+			return
+		}
+
+		appendLogLine("INFO Waiting for output input")
+		runRuntimeTaskAsync(func() error {
+			prevClicker, prevCfg, _ := getState()
+			if prevClicker == nil {
+				return fmt.Errorf("runtime is not initialized")
+			}
+			prevEnabled := prevClicker.IsEnabled()
+			prevCfg.startEnabled = prevEnabled
+
+			prevOutputRaw := prevCfg.outputRaw
+			capturedFromRuntime := true
+			code, err := prevClicker.CaptureNextKeyCode(2 * time.Second)
+			if err != nil {
+				capturedFromRuntime = false
+				stopRuntime()
+				code, err = captureNextCode(cfg.backend, "", 10*time.Second)
+				if err != nil {
+					_ = startRuntime(prevCfg)
+					return err
+				}
+			}
+			if code == cfg.toggleCode {
+				if !capturedFromRuntime {
+					_ = startRuntime(prevCfg)
+				}
+				return fmt.Errorf("captured output %s matches toggle; choose a different key/button", formatCodeName(code))
+			}
+			if code == cfg.panicCode {
+				if !capturedFromRuntime {
+					_ = startRuntime(prevCfg)
+				}
+				return fmt.Errorf("captured output %s matches panic key; choose a different key/button", formatCodeName(code))
+			}
+			if autoclicker.ChordModifiers(code) != 0 {
+				if !capturedFromRuntime {
+					_ = startRuntime(prevCfg)
+				}
+				return fmt.Errorf("captured output %s is a chord; choose a single key/button", formatCodeName(code))
+			}
+
+			cfg.outputCode = code
+			cfg.outputRaw = formatCodeName(code)
+			cfg.startEnabled = prevEnabled
+
+			fyne.DoAndWait(func() {
+				outputCaptureBtn.SetText(displayCodeName(cfg.outputRaw))
+			})
+
+			// The injector may not be able to emit the new code in-place (e.g. a
+			// uinput device created without that key), so fall back to a restart.
+			if capturedFromRuntime && prevClicker.SetOutputCode(code) == nil {
+				setCurrentCfg(cfg)
+				appendLogLine("INFO Captured output " + cfg.outputRaw)
+				return nil
+			}
+
+			stopRuntime()
+			if err := startRuntime(cfg); err != nil {
+				_ = startRuntime(prevCfg)
+				fyne.DoAndWait(func() {
+					outputCaptureBtn.SetText(displayCodeName(prevOutputRaw))
+				})
+				return err
+			}
+
+			appendLogLine("INFO Captured output " + cfg.outputRaw)
+			return nil
+		})
+	}
+
+	// applyConfig hands cfg to the running runtime in place when it can emit
+	// and observe every code, and restarts it otherwise.
+	applyConfig := func(prevClicker clickerRuntime, prevCfg, cfg config) error {
+		if prevClicker != nil && updateClickerFromConfig(prevClicker, cfg) == nil {
+			setCurrentCfg(cfg)
+			fyne.DoAndWait(func() {
+				refreshBindingRows(cfg.bindings)
+			})
+			return nil
+		}
+
+		stopRuntime()
+		if err := startRuntime(cfg); err != nil {
+			_ = startRuntime(prevCfg)
+			return err
+		}
+		return nil
+	}
+
+	addBindingBtn.OnTapped = func() {
+		clicker, _, _ := getState()
+		if clicker == nil {
+			return
+		}
+
+		cfg, err := buildCfgFromUI()
+		if err != nil {
+			errorText.Text = err.Error()
+			errorText.Refresh()
+			appendLogLine("ERROR " + err.Error())
+			return
+		}
+
+		appendLogLine("INFO Waiting for binding trigger input")
+		runRuntimeTaskAsync(func() error {
+			prevClicker, prevCfg, _ := getState()
+			if prevClicker == nil {
+				return fmt.Errorf("runtime is not initialized")
+			}
+			prevEnabled := prevClicker.IsEnabled()
+			prevCfg.startEnabled = prevEnabled
+			cfg.startEnabled = prevEnabled
+
+			liveClicker := prevClicker
+			captureCode := func() (uint16, error) {
+				if liveClicker != nil {
+					if code, err := liveClicker.CaptureNextKeyCode(2 * time.Second); err == nil {
+						return code, nil
+					}
+					liveClicker = nil
+					stopRuntime()
+				}
+				return captureNextCode(cfg.backend, "", 10*time.Second)
+			}
+			fail := func(err error) error {
+				if liveClicker == nil {
+					_ = startRuntime(prevCfg)
+				}
+				return err
+			}
+
+			triggerCode, err := captureCode()
+			if err != nil {
+				return fail(err)
+			}
+			appendLogLine("INFO Waiting for binding output input")
+			outputCode, err := captureCode()
+			if err != nil {
+				return fail(err)
+			}
+
+			binding := bindingConfig{
+				triggerCode: triggerCode,
+				outputCode:  outputCode,
+				triggerRaw:  formatCodeName(triggerCode),
+				outputRaw:   formatCodeName(outputCode),
+				cps:         cfg.cps,
+				downMS:      cfg.downMS,
+				jitter:      cfg.jitter,
+			}
+			bindings := append(append([]bindingConfig(nil), cfg.bindings...), binding)
+			if err := validateBindings(bindings, cfg.toggleCode); err != nil {
+				return fail(err)
+			}
+			cfg.bindings = bindings
+			if cfg.panicCode != 0 {
+				if err := cfg.validatePanicCode(cfg.panicCode); err != nil {
+					return fail(fmt.Errorf("panic key %w", err))
+				}
+			}
+
+			if liveClicker == nil {
+				if err := startRuntime(cfg); err != nil {
+					_ = startRuntime(prevCfg)
+					return err
+				}
+			} else if err := applyConfig(liveClicker, prevCfg, cfg); err != nil {
+				return err
+			}
+
+			appendLogLine(fmt.Sprintf("INFO Added binding %s -> %s", binding.triggerRaw, binding.outputRaw))
+			return nil
+		})
+	}
+
+	panicCaptureBtn.OnTapped = func() {
+		clicker, _, _ := getState()
+		if clicker == nil {
+			return
+		}
+
+		cfg, err := buildCfgFromUI()
+		if err != nil {
+			errorText.Text = err.Error()
+			errorText.Refresh()
+			appendLogLine("ERROR " + err.Error())
+			return
+		}
+
+		appendLogLine("INFO Waiting for panic key input")
+		runRuntimeTaskAsync(func() error {
+			prevClicker, prevCfg, _ := getState()
+			if prevClicker == nil {
+				return fmt.Errorf("runtime is not initialized")
+			}
+			prevEnabled := prevClicker.IsEnabled()
+			prevCfg.startEnabled = prevEnabled
+			cfg.startEnabled = prevEnabled
+
+			capturedFromRuntime := true
+			code, err := prevClicker.CaptureNextKeyCode(2 * time.Second)
+			if err != nil {
+				capturedFromRuntime = false
+				stopRuntime()
+				code, err = captureNextCode(cfg.backend, "", 10*time.Second)
+				if err != nil {
+					_ = startRuntime(prevCfg)
+					return err
+				}
+			}
+			if err := cfg.validatePanicCode(code); err != nil {
+				if !capturedFromRuntime {
+					_ = startRuntime(prevCfg)
+				}
+				return fmt.Errorf("captured panic key %s %w; choose a different key/button", formatCodeName(code), err)
+			}
+
+			cfg.panicCode = code
+			cfg.panicRaw = formatCodeName(code)
+			fyne.DoAndWait(func() {
+				panicCaptureBtn.SetText(displayCodeName(cfg.panicRaw))
+			})
+
+			// The panic key may live on a device the runtime has not opened,
+			// in which case applyConfig restarts it.
+			if capturedFromRuntime {
+				err = applyConfig(prevClicker, prevCfg, cfg)
+			} else if err = startRuntime(cfg); err != nil {
+				_ = startRuntime(prevCfg)
+			}
+			if err != nil {
+				fyne.DoAndWait(func() {
+					panicCaptureBtn.SetText(displayCodeName(prevCfg.panicRaw))
+				})
+				return err
+			}
+
+			appendLogLine("INFO Captured panic key " + cfg.panicRaw)
+			return nil
+		})
+	}
+
+	panicClearBtn.OnTapped = func() {
+		runRuntimeTaskAsync(func() error {
+			prevClicker, prevCfg, _ := getState()
+			if prevClicker == nil {
+				return fmt.Errorf("runtime is not initialized")
+			}
+			if prevCfg.panicCode == 0 {
+				return nil
+			}
+			prevCfg.startEnabled = prevClicker.IsEnabled()
+
+			cfg := prevCfg
+			cfg.panicCode = 0
+			cfg.panicRaw = ""
+			if err := applyConfig(prevClicker, prevCfg, cfg); err != nil {
+				return err
+			}
+			fyne.DoAndWait(func() {
+				panicCaptureBtn.SetText(displayCodeName(cfg.panicRaw))
+			})
+			appendLogLine("INFO Cleared panic key")
+			return nil
+		})
+	}
+
+	pauseCaptureBtn.OnTapped = func() {
+		clicker, _, _ := getState()
+		if clicker == nil {
+			return
+		}
+
+		cfg, err := buildCfgFromUI()
+		if err != nil {
+			errorText.Text = err.Error()
+			errorText.Refresh()
+			appendLogLine("ERROR " + err.Error())
+			return
+		}
+
+		appendLogLine("INFO Waiting for pause key input")
+		runRuntimeTaskAsync(func() error {
+			prevClicker, prevCfg, _ := getState()
+			if prevClicker == nil {
+				return fmt.Errorf("runtime is not initialized")
+			}
+			prevEnabled := prevClicker.IsEnabled()
+			prevCfg.startEnabled = prevEnabled
+			cfg.startEnabled = prevEnabled
+
+			capturedFromRuntime := true
+			code, err := prevClicker.CaptureNextKeyCode(2 * time.Second)
+			if err != nil {
+				capturedFromRuntime = false
+				stopRuntime()
+				code, err = captureNextCode(cfg.backend, "", 10*time.Second)
+				if err != nil {
+					_ = startRuntime(prevCfg)
+					return err
+				}
+			}
+			if slices.Contains(cfg.pauseCodes, code) {
+				if !capturedFromRuntime {
+					_ = startRuntime(prevCfg)
+				}
+				return nil
+			}
+			if err := cfg.validatePauseCode(code); err != nil {
+				if !capturedFromRuntime {
+					_ = startRuntime(prevCfg)
+				}
+				return fmt.Errorf("captured pause key %s %w; choose a different key/button", formatCodeName(code), err)
+			}
+
+			cfg.pauseCodes = append(append([]uint16(nil), cfg.pauseCodes...), code)
+			fyne.DoAndWait(func() {
+				pauseCaptureBtn.SetText(displayCodeNames(cfg.pauseCodes))
+			})
+
+			// The pause key may live on a device the runtime has not opened,
+			// in which case applyConfig restarts it.
+			if capturedFromRuntime {
+				err = applyConfig(prevClicker, prevCfg, cfg)
+			} else if err = startRuntime(cfg); err != nil {
+				_ = startRuntime(prevCfg)
+			}
+			if err != nil {
+				fyne.DoAndWait(func() {
+					pauseCaptureBtn.SetText(displayCodeNames(prevCfg.pauseCodes))
+				})
+				return err
+			}
+
+			appendLogLine("INFO Added pause key " + formatCodeName(code))
+			return nil
+		})
+	}
+
+	pauseClearBtn.OnTapped = func() {
+		runRuntimeTaskAsync(func() error {
+			prevClicker, prevCfg, _ := getState()
+			if prevClicker == nil {
+				return fmt.Errorf("runtime is not initialized")
+			}
+			if len(prevCfg.pauseCodes) == 0 {
+				return nil
+			}
+			prevCfg.startEnabled = prevClicker.IsEnabled()
+
+			cfg := prevCfg
+			cfg.pauseCodes = nil
+			if err := applyConfig(prevClicker, prevCfg, cfg); err != nil {
+				return err
+			}
+			fyne.DoAndWait(func() {
+				pauseCaptureBtn.SetText(displayCodeNames(cfg.pauseCodes))
+			})
+			appendLogLine("INFO Cleared pause keys")
+			return nil
+		})
+	}
+
+	pauseTypingCheck.OnChanged = func(checked bool) {
+		if _, cfg, _ := getState(); cfg.pauseTyping == checked {
+			return
+		}
+		runRuntimeTaskAsync(func() error {
+			prevClicker, prevCfg, _ := getState()
+			if prevClicker == nil {
+				return fmt.Errorf("runtime is not initialized")
+			}
+			prevCfg.startEnabled = prevClicker.IsEnabled()
+
+			cfg := prevCfg
+			cfg.pauseTyping = checked
+			if err := applyConfig(prevClicker, prevCfg, cfg); err != nil {
+				fyne.Do(func() {
+					pauseTypingCheck.SetChecked(prevCfg.pauseTyping)
+				})
+				return err
+			}
+			if checked {
+				appendLogLine("INFO Pausing while typing")
+			} else {
+				appendLogLine("INFO No longer pausing while typing")
+			}
+			return nil
+		})
+	}
+
+	panicActionSelect.OnChanged = func(value string) {
+		action, err := autoclicker.ParsePanicAction(value)
+		if err != nil {
+			return
+		}
+		if _, cfg, _ := getState(); cfg.panicAction == action {
+			return
+		}
+		runRuntimeTaskAsync(func() error {
+			prevClicker, prevCfg, _ := getState()
+			if prevClicker == nil {
+				return fmt.Errorf("runtime is not initialized")
+			}
+			prevCfg.startEnabled = prevClicker.IsEnabled()
+
+			cfg := prevCfg
+			cfg.panicAction = action
+			if err := applyConfig(prevClicker, prevCfg, cfg); err != nil {
+				return err
+			}
+			appendLogLine("INFO Panic action " + action.String())
+			return nil
+		})
+	}
+
+	patternEntry.OnSubmitted = func(value string) {
+		pattern, err := parsePatternSpec(value)
+		if err == nil {
+			_, cfg, _ := getState()
+			for _, code := range pattern.Codes() {
+				if code == cfg.toggleCode {
+					err = fmt.Errorf("pattern must not press the toggle %s", formatCodeName(code))
+				}
+			}
+		}
+		if err != nil {
+			errorText.Text = err.Error()
+			errorText.Refresh()
+			appendLogLine("ERROR " + err.Error())
+			return
+		}
+		runRuntimeTaskAsync(func() error {
+			prevClicker, prevCfg, _ := getState()
+			if prevClicker == nil {
+				return fmt.Errorf("runtime is not initialized")
+			}
+			prevCfg.startEnabled = prevClicker.IsEnabled()
+
+			cfg := prevCfg
+			cfg.pattern = pattern
+			if err := applyConfig(prevClicker, prevCfg, cfg); err != nil {
+				return err
+			}
+			fyne.DoAndWait(func() {
+				patternEntry.SetText(formatPatternSpec(cfg.pattern))
+			})
+			if len(cfg.pattern) == 0 {
+				appendLogLine("INFO Cleared pattern")
+			} else {
+				appendLogLine("INFO Pattern " + formatPatternSpec(cfg.pattern))
+			}
+			return nil
+		})
+	}
+
+	toggleGestureSelect.OnChanged = func(value string) {
+		gesture, err := autoclicker.ParseToggleGesture(value)
+		if err != nil {
+			return
+		}
+		if _, cfg, _ := getState(); cfg.toggleGesture == gesture {
+			return
+		}
+		runRuntimeTaskAsync(func() error {
+			prevClicker, prevCfg, _ := getState()
+			if prevClicker == nil {
+				return fmt.Errorf("runtime is not initialized")
+			}
+			prevCfg.startEnabled = prevClicker.IsEnabled()
+
+			cfg := prevCfg
+			cfg.toggleGesture = gesture
+			if err := applyConfig(prevClicker, prevCfg, cfg); err != nil {
+				return err
+			}
+			appendLogLine("INFO Toggle gesture " + gesture.String())
+			return nil
+		})
+	}
+
+	removeBinding := func(index int) {
+		runRuntimeTaskAsync(func() error {
+			prevClicker, prevCfg, _ := getState()
+			if prevClicker == nil {
+				return fmt.Errorf("runtime is not initialized")
+			}
+			if index < 0 || index >= len(prevCfg.bindings) {
+				return nil
+			}
+			prevCfg.startEnabled = prevClicker.IsEnabled()
+
+			cfg := prevCfg
+			removed := prevCfg.bindings[index]
+			cfg.bindings = append(append([]bindingConfig(nil), prevCfg.bindings[:index]...), prevCfg.bindings[index+1:]...)
+			if err := applyConfig(prevClicker, prevCfg, cfg); err != nil {
+				return err
+			}
+
+			appendLogLine(fmt.Sprintf("INFO Removed binding %s -> %s", removed.triggerRaw, removed.outputRaw))
+			return nil
+		})
+	}
+
+	refreshBindingRows = func(bindings []bindingConfig) {
+		rows := make([]fyne.CanvasObject, 0, len(bindings))
+		for i, binding := range bindings {
+			index := i
+			label := widget.NewLabel(fmt.Sprintf("%s → %s @ %.2f", displayCodeName(binding.triggerRaw), displayCodeName(binding.outputRaw), binding.cps))
+			label.Truncation = fyne.TextTruncateEllipsis
+			removeBtn := widget.NewButtonWithIcon("", theme.DeleteIcon(), func() {
+				removeBinding(index)
+			})
+			removeBtn.Importance = widget.LowImportance
+			rows = append(rows, container.NewBorder(nil, nil, nil, removeBtn, label))
+		}
+		bindingsBox.Objects = rows
+		bindingsBox.Refresh()
+	}
+	refreshBindingRows(bindings)
+
+	triggerModeSelect.OnChanged = func(value string) {
+		mode, err := autoclicker.ParseTriggerMode(value)
+		if err != nil {
+			return
+		}
+		clicker, cfg, _ := getState()
+		if cfg.triggerMode == mode {
+			return
+		}
+		cfg.triggerMode = mode
+		setCurrentCfg(cfg)
+		if clicker != nil {
+			if err := clicker.SetTriggerMode(mode); err != nil {
+				errorText.Text = err.Error()
+				errorText.Refresh()
+				appendLogLine("ERROR " + err.Error())
+			}
+		}
+		appendLogLine("INFO Trigger mode " + mode.String())
+		persistUISettings()
+	}
+
 	startupCfg, err := buildCfgFromUI()
 	if err != nil {
 		return err
@@ -881,16 +2164,53 @@ func runUI(baseCfg config) error {
 	}
 
 	rateControls := container.NewVBox(
-		newSliderControl("Min CPS", minValue, minSlider),
-		newSliderControl("Max CPS", maxValue, maxSlider),
-		newSliderControl("Jitter", jitterValue, jitterSlider),
+		widget.NewForm(
+			widget.NewFormItem("Timing", timingSelect),
+			widget.NewFormItem("Curve", curveSelect),
+		),
+		container.NewGridWithColumns(2,
+			newSliderControl("Min CPS", minValue, minSlider),
+			newSliderControl("Max CPS", maxValue, maxSlider),
+		),
+		container.NewGridWithColumns(2,
+			newSliderControl("Min Down", minDownValue, minDownSlider),
+			newSliderControl("Max Down", maxDownValue, maxDownSlider),
+		),
+		container.NewGridWithColumns(2,
+			newSliderControl("Burst", burstValue, burstSlider),
+			newSliderControl("Cooldown", burstCooldownValue, burstCooldownSlider),
+		),
+		container.NewGridWithColumns(2,
+			newSliderControl("Ramp Up", rampUpValue, rampUpSlider),
+			newSliderControl("Ramp Down", rampDownValue, rampDownSlider),
+		),
+		container.NewGridWithColumns(2,
+			newSliderControl("Hold Delay", holdDelayValue, holdDelaySlider),
+			newSliderControl("Max Hold", maxHoldValue, maxHoldSlider),
+		),
+		container.NewGridWithColumns(2,
+			newSliderControl("Debounce Down", debounceDownValue, debounceDownSlider),
+			newSliderControl("Debounce Up", debounceUpValue, debounceUpSlider),
+		),
+		container.NewGridWithColumns(2,
+			newSliderControl("Jitter", jitterValue, jitterSlider),
+			newSliderControl("CPS Cap", cpsCapValue, cpsCapSlider),
+		),
 	)
 	keybindControls := widget.NewForm(
 		widget.NewFormItem("Trigger", triggerCaptureBtn),
+		widget.NewFormItem("Mode", triggerModeSelect),
 		widget.NewFormItem("Toggle", toggleCaptureBtn),
+		widget.NewFormItem("Toggle Gesture", toggleGestureSelect),
+		widget.NewFormItem("Output", outputCaptureBtn),
+		widget.NewFormItem("Pattern", patternEntry),
+		widget.NewFormItem("Panic", container.NewBorder(nil, nil, nil, panicClearBtn, panicCaptureBtn)),
+		widget.NewFormItem("On Panic", panicActionSelect),
+		widget.NewFormItem("Pause", container.NewBorder(nil, nil, nil, pauseClearBtn, pauseCaptureBtn)),
+		widget.NewFormItem("", pauseTypingCheck),
 	)
 	rateCard := widget.NewCard("Rate", "", rateControls)
-	keybindCard := widget.NewCard("Keybinds", "", keybindControls)
+	keybindCard := widget.NewCard("Keybinds", "", container.NewVBox(keybindControls, bindingsBox, addBindingBtn))
 	controlsRow := container.NewGridWithColumns(2, rateCard, keybindCard)
 
 	mainContent := container.NewVBox(
@@ -898,6 +2218,7 @@ func runUI(baseCfg config) error {
 		accentLine,
 		controlsRow,
 		currentCPSText,
+		statsText,
 		errorText,
 		initProgress,
 		enableToggleBtn,
@@ -7,10 +7,14 @@ import (
 	"sort"
 	"time"
 
+	"clicker/internal/core/autoclicker"
+
 	evdev "github.com/holoplot/go-evdev"
 )
 
-// CaptureNextKeyCode waits for the next pressed key/button (EV_KEY with value 1).
+// CaptureNextKeyCode waits for the next pressed key/button (EV_KEY with value 1),
+// returning a chord when modifiers are held and a modifier pressed on its own
+// once it is released.
 // If devicePath is empty, it listens on all non-virtual input devices with key capabilities.
 func CaptureNextKeyCode(devicePath string, timeout time.Duration) (uint16, error) {
 	devices, err := openCaptureDevices(devicePath)
@@ -28,8 +32,9 @@ func captureNextFromDevices(devices []*evdev.InputDevice, timeout time.Duration)
 
 	done := make(chan struct{})
 	codeCh := make(chan uint16, 1)
+	chords := &autoclicker.ChordCapture{}
 	for _, dev := range devices {
-		go captureDeviceLoop(dev, done, codeCh)
+		go captureDeviceLoop(dev, chords, done, codeCh)
 	}
 
 	timer := time.NewTimer(timeout)
@@ -45,7 +50,7 @@ func captureNextFromDevices(devices []*evdev.InputDevice, timeout time.Duration)
 	}
 }
 
-func captureDeviceLoop(dev *evdev.InputDevice, done <-chan struct{}, codeCh chan<- uint16) {
+func captureDeviceLoop(dev *evdev.InputDevice, chords *autoclicker.ChordCapture, done <-chan struct{}, codeCh chan<- uint16) {
 	for {
 		select {
 		case <-done:
@@ -72,9 +77,14 @@ func captureDeviceLoop(dev *evdev.InputDevice, done <-chan struct{}, codeCh chan
 		if event == nil {
 			continue
 		}
-		if event.Type == evdev.EV_KEY && event.Value == 1 {
+		code, ok := chords.Feed(dev.Path(), autoclicker.Event{
+			Type:  uint16(event.Type),
+			Code:  uint16(event.Code),
+			Value: event.Value,
+		})
+		if ok {
 			select {
-			case codeCh <- uint16(event.Code):
+			case codeCh <- code:
 			default:
 			}
 			return
@@ -151,3 +161,61 @@ func closeInputDevices(devices []*evdev.InputDevice) {
 		_ = dev.Close()
 	}
 }
+
+// WatchKey reports every press and release of code, with the kernel's event
+// time, until stop is closed. If devicePath is empty, it listens on all
+// non-virtual input devices with key capabilities.
+func WatchKey(devicePath string, code uint16, stop <-chan struct{}, fn func(down bool, at time.Time)) error {
+	devices, err := openCaptureDevices(devicePath)
+	if err != nil {
+		return err
+	}
+	defer closeInputDevices(devices)
+
+	type keyEvent struct {
+		down bool
+		at   time.Time
+	}
+	events := make(chan keyEvent, 64)
+	done := make(chan struct{})
+	defer close(done)
+	for _, dev := range devices {
+		go func(dev *evdev.InputDevice) {
+			for {
+				select {
+				case <-done:
+					return
+				default:
+				}
+				event, err := dev.ReadOne()
+				if err != nil {
+					if isDeviceClosedError(err) {
+						return
+					}
+					if !sleepCapture(done, 2*time.Millisecond) {
+						return
+					}
+					continue
+				}
+				if event == nil || event.Type != evdev.EV_KEY || uint16(event.Code) != code || event.Value == 2 {
+					continue
+				}
+				at := time.Unix(0, event.Time.Nano())
+				select {
+				case events <- keyEvent{down: event.Value == 1, at: at}:
+				case <-done:
+					return
+				}
+			}
+		}(dev)
+	}
+
+	for {
+		select {
+		case <-stop:
+			return nil
+		case event := <-events:
+			fn(event.down, event.at)
+		}
+	}
+}
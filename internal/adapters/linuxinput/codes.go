@@ -7,6 +7,8 @@ import (
 	"strconv"
 	"strings"
 
+	"clicker/internal/core/autoclicker"
+
 	evdev "github.com/holoplot/go-evdev"
 )
 
@@ -16,6 +18,10 @@ const (
 )
 
 func ParseCode(value string) (uint16, error) {
+	return autoclicker.ParseChord(value, parseKeyCode)
+}
+
+func parseKeyCode(value string) (uint16, error) {
 	raw := strings.ToUpper(strings.TrimSpace(value))
 	if raw == "" {
 		return 0, fmt.Errorf("trigger code is empty")
@@ -47,6 +53,10 @@ func ParseCode(value string) (uint16, error) {
 }
 
 func FormatCodeName(code uint16) string {
+	return autoclicker.FormatChord(code, formatKeyName)
+}
+
+func formatKeyName(code uint16) string {
 	name := evdev.CodeName(evdev.EV_KEY, evdev.EvCode(code))
 	if name != "" {
 		if strings.Contains(name, "/") {
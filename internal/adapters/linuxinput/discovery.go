@@ -8,9 +8,13 @@ import (
 	"sort"
 	"strings"
 
+	"clicker/internal/core/autoclicker"
+
 	evdev "github.com/holoplot/go-evdev"
 )
 
+const keyboardProbeCode = uint16(evdev.KEY_A)
+
 type DeviceInfo struct {
 	Path      string
 	Name      string
@@ -22,6 +26,8 @@ type SourceSelection struct {
 	Devices      []*evdev.InputDevice
 	TriggerPaths map[string]struct{}
 	TogglePaths  map[string]struct{}
+	PanicPaths   map[string]struct{}
+	PausePaths   map[string]struct{}
 }
 
 func ListInputDevices() ([]DeviceInfo, error) {
@@ -58,15 +64,41 @@ func ListInputDevices() ([]DeviceInfo, error) {
 	return devices, nil
 }
 
-func OpenSourceSelection(devicePath string, triggerCode, toggleCode uint16) (*SourceSelection, error) {
+func OpenSourceSelection(devicePath string, triggerCodes []uint16, toggleCode, panicCode uint16, pauseCodes []uint16, pauseTyping bool) (*SourceSelection, error) {
+	selection, err := openSourceSelection(devicePath, triggerCodes, toggleCode)
+	if err != nil {
+		return nil, err
+	}
+	err = selection.openModifierDevices(append([]uint16{toggleCode}, triggerCodes...))
+	if err == nil && panicCode != 0 {
+		err = selection.openPanicDevices(panicCode)
+	}
+	if err == nil && (len(pauseCodes) > 0 || pauseTyping) {
+		err = selection.openPauseDevices(pauseCodes, pauseTyping)
+	}
+	if err != nil {
+		for _, dev := range selection.Devices {
+			_ = dev.Close()
+		}
+		return nil, err
+	}
+	return selection, nil
+}
+
+func openSourceSelection(devicePath string, triggerCodes []uint16, toggleCode uint16) (*SourceSelection, error) {
+	if len(triggerCodes) == 0 {
+		return nil, fmt.Errorf("no trigger codes configured")
+	}
 	if devicePath != "" {
 		dev, err := openInputDevice(devicePath)
 		if err != nil {
 			return nil, err
 		}
-		if !deviceSupportsCode(dev, triggerCode) {
-			_ = dev.Close()
-			return nil, fmt.Errorf("%s does not expose trigger %s", devicePath, FormatCodeName(triggerCode))
+		for _, triggerCode := range triggerCodes {
+			if !deviceSupportsCode(dev, triggerCode) {
+				_ = dev.Close()
+				return nil, fmt.Errorf("%s does not expose trigger %s", devicePath, FormatCodeName(triggerCode))
+			}
 		}
 		if !deviceSupportsCode(dev, toggleCode) {
 			_ = dev.Close()
@@ -80,12 +112,18 @@ func OpenSourceSelection(devicePath string, triggerCode, toggleCode uint16) (*So
 		}, nil
 	}
 
-	triggerMatches, err := findDevicesByCode(triggerCode)
-	if err != nil {
-		return nil, err
-	}
-	if len(triggerMatches) == 0 {
-		return nil, fmt.Errorf("no input device exposes trigger %s; use --list-devices and then pass --device", FormatCodeName(triggerCode))
+	triggerPaths := make(map[string]struct{})
+	for _, triggerCode := range triggerCodes {
+		triggerMatches, err := findDevicesByCode(triggerCode)
+		if err != nil {
+			return nil, err
+		}
+		if len(triggerMatches) == 0 {
+			return nil, fmt.Errorf("no input device exposes trigger %s; use --list-devices and then pass --device", FormatCodeName(triggerCode))
+		}
+		for _, dev := range triggerMatches {
+			triggerPaths[dev.Path] = struct{}{}
+		}
 	}
 
 	toggleMatches, err := findDevicesByCode(toggleCode)
@@ -96,11 +134,6 @@ func OpenSourceSelection(devicePath string, triggerCode, toggleCode uint16) (*So
 		return nil, fmt.Errorf("no input device exposes toggle %s; use --list-devices and choose another --toggle", FormatCodeName(toggleCode))
 	}
 
-	triggerPaths := make(map[string]struct{}, len(triggerMatches))
-	for _, dev := range triggerMatches {
-		triggerPaths[dev.Path] = struct{}{}
-	}
-
 	togglePaths := make(map[string]struct{}, len(toggleMatches))
 	for _, dev := range toggleMatches {
 		togglePaths[dev.Path] = struct{}{}
@@ -166,12 +199,157 @@ func OpenSourceSelection(devicePath string, triggerCode, toggleCode uint16) (*So
 	return &SourceSelection{Devices: devices, TriggerPaths: triggerPaths, TogglePaths: togglePaths}, nil
 }
 
+func (s *SourceSelection) openPanicDevices(panicCode uint16) error {
+	s.PanicPaths = make(map[string]struct{})
+	opened := make(map[string]struct{}, len(s.Devices))
+	for _, dev := range s.Devices {
+		opened[dev.Path()] = struct{}{}
+		if deviceSupportsCode(dev, panicCode) {
+			s.PanicPaths[dev.Path()] = struct{}{}
+		}
+	}
+	if len(s.PanicPaths) > 0 {
+		return nil
+	}
+
+	matches, err := findDevicesByCode(panicCode)
+	if err != nil {
+		return err
+	}
+	for _, match := range matches {
+		if _, ok := opened[match.Path]; ok {
+			continue
+		}
+		dev, err := openInputDevice(match.Path)
+		if err != nil {
+			continue
+		}
+		s.Devices = append(s.Devices, dev)
+		s.PanicPaths[match.Path] = struct{}{}
+	}
+	if len(s.PanicPaths) == 0 {
+		return fmt.Errorf("no input device exposes panic %s; use --list-devices and choose another --panic", FormatCodeName(panicCode))
+	}
+	return nil
+}
+
+func (s *SourceSelection) openPauseDevices(codes []uint16, typing bool) error {
+	if typing {
+		codes = append(codes, keyboardProbeCode)
+	}
+	for _, code := range codes {
+		if err := s.openDevicesExposing(code); err != nil {
+			return err
+		}
+		if !s.exposesAnyKey([]uint16{code}) {
+			if code == keyboardProbeCode && typing {
+				return fmt.Errorf("no keyboard found to pause while typing; use --list-devices and pass --device")
+			}
+			return fmt.Errorf("no input device exposes pause %s; use --list-devices and choose another --pause", FormatCodeName(code))
+		}
+	}
+	s.PausePaths = pauseDevicePaths(s.Devices, codes)
+	return nil
+}
+
+func pauseDevicePaths(devices []*evdev.InputDevice, codes []uint16) map[string]struct{} {
+	paths := make(map[string]struct{})
+	for _, dev := range devices {
+		for _, code := range codes {
+			if deviceSupportsCode(dev, code) {
+				paths[dev.Path()] = struct{}{}
+				break
+			}
+		}
+	}
+	return paths
+}
+
+func (s *SourceSelection) openDevicesExposing(code uint16) error {
+	if s.exposesAnyKey([]uint16{code}) {
+		return nil
+	}
+	opened := make(map[string]struct{}, len(s.Devices))
+	for _, dev := range s.Devices {
+		opened[dev.Path()] = struct{}{}
+	}
+	matches, err := findDevicesByCode(code)
+	if err != nil {
+		return err
+	}
+	for _, match := range matches {
+		if _, ok := opened[match.Path]; ok {
+			continue
+		}
+		dev, err := openInputDevice(match.Path)
+		if err != nil {
+			continue
+		}
+		s.Devices = append(s.Devices, dev)
+	}
+	return nil
+}
+
+func (s *SourceSelection) openModifierDevices(codes []uint16) error {
+	var mods autoclicker.Modifiers
+	for _, code := range codes {
+		mods |= autoclicker.ChordModifiers(code)
+	}
+	for _, mod := range mods.Split() {
+		if s.exposesModifiers(mod) {
+			continue
+		}
+		opened := make(map[string]struct{}, len(s.Devices))
+		for _, dev := range s.Devices {
+			opened[dev.Path()] = struct{}{}
+		}
+		matches, err := findDevicesByCode(mod.Keys()[0])
+		if err != nil {
+			return err
+		}
+		for _, match := range matches {
+			if _, ok := opened[match.Path]; ok {
+				continue
+			}
+			dev, err := openInputDevice(match.Path)
+			if err != nil {
+				continue
+			}
+			s.Devices = append(s.Devices, dev)
+		}
+		if !s.exposesModifiers(mod) {
+			return fmt.Errorf("no input device exposes the %s modifier; use --list-devices and choose another chord", mod)
+		}
+	}
+	return nil
+}
+
+func (s *SourceSelection) exposesModifiers(mods autoclicker.Modifiers) bool {
+	for _, mod := range mods.Split() {
+		if !s.exposesAnyKey(mod.Keys()) {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *SourceSelection) exposesAnyKey(codes []uint16) bool {
+	for _, dev := range s.Devices {
+		for _, code := range codes {
+			if deviceSupportsCode(dev, code) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 func openInputDevice(path string) (*evdev.InputDevice, error) {
 	return evdev.OpenWithFlags(path, os.O_RDONLY)
 }
 
 func deviceSupportsCode(device *evdev.InputDevice, code uint16) bool {
-	needle := evdev.EvCode(code)
+	needle := evdev.EvCode(autoclicker.ChordKey(code))
 	for _, c := range device.CapableEvents(evdev.EV_KEY) {
 		if c == needle {
 			return true
@@ -5,6 +5,7 @@ package linuxinput
 import (
 	"errors"
 	"fmt"
+	"slices"
 	"sort"
 	"sync"
 	"syscall"
@@ -18,27 +19,63 @@ import (
 type RuntimeConfig struct {
 	TriggerCode        uint16
 	ToggleCode         uint16
+	OutputCode         uint16
 	CPS                float64
+	Timing             autoclicker.TimingModel
+	Curve              autoclicker.RateCurve
 	ClickDown          time.Duration
+	ClickDownModel     autoclicker.ClickDownModel
 	JitterPixels       int
+	Pattern            autoclicker.Pattern
 	StartEnabled       bool
 	GrabDevices        bool
 	PassThroughTrigger bool
+	TriggerMode        autoclicker.TriggerMode
+	LatchThreshold     time.Duration
+	BurstCount         int
+	BurstCooldown      time.Duration
+	MaxCPSWindow       int
+	CatchUp            autoclicker.CatchUpPolicy
+	Ramp               autoclicker.Ramp
+	Ticks              autoclicker.Ticks
+	MaxHold            time.Duration
+	HoldDelay          time.Duration
+	DebouncePress      time.Duration
+	DebounceRelease    time.Duration
+	PauseCodes         []uint16
+	PauseFor           time.Duration
+	PauseWhileTyping   bool
+	TypingPause        time.Duration
+	ToggleGesture      autoclicker.ToggleGesture
+	DoubleTapWindow    time.Duration
+	LongPressThreshold time.Duration
+	PanicCode          uint16
+	PanicAction        autoclicker.PanicAction
+	OnPanic            func(autoclicker.PanicAction)
+	Bindings           []autoclicker.Binding
 }
 
 type Runtime struct {
 	sourceDevices []*evdev.InputDevice
+	triggerPaths  map[string]struct{}
+	togglePaths   map[string]struct{}
 	grabPaths     map[string]struct{}
-	grabEnabled   bool
+	grabRequested bool
+	keyCaps       map[evdev.EvCode]struct{}
+	triggerCaps   map[evdev.EvCode]struct{}
 	service       *autoclicker.Service
 	logger        autoclicker.Logger
 
+	grabMu      sync.Mutex
+	grabEnabled bool
+
 	stopCh    chan struct{}
 	stopOnce  sync.Once
 	readersWG sync.WaitGroup
 
 	captureMu sync.Mutex
 	captureCh chan uint16
+	chords    autoclicker.ChordCapture
 }
 
 type evdevInjector struct {
@@ -103,7 +140,20 @@ func NewRuntime(selection *SourceSelection, cfg RuntimeConfig, logger autoclicke
 		}
 	}
 
-	capabilities := buildUinputCapabilities(selection.Devices, grabPaths, cfg.TriggerCode, cfg.ToggleCode, grabEnabled)
+	outputCode := cfg.OutputCode
+	if outputCode == 0 {
+		outputCode = CodeBTNLeft
+	}
+	triggerCodes := []uint16{cfg.TriggerCode}
+	outputCodes := append([]uint16{outputCode}, cfg.Pattern.Codes()...)
+	for _, binding := range cfg.Bindings {
+		triggerCodes = append(triggerCodes, binding.TriggerCode)
+		if binding.OutputCode != 0 {
+			outputCodes = append(outputCodes, binding.OutputCode)
+		}
+		outputCodes = append(outputCodes, binding.Pattern.Codes()...)
+	}
+	capabilities := buildUinputCapabilities(selection.Devices, grabPaths, triggerCodes, []uint16{cfg.ToggleCode, cfg.PanicCode}, outputCodes, grabEnabled)
 	id := evdev.InputID{
 		BusType: uint16(evdev.BUS_VIRTUAL),
 		Vendor:  0x1,
@@ -121,41 +171,126 @@ func NewRuntime(selection *SourceSelection, cfg RuntimeConfig, logger autoclicke
 	}
 	injector := &evdevInjector{dev: injectorDev}
 
-	service, err := autoclicker.NewService(
-		autoclicker.Config{
-			TriggerCode:        cfg.TriggerCode,
-			ToggleCode:         cfg.ToggleCode,
-			TriggerSources:     selection.TriggerPaths,
-			ToggleSources:      selection.TogglePaths,
-			GrabSources:        grabPaths,
-			GrabEnabled:        grabEnabled,
-			PassThroughTrigger: cfg.PassThroughTrigger,
-			CPS:                cfg.CPS,
-			ClickDown:          cfg.ClickDown,
-			JitterPixels:       cfg.JitterPixels,
-			StartEnabled:       cfg.StartEnabled,
-		},
-		injector,
-		logger,
-	)
-	if err != nil {
-		_ = injector.Close()
-		return nil, err
-	}
-
-	return &Runtime{
+	r := &Runtime{
 		sourceDevices: selection.Devices,
+		triggerPaths:  selection.TriggerPaths,
+		togglePaths:   selection.TogglePaths,
 		grabPaths:     grabPaths,
+		grabRequested: cfg.GrabDevices,
 		grabEnabled:   grabEnabled,
-		service:       service,
+		keyCaps:       codeSet(capabilities[evdev.EV_KEY]),
+		triggerCaps:   triggerSourceCodes(selection),
 		logger:        logger,
 		stopCh:        make(chan struct{}),
-	}, nil
+	}
+	service, err := autoclicker.NewService(r.serviceConfig(cfg, outputCode), injector, logger)
+	if err != nil {
+		_ = injector.Close()
+		return nil, err
+	}
+
+	r.service = service
+	return r, nil
+}
+
+func (r *Runtime) serviceConfig(cfg RuntimeConfig, outputCode uint16) autoclicker.Config {
+	return autoclicker.Config{
+		TriggerCode:        cfg.TriggerCode,
+		ToggleCode:         cfg.ToggleCode,
+		OutputCode:         outputCode,
+		TriggerSources:     r.triggerPaths,
+		ToggleSources:      r.togglePaths,
+		GrabSources:        r.grabPaths,
+		GrabEnabled:        r.GrabEnabled(),
+		PassThroughTrigger: cfg.PassThroughTrigger,
+		CPS:                cfg.CPS,
+		Timing:             cfg.Timing,
+		Curve:              cfg.Curve,
+		ClickDown:          cfg.ClickDown,
+		ClickDownModel:     cfg.ClickDownModel,
+		JitterPixels:       cfg.JitterPixels,
+		Pattern:            cfg.Pattern,
+		StartEnabled:       cfg.StartEnabled,
+		TriggerMode:        cfg.TriggerMode,
+		LatchThreshold:     cfg.LatchThreshold,
+		BurstCount:         cfg.BurstCount,
+		BurstCooldown:      cfg.BurstCooldown,
+		MaxCPSWindow:       cfg.MaxCPSWindow,
+		CatchUp:            cfg.CatchUp,
+		Ramp:               cfg.Ramp,
+		Ticks:              cfg.Ticks,
+		MaxHold:            cfg.MaxHold,
+		HoldDelay:          cfg.HoldDelay,
+		DebouncePress:      cfg.DebouncePress,
+		DebounceRelease:    cfg.DebounceRelease,
+		PauseCodes:         cfg.PauseCodes,
+		PauseFor:           cfg.PauseFor,
+		PauseWhileTyping:   cfg.PauseWhileTyping,
+		TypingPause:        cfg.TypingPause,
+		PauseSources:       r.pauseSources(cfg.PauseCodes, cfg.PauseWhileTyping),
+		ToggleGesture:      cfg.ToggleGesture,
+		DoubleTapWindow:    cfg.DoubleTapWindow,
+		LongPressThreshold: cfg.LongPressThreshold,
+		PanicCode:          cfg.PanicCode,
+		PanicSources:       r.panicSources(cfg.PanicCode),
+		PanicAction:        cfg.PanicAction,
+		OnPanic:            r.onPanic(cfg.OnPanic),
+		Bindings:           cfg.Bindings,
+	}
+}
+
+func (r *Runtime) onPanic(next func(autoclicker.PanicAction)) func(autoclicker.PanicAction) {
+	return func(action autoclicker.PanicAction) {
+		if action == autoclicker.PanicUngrab && r.ungrabDevices() {
+			r.logger.Warn("Panic key pressed, source devices ungrabbed")
+		}
+		if next != nil {
+			next(action)
+		}
+	}
+}
+
+func (r *Runtime) panicSources(code uint16) map[string]struct{} {
+	paths := make(map[string]struct{})
+	if code == 0 {
+		return paths
+	}
+	for _, dev := range r.sourceDevices {
+		if deviceSupportsCode(dev, code) {
+			paths[dev.Path()] = struct{}{}
+		}
+	}
+	return paths
+}
+
+func (r *Runtime) pauseSources(codes []uint16, typing bool) map[string]struct{} {
+	if typing {
+		codes = append(slices.Clone(codes), keyboardProbeCode)
+	}
+	return pauseDevicePaths(r.sourceDevices, codes)
+}
+
+func (r *Runtime) ungrabDevices() bool {
+	r.grabMu.Lock()
+	defer r.grabMu.Unlock()
+	if !r.grabEnabled {
+		return false
+	}
+	r.grabEnabled = false
+	for _, dev := range r.sourceDevices {
+		if _, ok := r.grabPaths[dev.Path()]; ok {
+			_ = dev.Ungrab()
+		}
+	}
+	if r.service != nil {
+		r.service.SetGrabEnabled(false)
+	}
+	return true
 }
 
 func (r *Runtime) Start() error {
 	grabbed := make([]*evdev.InputDevice, 0, len(r.sourceDevices))
-	if r.grabEnabled {
+	if r.GrabEnabled() {
 		for _, dev := range r.sourceDevices {
 			if _, ok := r.grabPaths[dev.Path()]; !ok {
 				continue
@@ -189,14 +324,7 @@ func (r *Runtime) Start() error {
 func (r *Runtime) Stop() {
 	r.stopOnce.Do(func() {
 		close(r.stopCh)
-		if r.grabEnabled {
-			for _, dev := range r.sourceDevices {
-				if _, ok := r.grabPaths[dev.Path()]; !ok {
-					continue
-				}
-				_ = dev.Ungrab()
-			}
-		}
+		r.ungrabDevices()
 		for _, dev := range r.sourceDevices {
 			_ = dev.Close()
 		}
@@ -213,10 +341,30 @@ func (r *Runtime) IsEnabled() bool {
 	return r.service.IsEnabled()
 }
 
+func (r *Runtime) Stats() autoclicker.Stats {
+	return r.service.Stats()
+}
+
+func (r *Runtime) Subscribe(buffer int) (<-chan autoclicker.StateEvent, func()) {
+	return r.service.Subscribe(buffer)
+}
+
 func (r *Runtime) SetCPS(cps float64) error {
 	return r.service.SetCPS(cps)
 }
 
+func (r *Runtime) SetTimingModel(model autoclicker.TimingModel) error {
+	return r.service.SetTimingModel(model)
+}
+
+func (r *Runtime) SetRateCurve(curve autoclicker.RateCurve) {
+	r.service.SetRateCurve(curve)
+}
+
+func (r *Runtime) SetClickDownModel(model autoclicker.ClickDownModel) error {
+	return r.service.SetClickDownModel(model)
+}
+
 func (r *Runtime) SetJitter(pixels int) error {
 	return r.service.SetJitter(pixels)
 }
@@ -229,7 +377,126 @@ func (r *Runtime) SetToggleCode(code uint16) {
 	r.service.SetToggleCode(code)
 }
 
+func (r *Runtime) SetTriggerMode(mode autoclicker.TriggerMode) error {
+	return r.service.SetTriggerMode(mode)
+}
+
+func (r *Runtime) SetBurst(count int, cooldown time.Duration) error {
+	return r.service.SetBurst(count, cooldown)
+}
+
+func (r *Runtime) SetRamp(ramp autoclicker.Ramp) error {
+	return r.service.SetRamp(ramp)
+}
+
+func (r *Runtime) SetTicks(ticks autoclicker.Ticks) error {
+	return r.service.SetTicks(ticks)
+}
+
+func (r *Runtime) SetMaxHold(d time.Duration) error {
+	return r.service.SetMaxHold(d)
+}
+
+func (r *Runtime) SetHoldDelay(d time.Duration) error {
+	return r.service.SetHoldDelay(d)
+}
+
+func (r *Runtime) SetMaxCPSWindow(limit int) error {
+	return r.service.SetMaxCPSWindow(limit)
+}
+
+func (r *Runtime) SetDebounce(press, release time.Duration) error {
+	return r.service.SetDebounce(press, release)
+}
+
+func (r *Runtime) SetOutputCode(code uint16) error {
+	if _, ok := r.keyCaps[evdev.EvCode(code)]; !ok {
+		return fmt.Errorf("virtual device cannot emit %s; restart required", FormatCodeName(code))
+	}
+	r.service.SetOutputCode(code)
+	return nil
+}
+
+func (r *Runtime) SetPattern(pattern autoclicker.Pattern) error {
+	if err := r.checkOutputCaps(pattern.Codes()); err != nil {
+		return err
+	}
+	return r.service.SetPattern(pattern)
+}
+
+func (r *Runtime) SetBindings(bindings []autoclicker.Binding) error {
+	if err := r.checkBindingCaps(bindings); err != nil {
+		return err
+	}
+	return r.service.SetBindings(bindings)
+}
+
+func (r *Runtime) checkBindingCaps(bindings []autoclicker.Binding) error {
+	for _, binding := range bindings {
+		if _, ok := r.triggerCaps[evdev.EvCode(autoclicker.ChordKey(binding.TriggerCode))]; !ok {
+			return fmt.Errorf("no opened source exposes trigger %s; restart required", FormatCodeName(binding.TriggerCode))
+		}
+		output := binding.OutputCode
+		if output == 0 {
+			output = CodeBTNLeft
+		}
+		if err := r.checkOutputCaps(append([]uint16{output}, binding.Pattern.Codes()...)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *Runtime) checkOutputCaps(codes []uint16) error {
+	for _, code := range codes {
+		if _, ok := r.keyCaps[evdev.EvCode(code)]; !ok {
+			return fmt.Errorf("virtual device cannot emit %s; restart required", FormatCodeName(code))
+		}
+	}
+	return nil
+}
+
+func (r *Runtime) UpdateConfig(cfg RuntimeConfig) error {
+	if cfg.GrabDevices != r.grabRequested {
+		return fmt.Errorf("grab mode cannot change in place; restart required")
+	}
+	outputCode := cfg.OutputCode
+	if outputCode == 0 {
+		outputCode = CodeBTNLeft
+	}
+	if err := r.checkOutputCaps(append([]uint16{outputCode}, cfg.Pattern.Codes()...)); err != nil {
+		return err
+	}
+	if err := r.checkBindingCaps(cfg.Bindings); err != nil {
+		return err
+	}
+	if cfg.PanicCode != 0 && len(r.panicSources(cfg.PanicCode)) == 0 {
+		return fmt.Errorf("no opened source exposes panic %s; restart required", FormatCodeName(cfg.PanicCode))
+	}
+	selection := SourceSelection{Devices: r.sourceDevices}
+	for _, code := range cfg.PauseCodes {
+		if !selection.exposesAnyKey([]uint16{code}) {
+			return fmt.Errorf("no opened source exposes pause %s; restart required", FormatCodeName(code))
+		}
+	}
+	if cfg.PauseWhileTyping && !selection.exposesAnyKey([]uint16{keyboardProbeCode}) {
+		return fmt.Errorf("no opened source is a keyboard to pause while typing; restart required")
+	}
+	chords := []uint16{cfg.TriggerCode, cfg.ToggleCode}
+	for _, binding := range cfg.Bindings {
+		chords = append(chords, binding.TriggerCode)
+	}
+	for _, code := range chords {
+		if !selection.exposesModifiers(autoclicker.ChordModifiers(code)) {
+			return fmt.Errorf("no opened source exposes the modifiers of %s; restart required", FormatCodeName(code))
+		}
+	}
+	return r.service.UpdateConfig(r.serviceConfig(cfg, outputCode))
+}
+
 func (r *Runtime) GrabEnabled() bool {
+	r.grabMu.Lock()
+	defer r.grabMu.Unlock()
 	return r.grabEnabled
 }
 
@@ -293,14 +560,15 @@ func (r *Runtime) readLoop(dev *evdev.InputDevice) {
 		}
 
 		for _, event := range events {
-			if event.Type == evdev.EV_KEY && event.Value == 1 {
-				r.publishCapturedCode(uint16(event.Code))
-			}
-			if !r.service.SubmitEvent(path, autoclicker.Event{
+			input := autoclicker.Event{
 				Type:  uint16(event.Type),
 				Code:  uint16(event.Code),
 				Value: event.Value,
-			}) {
+			}
+			if code, ok := r.chords.Feed(path, input); ok {
+				r.publishCapturedCode(code)
+			}
+			if !r.service.SubmitEvent(path, input) {
 				return
 			}
 		}
@@ -343,11 +611,34 @@ func (r *Runtime) sleepWithStop(duration time.Duration) bool {
 func buildUinputCapabilities(
 	sourceDevices []*evdev.InputDevice,
 	grabPaths map[string]struct{},
-	triggerCode uint16,
-	toggleCode uint16,
+	triggerCodes []uint16,
+	controlCodes []uint16,
+	outputCodes []uint16,
 	grabEnabled bool,
 ) map[evdev.EvType][]evdev.EvCode {
-	keyCodes := map[evdev.EvCode]struct{}{evdev.BTN_LEFT: {}}
+	// Common mouse buttons are always declared so the output can be switched
+	// between them without recreating the device.
+	keyCodes := map[evdev.EvCode]struct{}{
+		evdev.BTN_LEFT:   {},
+		evdev.BTN_RIGHT:  {},
+		evdev.BTN_MIDDLE: {},
+		evdev.BTN_SIDE:   {},
+		evdev.BTN_EXTRA:  {},
+	}
+	outputSet := make(map[uint16]struct{}, len(outputCodes))
+	for _, code := range outputCodes {
+		keyCodes[evdev.EvCode(code)] = struct{}{}
+		outputSet[code] = struct{}{}
+	}
+	triggerSet := make(map[uint16]struct{}, len(triggerCodes))
+	for _, code := range triggerCodes {
+		triggerSet[code] = struct{}{}
+	}
+	// The toggle and panic codes are consumed and never passed through.
+	controlSet := make(map[uint16]struct{}, len(controlCodes))
+	for _, code := range controlCodes {
+		controlSet[code] = struct{}{}
+	}
 	relCodes := map[evdev.EvCode]struct{}{
 		evdev.REL_X: {},
 		evdev.REL_Y: {},
@@ -359,10 +650,12 @@ func buildUinputCapabilities(
 				continue
 			}
 			for _, code := range dev.CapableEvents(evdev.EV_KEY) {
-				if uint16(code) == triggerCode && triggerCode != uint16(evdev.BTN_LEFT) {
-					continue
+				if _, isTrigger := triggerSet[uint16(code)]; isTrigger && code != evdev.BTN_LEFT {
+					if _, isOutput := outputSet[uint16(code)]; !isOutput {
+						continue
+					}
 				}
-				if uint16(code) == toggleCode {
+				if _, isControl := controlSet[uint16(code)]; isControl {
 					continue
 				}
 				keyCodes[code] = struct{}{}
@@ -382,6 +675,27 @@ func buildUinputCapabilities(
 	return capabilities
 }
 
+func triggerSourceCodes(selection *SourceSelection) map[evdev.EvCode]struct{} {
+	codes := make(map[evdev.EvCode]struct{})
+	for _, dev := range selection.Devices {
+		if _, ok := selection.TriggerPaths[dev.Path()]; !ok {
+			continue
+		}
+		for _, code := range dev.CapableEvents(evdev.EV_KEY) {
+			codes[code] = struct{}{}
+		}
+	}
+	return codes
+}
+
+func codeSet(codes []evdev.EvCode) map[evdev.EvCode]struct{} {
+	set := make(map[evdev.EvCode]struct{}, len(codes))
+	for _, code := range codes {
+		set[code] = struct{}{}
+	}
+	return set
+}
+
 func sortedCodes(values map[evdev.EvCode]struct{}) []evdev.EvCode {
 	codes := make([]evdev.EvCode, 0, len(values))
 	for code := range values {
@@ -5,6 +5,8 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+
+	"clicker/internal/core/autoclicker"
 )
 
 const (
@@ -678,6 +680,10 @@ func init() {
 }
 
 func ParseCode(value string) (uint16, error) {
+	return autoclicker.ParseChord(value, parseKeyCode)
+}
+
+func parseKeyCode(value string) (uint16, error) {
 	raw := strings.ToUpper(strings.TrimSpace(value))
 	if raw == "" {
 		return 0, fmt.Errorf("trigger code is empty")
@@ -697,6 +703,10 @@ func ParseCode(value string) (uint16, error) {
 }
 
 func FormatCodeName(code uint16) string {
+	return autoclicker.FormatChord(code, formatKeyName)
+}
+
+func formatKeyName(code uint16) string {
 	if name, ok := codeToName[code]; ok {
 		return name
 	}
@@ -1,6 +1,10 @@
 package wininput
 
-import "testing"
+import (
+	"testing"
+
+	"clicker/internal/core/autoclicker"
+)
 
 func TestParseAndFormatMouseCodes(t *testing.T) {
 	tests := []struct {
@@ -28,6 +32,22 @@ func TestParseAndFormatMouseCodes(t *testing.T) {
 	}
 }
 
+func TestParseAndFormatChords(t *testing.T) {
+	code, err := ParseCode("ctrl+shift+KEY_F8")
+	if err != nil {
+		t.Fatalf("ParseCode() returned error: %v", err)
+	}
+	if want := autoclicker.Chord(codeKEYF8, autoclicker.ModCtrl|autoclicker.ModShift); code != want {
+		t.Fatalf("ParseCode(ctrl+shift+KEY_F8)=%d, want %d", code, want)
+	}
+	if name := FormatCodeName(code); name != "CTRL+SHIFT+KEY_F8" {
+		t.Fatalf("FormatCodeName(chord)=%q, want CTRL+SHIFT+KEY_F8", name)
+	}
+	if _, err := ParseCode("HYPER+KEY_F8"); err == nil {
+		t.Fatalf("ParseCode(HYPER+KEY_F8) accepted an unknown modifier")
+	}
+}
+
 func TestCodeFromVKMappings(t *testing.T) {
 	if code, ok := CodeFromVK(vkA, 0, 0); !ok || code != codeKEYA {
 		t.Fatalf("CodeFromVK(vkA)=%d,%v, want %d,true", code, ok, codeKEYA)
@@ -27,10 +27,30 @@ func (r *Runtime) IsEnabled() bool {
 	return false
 }
 
+func (r *Runtime) Stats() autoclicker.Stats {
+	return autoclicker.Stats{}
+}
+
+func (r *Runtime) Subscribe(buffer int) (<-chan autoclicker.StateEvent, func()) {
+	events := make(chan autoclicker.StateEvent)
+	close(events)
+	return events, func() {}
+}
+
 func (r *Runtime) SetCPS(cps float64) error {
 	return fmt.Errorf("windows input runtime is only available on Windows")
 }
 
+func (r *Runtime) SetTimingModel(model autoclicker.TimingModel) error {
+	return fmt.Errorf("windows input runtime is only available on Windows")
+}
+
+func (r *Runtime) SetRateCurve(curve autoclicker.RateCurve) {}
+
+func (r *Runtime) SetClickDownModel(model autoclicker.ClickDownModel) error {
+	return fmt.Errorf("windows input runtime is only available on Windows")
+}
+
 func (r *Runtime) SetJitter(pixels int) error {
 	return fmt.Errorf("windows input runtime is only available on Windows")
 }
@@ -39,6 +59,54 @@ func (r *Runtime) SetTriggerCode(code uint16) {}
 
 func (r *Runtime) SetToggleCode(code uint16) {}
 
+func (r *Runtime) SetTriggerMode(mode autoclicker.TriggerMode) error {
+	return fmt.Errorf("windows input runtime is only available on Windows")
+}
+
+func (r *Runtime) SetBurst(count int, cooldown time.Duration) error {
+	return fmt.Errorf("windows input runtime is only available on Windows")
+}
+
+func (r *Runtime) SetRamp(ramp autoclicker.Ramp) error {
+	return fmt.Errorf("windows input runtime is only available on Windows")
+}
+
+func (r *Runtime) SetPattern(pattern autoclicker.Pattern) error {
+	return fmt.Errorf("windows input runtime is only available on Windows")
+}
+
+func (r *Runtime) SetTicks(ticks autoclicker.Ticks) error {
+	return fmt.Errorf("windows input runtime is only available on Windows")
+}
+
+func (r *Runtime) SetMaxHold(d time.Duration) error {
+	return fmt.Errorf("windows input runtime is only available on Windows")
+}
+
+func (r *Runtime) SetHoldDelay(d time.Duration) error {
+	return fmt.Errorf("windows input runtime is only available on Windows")
+}
+
+func (r *Runtime) SetMaxCPSWindow(limit int) error {
+	return fmt.Errorf("windows input runtime is only available on Windows")
+}
+
+func (r *Runtime) SetDebounce(press, release time.Duration) error {
+	return fmt.Errorf("windows input runtime is only available on Windows")
+}
+
+func (r *Runtime) SetOutputCode(code uint16) error {
+	return fmt.Errorf("windows input runtime is only available on Windows")
+}
+
+func (r *Runtime) UpdateConfig(cfg RuntimeConfig) error {
+	return fmt.Errorf("windows input runtime is only available on Windows")
+}
+
+func (r *Runtime) SetBindings(bindings []autoclicker.Binding) error {
+	return fmt.Errorf("windows input runtime is only available on Windows")
+}
+
 func (r *Runtime) CaptureNextKeyCode(timeout time.Duration) (uint16, error) {
 	return 0, fmt.Errorf("windows input runtime is only available on Windows")
 }
@@ -50,3 +118,7 @@ func ListInputDevices() ([]DeviceInfo, error) {
 func CaptureNextKeyCode(timeout time.Duration) (uint16, error) {
 	return 0, fmt.Errorf("windows input runtime is only available on Windows")
 }
+
+func WatchKey(code uint16, stop <-chan struct{}, fn func(down bool, at time.Time)) error {
+	return fmt.Errorf("windows input runtime is only available on Windows")
+}
@@ -39,11 +39,19 @@ const (
 	llkhfInjected        = 0x00000010
 	llkhfLowerILInjected = 0x00000002
 
-	inputMouse           = 0
-	mouseeventfMove      = 0x0001
-	mouseeventfLeftDown  = 0x0002
-	mouseeventfLeftUp    = 0x0004
-	globalSourceIdentity = "windows-global"
+	inputMouse            = 0
+	inputKeyboard         = 1
+	mouseeventfMove       = 0x0001
+	mouseeventfLeftDown   = 0x0002
+	mouseeventfLeftUp     = 0x0004
+	mouseeventfRightDown  = 0x0008
+	mouseeventfRightUp    = 0x0010
+	mouseeventfMiddleDown = 0x0020
+	mouseeventfMiddleUp   = 0x0040
+	mouseeventfXDown      = 0x0080
+	mouseeventfXUp        = 0x0100
+	keyeventfKeyUp        = 0x0002
+	globalSourceIdentity  = "windows-global"
 )
 
 var (
@@ -109,11 +117,55 @@ type mouseInput struct {
 	DwExtraInfo uintptr
 }
 
+type keyboardInput struct {
+	WVk         uint16
+	WScan       uint16
+	DwFlags     uint32
+	Time        uint32
+	DwExtraInfo uintptr
+}
+
 type input struct {
 	Type uint32
 	Mi   mouseInput
 }
 
+func newKeyboardInput(ki keyboardInput) input {
+	in := input{Type: inputKeyboard}
+	*(*keyboardInput)(unsafe.Pointer(&in.Mi)) = ki
+	return in
+}
+
+func buttonInputFlags(code uint16, down bool) (uint32, uint32, bool) {
+	var downFlag, upFlag, data uint32
+	switch code {
+	case CodeBTNLeft:
+		downFlag, upFlag = mouseeventfLeftDown, mouseeventfLeftUp
+	case CodeBTNRight:
+		downFlag, upFlag = mouseeventfRightDown, mouseeventfRightUp
+	case CodeBTNMiddle:
+		downFlag, upFlag = mouseeventfMiddleDown, mouseeventfMiddleUp
+	case CodeBTNSide:
+		downFlag, upFlag, data = mouseeventfXDown, mouseeventfXUp, xButton1
+	case CodeBTNExtra:
+		downFlag, upFlag, data = mouseeventfXDown, mouseeventfXUp, xButton2
+	default:
+		return 0, 0, false
+	}
+	if down {
+		return downFlag, data, true
+	}
+	return upFlag, data, true
+}
+
+func outputSupported(code uint16) bool {
+	if _, _, ok := buttonInputFlags(code, true); ok {
+		return true
+	}
+	_, ok := CodeToVK(code)
+	return ok
+}
+
 type windowsInjector struct{}
 
 func (i *windowsInjector) WriteEvents(events ...autoclicker.Event) error {
@@ -151,27 +203,35 @@ func (i *windowsInjector) WriteEvents(events ...autoclicker.Event) error {
 				flushMove()
 			}
 		case autoclicker.EventTypeKey:
-			if event.Code != autoclicker.LeftButtonCode {
+			if event.Value != 0 && event.Value != 1 {
 				continue
 			}
+			down := event.Value == 1
 			flushMove()
 
-			var flags uint32
-			switch event.Value {
-			case 1:
-				flags = mouseeventfLeftDown
-			case 0:
-				flags = mouseeventfLeftUp
-			default:
+			if flags, data, ok := buttonInputFlags(event.Code, down); ok {
+				inputs = append(inputs, input{
+					Type: inputMouse,
+					Mi: mouseInput{
+						MouseData: data,
+						DwFlags:   flags,
+					},
+				})
 				continue
 			}
 
-			inputs = append(inputs, input{
-				Type: inputMouse,
-				Mi: mouseInput{
-					DwFlags: flags,
-				},
-			})
+			vk, ok := CodeToVK(event.Code)
+			if !ok {
+				return fmt.Errorf("unsupported windows output %s", FormatCodeName(event.Code))
+			}
+			var flags uint32
+			if !down {
+				flags = keyeventfKeyUp
+			}
+			inputs = append(inputs, newKeyboardInput(keyboardInput{
+				WVk:     uint16(vk),
+				DwFlags: flags,
+			}))
 		default:
 			continue
 		}
@@ -213,6 +273,7 @@ type Runtime struct {
 
 	captureMu sync.Mutex
 	captureCh chan uint16
+	chords    autoclicker.ChordCapture
 }
 
 func NewRuntime(cfg RuntimeConfig, logger autoclicker.Logger) (*Runtime, error) {
@@ -220,22 +281,11 @@ func NewRuntime(cfg RuntimeConfig, logger autoclicker.Logger) (*Runtime, error)
 		return nil, fmt.Errorf("logger is nil")
 	}
 
-	service, err := autoclicker.NewService(
-		autoclicker.Config{
-			TriggerCode:    cfg.TriggerCode,
-			ToggleCode:     cfg.ToggleCode,
-			TriggerSources: map[string]struct{}{globalSourceIdentity: {}},
-			ToggleSources:  map[string]struct{}{globalSourceIdentity: {}},
-			GrabSources:    nil,
-			GrabEnabled:    false,
-			CPS:            cfg.CPS,
-			ClickDown:      cfg.ClickDown,
-			JitterPixels:   cfg.JitterPixels,
-			StartEnabled:   cfg.StartEnabled,
-		},
-		&windowsInjector{},
-		logger,
-	)
+	serviceCfg, err := serviceConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+	service, err := autoclicker.NewService(serviceCfg, &windowsInjector{}, logger)
 	if err != nil {
 		return nil, err
 	}
@@ -248,6 +298,63 @@ func NewRuntime(cfg RuntimeConfig, logger autoclicker.Logger) (*Runtime, error)
 	}, nil
 }
 
+func serviceConfig(cfg RuntimeConfig) (autoclicker.Config, error) {
+	outputCode := cfg.OutputCode
+	if outputCode == 0 {
+		outputCode = CodeBTNLeft
+	}
+	if !outputSupported(outputCode) {
+		return autoclicker.Config{}, fmt.Errorf("unsupported windows output %s", FormatCodeName(outputCode))
+	}
+	if err := validatePatternOutputs(cfg.Pattern); err != nil {
+		return autoclicker.Config{}, err
+	}
+	if err := validateBindingOutputs(cfg.Bindings); err != nil {
+		return autoclicker.Config{}, err
+	}
+
+	return autoclicker.Config{
+		TriggerCode:        cfg.TriggerCode,
+		ToggleCode:         cfg.ToggleCode,
+		OutputCode:         outputCode,
+		TriggerSources:     map[string]struct{}{globalSourceIdentity: {}},
+		ToggleSources:      map[string]struct{}{globalSourceIdentity: {}},
+		GrabSources:        nil,
+		GrabEnabled:        false,
+		CPS:                cfg.CPS,
+		Timing:             cfg.Timing,
+		Curve:              cfg.Curve,
+		ClickDown:          cfg.ClickDown,
+		ClickDownModel:     cfg.ClickDownModel,
+		JitterPixels:       cfg.JitterPixels,
+		Pattern:            cfg.Pattern,
+		StartEnabled:       cfg.StartEnabled,
+		TriggerMode:        cfg.TriggerMode,
+		LatchThreshold:     cfg.LatchThreshold,
+		BurstCount:         cfg.BurstCount,
+		BurstCooldown:      cfg.BurstCooldown,
+		MaxCPSWindow:       cfg.MaxCPSWindow,
+		CatchUp:            cfg.CatchUp,
+		Ramp:               cfg.Ramp,
+		Ticks:              cfg.Ticks,
+		MaxHold:            cfg.MaxHold,
+		HoldDelay:          cfg.HoldDelay,
+		DebouncePress:      cfg.DebouncePress,
+		DebounceRelease:    cfg.DebounceRelease,
+		PauseCodes:         cfg.PauseCodes,
+		PauseFor:           cfg.PauseFor,
+		PauseWhileTyping:   cfg.PauseWhileTyping,
+		TypingPause:        cfg.TypingPause,
+		ToggleGesture:      cfg.ToggleGesture,
+		DoubleTapWindow:    cfg.DoubleTapWindow,
+		LongPressThreshold: cfg.LongPressThreshold,
+		PanicCode:          cfg.PanicCode,
+		PanicAction:        cfg.PanicAction,
+		OnPanic:            cfg.OnPanic,
+		Bindings:           cfg.Bindings,
+	}, nil
+}
+
 func (r *Runtime) Start() error {
 	if !activeRuntime.CompareAndSwap(nil, r) {
 		return fmt.Errorf("windows runtime is already active")
@@ -297,10 +404,30 @@ func (r *Runtime) IsEnabled() bool {
 	return r.service.IsEnabled()
 }
 
+func (r *Runtime) Stats() autoclicker.Stats {
+	return r.service.Stats()
+}
+
+func (r *Runtime) Subscribe(buffer int) (<-chan autoclicker.StateEvent, func()) {
+	return r.service.Subscribe(buffer)
+}
+
 func (r *Runtime) SetCPS(cps float64) error {
 	return r.service.SetCPS(cps)
 }
 
+func (r *Runtime) SetTimingModel(model autoclicker.TimingModel) error {
+	return r.service.SetTimingModel(model)
+}
+
+func (r *Runtime) SetRateCurve(curve autoclicker.RateCurve) {
+	r.service.SetRateCurve(curve)
+}
+
+func (r *Runtime) SetClickDownModel(model autoclicker.ClickDownModel) error {
+	return r.service.SetClickDownModel(model)
+}
+
 func (r *Runtime) SetJitter(pixels int) error {
 	return r.service.SetJitter(pixels)
 }
@@ -313,6 +440,89 @@ func (r *Runtime) SetToggleCode(code uint16) {
 	r.service.SetToggleCode(code)
 }
 
+func (r *Runtime) SetTriggerMode(mode autoclicker.TriggerMode) error {
+	return r.service.SetTriggerMode(mode)
+}
+
+func (r *Runtime) SetBurst(count int, cooldown time.Duration) error {
+	return r.service.SetBurst(count, cooldown)
+}
+
+func (r *Runtime) SetRamp(ramp autoclicker.Ramp) error {
+	return r.service.SetRamp(ramp)
+}
+
+func (r *Runtime) SetTicks(ticks autoclicker.Ticks) error {
+	return r.service.SetTicks(ticks)
+}
+
+func (r *Runtime) SetMaxHold(d time.Duration) error {
+	return r.service.SetMaxHold(d)
+}
+
+func (r *Runtime) SetHoldDelay(d time.Duration) error {
+	return r.service.SetHoldDelay(d)
+}
+
+func (r *Runtime) SetMaxCPSWindow(limit int) error {
+	return r.service.SetMaxCPSWindow(limit)
+}
+
+func (r *Runtime) SetDebounce(press, release time.Duration) error {
+	return r.service.SetDebounce(press, release)
+}
+
+func (r *Runtime) SetOutputCode(code uint16) error {
+	if !outputSupported(code) {
+		return fmt.Errorf("unsupported windows output %s", FormatCodeName(code))
+	}
+	r.service.SetOutputCode(code)
+	return nil
+}
+
+func (r *Runtime) SetPattern(pattern autoclicker.Pattern) error {
+	if err := validatePatternOutputs(pattern); err != nil {
+		return err
+	}
+	return r.service.SetPattern(pattern)
+}
+
+func (r *Runtime) SetBindings(bindings []autoclicker.Binding) error {
+	if err := validateBindingOutputs(bindings); err != nil {
+		return err
+	}
+	return r.service.SetBindings(bindings)
+}
+
+func (r *Runtime) UpdateConfig(cfg RuntimeConfig) error {
+	serviceCfg, err := serviceConfig(cfg)
+	if err != nil {
+		return err
+	}
+	return r.service.UpdateConfig(serviceCfg)
+}
+
+func validateBindingOutputs(bindings []autoclicker.Binding) error {
+	for _, binding := range bindings {
+		if binding.OutputCode != 0 && !outputSupported(binding.OutputCode) {
+			return fmt.Errorf("unsupported windows output %s", FormatCodeName(binding.OutputCode))
+		}
+		if err := validatePatternOutputs(binding.Pattern); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func validatePatternOutputs(pattern autoclicker.Pattern) error {
+	for _, code := range pattern.Codes() {
+		if !outputSupported(code) {
+			return fmt.Errorf("unsupported windows output %s", FormatCodeName(code))
+		}
+	}
+	return nil
+}
+
 func (r *Runtime) CaptureNextKeyCode(timeout time.Duration) (uint16, error) {
 	if timeout <= 0 {
 		timeout = 10 * time.Second
@@ -459,14 +669,11 @@ func (r *Runtime) handleMouseHook(wParam uintptr, lParam uintptr) {
 		return
 	}
 
-	if value == 1 {
-		r.publishCapturedCode(code)
+	input := autoclicker.Event{Type: autoclicker.EventTypeKey, Code: code, Value: value}
+	if captured, ok := r.chords.Feed(globalSourceIdentity, input); ok {
+		r.publishCapturedCode(captured)
 	}
-	_ = r.service.SubmitEvent(globalSourceIdentity, autoclicker.Event{
-		Type:  autoclicker.EventTypeKey,
-		Code:  code,
-		Value: value,
-	})
+	_ = r.service.SubmitEvent(globalSourceIdentity, input)
 }
 
 func (r *Runtime) handleKeyboardHook(wParam uintptr, lParam uintptr) {
@@ -494,14 +701,11 @@ func (r *Runtime) handleKeyboardHook(wParam uintptr, lParam uintptr) {
 		return
 	}
 
-	if value == 1 {
-		r.publishCapturedCode(code)
+	input := autoclicker.Event{Type: autoclicker.EventTypeKey, Code: code, Value: value}
+	if captured, ok := r.chords.Feed(globalSourceIdentity, input); ok {
+		r.publishCapturedCode(captured)
 	}
-	_ = r.service.SubmitEvent(globalSourceIdentity, autoclicker.Event{
-		Type:  autoclicker.EventTypeKey,
-		Code:  code,
-		Value: value,
-	})
+	_ = r.service.SubmitEvent(globalSourceIdentity, input)
 }
 
 func xButtonCode(mouseData uint32) uint16 {
@@ -558,13 +762,21 @@ func CaptureNextKeyCode(timeout time.Duration) (uint16, error) {
 	ticker := time.NewTicker(2 * time.Millisecond)
 	defer ticker.Stop()
 
+	var chords autoclicker.ChordCapture
 	for {
 		for _, code := range codes {
 			down := isCodeDown(code)
 			wasDown := state[code]
 			state[code] = down
-			if down && !wasDown {
-				return code, nil
+			if down == wasDown {
+				continue
+			}
+			event := autoclicker.Event{Type: autoclicker.EventTypeKey, Code: code}
+			if down {
+				event.Value = 1
+			}
+			if captured, ok := chords.Feed(globalSourceIdentity, event); ok {
+				return captured, nil
 			}
 		}
 
@@ -576,6 +788,27 @@ func CaptureNextKeyCode(timeout time.Duration) (uint16, error) {
 	}
 }
 
+func WatchKey(code uint16, stop <-chan struct{}, fn func(down bool, at time.Time)) error {
+	if _, ok := CodeToVK(code); !ok {
+		return fmt.Errorf("code %d has no windows virtual key", code)
+	}
+	ticker := time.NewTicker(2 * time.Millisecond)
+	defer ticker.Stop()
+
+	wasDown := isCodeDown(code)
+	for {
+		select {
+		case <-stop:
+			return nil
+		case at := <-ticker.C:
+			if down := isCodeDown(code); down != wasDown {
+				wasDown = down
+				fn(down, at)
+			}
+		}
+	}
+}
+
 func isCodeDown(code uint16) bool {
 	vk, ok := CodeToVK(code)
 	if !ok {
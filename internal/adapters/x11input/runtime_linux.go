@@ -25,6 +25,23 @@ type codeBinding struct {
 	buttons  []xproto.Button
 }
 
+type keyGrab struct {
+	key  xproto.Keycode
+	mods uint16
+}
+
+type buttonGrab struct {
+	button xproto.Button
+	mods   uint16
+}
+
+var xModifierMasks = map[autoclicker.Modifiers]uint16{
+	autoclicker.ModCtrl:  xproto.ModMaskControl,
+	autoclicker.ModShift: xproto.ModMaskShift,
+	autoclicker.ModAlt:   xproto.ModMask1,
+	autoclicker.ModMeta:  xproto.ModMask4,
+}
+
 type Runtime struct {
 	xu      *xgbutil.XUtil
 	conn    *xgb.Conn
@@ -36,21 +53,31 @@ type Runtime struct {
 	mu             sync.RWMutex
 	triggerCode    uint16
 	toggleCode     uint16
+	panicCode      uint16
+	bindings       []autoclicker.Binding
 	triggerBinding codeBinding
 	toggleBinding  codeBinding
 	keyToCode      map[xproto.Keycode]uint16
 	buttonToCode   map[xproto.Button]uint16
 
-	grabbedKeys    []xproto.Keycode
-	grabbedButtons []xproto.Button
+	grabbedKeys    []keyGrab
+	grabbedButtons []buttonGrab
+
+	modifiers autoclicker.Modifiers
 
-	injectMu sync.Mutex
+	injectMu      sync.Mutex
+	outputTargets map[uint16]outputTarget
 
 	stopOnce sync.Once
 	stopCh   chan struct{}
 	doneCh   chan struct{}
 }
 
+type outputTarget struct {
+	isButton bool
+	detail   byte
+}
+
 type x11Injector struct {
 	r *Runtime
 }
@@ -111,19 +138,24 @@ func (i *x11Injector) WriteEvents(events ...autoclicker.Event) error {
 				}
 			}
 		case autoclicker.EventTypeKey:
-			if event.Code != autoclicker.LeftButtonCode {
-				continue
+			target, err := i.r.outputTargetLocked(event.Code)
+			if err != nil {
+				return err
 			}
 			if err := flushMove(); err != nil {
 				return err
 			}
 
 			var eventType byte
-			switch event.Value {
-			case 1:
+			switch {
+			case event.Value == 1 && target.isButton:
 				eventType = xproto.ButtonPress
-			case 0:
+			case event.Value == 0 && target.isButton:
 				eventType = xproto.ButtonRelease
+			case event.Value == 1:
+				eventType = xproto.KeyPress
+			case event.Value == 0:
+				eventType = xproto.KeyRelease
 			default:
 				continue
 			}
@@ -131,7 +163,7 @@ func (i *x11Injector) WriteEvents(events ...autoclicker.Event) error {
 			if err := xtest.FakeInputChecked(
 				i.r.conn,
 				eventType,
-				byte(xproto.ButtonIndex1),
+				target.detail,
 				xproto.TimeCurrentTime,
 				i.r.rootWin,
 				0,
@@ -178,37 +210,32 @@ func NewRuntime(cfg RuntimeConfig, logger autoclicker.Logger) (*Runtime, error)
 	keybind.Initialize(xu)
 
 	r := &Runtime{
-		xu:      xu,
-		conn:    conn,
-		rootWin: xu.RootWin(),
-		logger:  logger,
-		stopCh:  make(chan struct{}),
-		doneCh:  make(chan struct{}),
-	}
-
-	service, err := autoclicker.NewService(
-		autoclicker.Config{
-			TriggerCode:    cfg.TriggerCode,
-			ToggleCode:     cfg.ToggleCode,
-			TriggerSources: map[string]struct{}{"x11-global": {}},
-			ToggleSources:  map[string]struct{}{"x11-global": {}},
-			GrabSources:    nil,
-			GrabEnabled:    false,
-			CPS:            cfg.CPS,
-			ClickDown:      cfg.ClickDown,
-			JitterPixels:   cfg.JitterPixels,
-			StartEnabled:   cfg.StartEnabled,
-		},
-		&x11Injector{r: r},
-		logger,
-	)
+		xu:            xu,
+		conn:          conn,
+		rootWin:       xu.RootWin(),
+		logger:        logger,
+		outputTargets: make(map[uint16]outputTarget),
+		stopCh:        make(chan struct{}),
+		doneCh:        make(chan struct{}),
+	}
+
+	outputCode := cfg.OutputCode
+	if outputCode == 0 {
+		outputCode = linuxinput.CodeBTNLeft
+	}
+	if err := r.validateOutputs(outputCode, cfg.Pattern, cfg.Bindings); err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	service, err := autoclicker.NewService(r.serviceConfig(cfg, outputCode), &x11Injector{r: r}, logger)
 	if err != nil {
 		conn.Close()
 		return nil, err
 	}
 	r.service = service
 
-	if err := r.applyBindings(cfg.TriggerCode, cfg.ToggleCode); err != nil {
+	if err := r.applyBindings(cfg.TriggerCode, cfg.Bindings, cfg.ToggleCode, cfg.PanicCode); err != nil {
 		r.service.Stop()
 		conn.Close()
 		return nil, err
@@ -217,6 +244,61 @@ func NewRuntime(cfg RuntimeConfig, logger autoclicker.Logger) (*Runtime, error)
 	return r, nil
 }
 
+func (r *Runtime) serviceConfig(cfg RuntimeConfig, outputCode uint16) autoclicker.Config {
+	return autoclicker.Config{
+		TriggerCode:        cfg.TriggerCode,
+		ToggleCode:         cfg.ToggleCode,
+		OutputCode:         outputCode,
+		TriggerSources:     map[string]struct{}{"x11-global": {}},
+		ToggleSources:      map[string]struct{}{"x11-global": {}},
+		GrabSources:        nil,
+		GrabEnabled:        false,
+		CPS:                cfg.CPS,
+		Timing:             cfg.Timing,
+		Curve:              cfg.Curve,
+		ClickDown:          cfg.ClickDown,
+		ClickDownModel:     cfg.ClickDownModel,
+		JitterPixels:       cfg.JitterPixels,
+		Pattern:            cfg.Pattern,
+		StartEnabled:       cfg.StartEnabled,
+		TriggerMode:        cfg.TriggerMode,
+		LatchThreshold:     cfg.LatchThreshold,
+		BurstCount:         cfg.BurstCount,
+		BurstCooldown:      cfg.BurstCooldown,
+		MaxCPSWindow:       cfg.MaxCPSWindow,
+		CatchUp:            cfg.CatchUp,
+		Ramp:               cfg.Ramp,
+		Ticks:              cfg.Ticks,
+		MaxHold:            cfg.MaxHold,
+		HoldDelay:          cfg.HoldDelay,
+		DebouncePress:      cfg.DebouncePress,
+		DebounceRelease:    cfg.DebounceRelease,
+		ToggleGesture:      cfg.ToggleGesture,
+		DoubleTapWindow:    cfg.DoubleTapWindow,
+		LongPressThreshold: cfg.LongPressThreshold,
+		PanicCode:          cfg.PanicCode,
+		PanicAction:        cfg.PanicAction,
+		OnPanic:            r.onPanic(cfg.OnPanic),
+		Bindings:           cfg.Bindings,
+	}
+}
+
+func (r *Runtime) onPanic(next func(autoclicker.PanicAction)) func(autoclicker.PanicAction) {
+	return func(action autoclicker.PanicAction) {
+		if action == autoclicker.PanicUngrab {
+			r.mu.Lock()
+			r.ungrabAllLocked()
+			r.keyToCode = nil
+			r.buttonToCode = nil
+			r.mu.Unlock()
+			r.logger.Warn("Panic key pressed, X11 grabs released")
+		}
+		if next != nil {
+			next(action)
+		}
+	}
+}
+
 func (r *Runtime) Start() error {
 	r.service.Start()
 	go r.eventLoop()
@@ -247,10 +329,30 @@ func (r *Runtime) IsEnabled() bool {
 	return r.service.IsEnabled()
 }
 
+func (r *Runtime) Stats() autoclicker.Stats {
+	return r.service.Stats()
+}
+
+func (r *Runtime) Subscribe(buffer int) (<-chan autoclicker.StateEvent, func()) {
+	return r.service.Subscribe(buffer)
+}
+
 func (r *Runtime) SetCPS(cps float64) error {
 	return r.service.SetCPS(cps)
 }
 
+func (r *Runtime) SetTimingModel(model autoclicker.TimingModel) error {
+	return r.service.SetTimingModel(model)
+}
+
+func (r *Runtime) SetRateCurve(curve autoclicker.RateCurve) {
+	r.service.SetRateCurve(curve)
+}
+
+func (r *Runtime) SetClickDownModel(model autoclicker.ClickDownModel) error {
+	return r.service.SetClickDownModel(model)
+}
+
 func (r *Runtime) SetJitter(pixels int) error {
 	return r.service.SetJitter(pixels)
 }
@@ -258,8 +360,10 @@ func (r *Runtime) SetJitter(pixels int) error {
 func (r *Runtime) SetTriggerCode(code uint16) {
 	r.mu.RLock()
 	toggle := r.toggleCode
+	panicCode := r.panicCode
+	bindings := r.bindings
 	r.mu.RUnlock()
-	if err := r.applyBindings(code, toggle); err != nil {
+	if err := r.applyBindings(code, bindings, toggle, panicCode); err != nil {
 		r.logger.Warn("Failed to update trigger binding", "err", err)
 	}
 }
@@ -267,12 +371,134 @@ func (r *Runtime) SetTriggerCode(code uint16) {
 func (r *Runtime) SetToggleCode(code uint16) {
 	r.mu.RLock()
 	trigger := r.triggerCode
+	panicCode := r.panicCode
+	bindings := r.bindings
 	r.mu.RUnlock()
-	if err := r.applyBindings(trigger, code); err != nil {
+	if err := r.applyBindings(trigger, bindings, code, panicCode); err != nil {
 		r.logger.Warn("Failed to update toggle binding", "err", err)
 	}
 }
 
+func (r *Runtime) SetTriggerMode(mode autoclicker.TriggerMode) error {
+	return r.service.SetTriggerMode(mode)
+}
+
+func (r *Runtime) SetBurst(count int, cooldown time.Duration) error {
+	return r.service.SetBurst(count, cooldown)
+}
+
+func (r *Runtime) SetRamp(ramp autoclicker.Ramp) error {
+	return r.service.SetRamp(ramp)
+}
+
+func (r *Runtime) SetTicks(ticks autoclicker.Ticks) error {
+	return r.service.SetTicks(ticks)
+}
+
+func (r *Runtime) SetMaxHold(d time.Duration) error {
+	return r.service.SetMaxHold(d)
+}
+
+func (r *Runtime) SetHoldDelay(d time.Duration) error {
+	return r.service.SetHoldDelay(d)
+}
+
+func (r *Runtime) SetMaxCPSWindow(limit int) error {
+	return r.service.SetMaxCPSWindow(limit)
+}
+
+func (r *Runtime) SetDebounce(press, release time.Duration) error {
+	return r.service.SetDebounce(press, release)
+}
+
+func (r *Runtime) SetOutputCode(code uint16) error {
+	if err := r.validateOutputs(code, nil, nil); err != nil {
+		return err
+	}
+	r.service.SetOutputCode(code)
+	return nil
+}
+
+func (r *Runtime) SetPattern(pattern autoclicker.Pattern) error {
+	if err := r.validateOutputs(0, pattern, nil); err != nil {
+		return err
+	}
+	return r.service.SetPattern(pattern)
+}
+
+func (r *Runtime) SetBindings(bindings []autoclicker.Binding) error {
+	if err := r.validateOutputs(0, nil, bindings); err != nil {
+		return err
+	}
+	r.mu.RLock()
+	trigger := r.triggerCode
+	toggle := r.toggleCode
+	panicCode := r.panicCode
+	r.mu.RUnlock()
+	if err := r.applyBindings(trigger, bindings, toggle, panicCode); err != nil {
+		return err
+	}
+	return r.service.SetBindings(bindings)
+}
+
+func (r *Runtime) UpdateConfig(cfg RuntimeConfig) error {
+	outputCode := cfg.OutputCode
+	if outputCode == 0 {
+		outputCode = linuxinput.CodeBTNLeft
+	}
+	if err := r.validateOutputs(outputCode, cfg.Pattern, cfg.Bindings); err != nil {
+		return err
+	}
+
+	r.mu.RLock()
+	trigger := r.triggerCode
+	toggle := r.toggleCode
+	panicCode := r.panicCode
+	bindings := r.bindings
+	r.mu.RUnlock()
+	if err := r.applyBindings(cfg.TriggerCode, cfg.Bindings, cfg.ToggleCode, cfg.PanicCode); err != nil {
+		return err
+	}
+	if err := r.service.UpdateConfig(r.serviceConfig(cfg, outputCode)); err != nil {
+		if restoreErr := r.applyBindings(trigger, bindings, toggle, panicCode); restoreErr != nil {
+			r.logger.Warn("Failed to restore bindings", "err", restoreErr)
+		}
+		return err
+	}
+	return nil
+}
+
+func (r *Runtime) validateOutputs(primary uint16, pattern autoclicker.Pattern, bindings []autoclicker.Binding) error {
+	r.injectMu.Lock()
+	defer r.injectMu.Unlock()
+
+	if primary != 0 {
+		if _, err := r.outputTargetLocked(primary); err != nil {
+			return fmt.Errorf("output binding: %w", err)
+		}
+	}
+	for _, code := range pattern.Codes() {
+		if _, err := r.outputTargetLocked(code); err != nil {
+			return fmt.Errorf("output pattern: %w", err)
+		}
+	}
+	for _, binding := range bindings {
+		output := binding.OutputCode
+		if output == 0 {
+			output = linuxinput.CodeBTNLeft
+		}
+		if _, err := r.outputTargetLocked(output); err != nil {
+			return fmt.Errorf("output binding: %w", err)
+		}
+		for _, code := range binding.Pattern.Codes() {
+			if _, err := r.outputTargetLocked(code); err != nil {
+				return fmt.Errorf("output pattern: %w", err)
+			}
+		}
+	}
+	return nil
+}
+
 func (r *Runtime) CaptureNextKeyCode(timeout time.Duration) (uint16, error) {
 	return 0, fmt.Errorf("live capture unavailable on x11 runtime")
 }
@@ -298,28 +524,46 @@ func (r *Runtime) eventLoop() {
 		switch ev := event.(type) {
 		case xproto.KeyPressEvent:
 			if code, ok := r.lookupKeyCode(ev.Detail); ok {
-				r.service.SubmitEvent("x11-global", autoclicker.Event{Type: autoclicker.EventTypeKey, Code: code, Value: 1})
+				r.submitGrabbed(code, 1, ev.State)
 			}
 			_ = xproto.AllowEventsChecked(r.conn, xproto.AllowReplayKeyboard, xproto.TimeCurrentTime).Check()
 		case xproto.KeyReleaseEvent:
 			if code, ok := r.lookupKeyCode(ev.Detail); ok {
-				r.service.SubmitEvent("x11-global", autoclicker.Event{Type: autoclicker.EventTypeKey, Code: code, Value: 0})
+				r.submitGrabbed(code, 0, ev.State)
 			}
 			_ = xproto.AllowEventsChecked(r.conn, xproto.AllowReplayKeyboard, xproto.TimeCurrentTime).Check()
 		case xproto.ButtonPressEvent:
 			if code, ok := r.lookupButtonCode(ev.Detail); ok {
-				r.service.SubmitEvent("x11-global", autoclicker.Event{Type: autoclicker.EventTypeKey, Code: code, Value: 1})
+				r.submitGrabbed(code, 1, ev.State)
 			}
 			_ = xproto.AllowEventsChecked(r.conn, xproto.AllowReplayPointer, xproto.TimeCurrentTime).Check()
 		case xproto.ButtonReleaseEvent:
 			if code, ok := r.lookupButtonCode(ev.Detail); ok {
-				r.service.SubmitEvent("x11-global", autoclicker.Event{Type: autoclicker.EventTypeKey, Code: code, Value: 0})
+				r.submitGrabbed(code, 0, ev.State)
 			}
 			_ = xproto.AllowEventsChecked(r.conn, xproto.AllowReplayPointer, xproto.TimeCurrentTime).Check()
 		}
 	}
 }
 
+func (r *Runtime) submitGrabbed(code uint16, value int32, state uint16) {
+	var held autoclicker.Modifiers
+	for mod, mask := range xModifierMasks {
+		if state&mask != 0 {
+			held |= mod
+		}
+	}
+	for _, mod := range (held ^ r.modifiers).Split() {
+		var modValue int32
+		if held&mod != 0 {
+			modValue = 1
+		}
+		r.service.SubmitEvent("x11-global", autoclicker.Event{Type: autoclicker.EventTypeKey, Code: mod.Keys()[0], Value: modValue})
+	}
+	r.modifiers = held
+	r.service.SubmitEvent("x11-global", autoclicker.Event{Type: autoclicker.EventTypeKey, Code: code, Value: value})
+}
+
 func (r *Runtime) lookupKeyCode(key xproto.Keycode) (uint16, bool) {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
@@ -334,7 +578,7 @@ func (r *Runtime) lookupButtonCode(button xproto.Button) (uint16, bool) {
 	return code, ok
 }
 
-func (r *Runtime) applyBindings(triggerCode, toggleCode uint16) error {
+func (r *Runtime) applyBindings(triggerCode uint16, bindings []autoclicker.Binding, toggleCode, panicCode uint16) error {
 	triggerBinding, err := r.resolveBinding(triggerCode)
 	if err != nil {
 		return fmt.Errorf("trigger binding: %w", err)
@@ -343,40 +587,84 @@ func (r *Runtime) applyBindings(triggerCode, toggleCode uint16) error {
 	if err != nil {
 		return fmt.Errorf("toggle binding: %w", err)
 	}
+	triggerBindings := []codeBinding{triggerBinding}
+	for _, binding := range bindings {
+		resolved, err := r.resolveBinding(binding.TriggerCode)
+		if err != nil {
+			return fmt.Errorf("trigger binding: %w", err)
+		}
+		triggerBindings = append(triggerBindings, resolved)
+	}
 
+	// The grabbed keycodes and buttons map to key codes without modifiers;
+	// the service tells chords apart from the modifier state.
 	keyToCode := make(map[xproto.Keycode]uint16)
-	for _, key := range triggerBinding.keycodes {
-		keyToCode[key] = triggerCode
+	buttonToCode := make(map[xproto.Button]uint16)
+	keyMasks := make(map[xproto.Keycode]map[uint16]struct{})
+	buttonMasks := make(map[xproto.Button]map[uint16]struct{})
+	addGrabs := func(binding codeBinding, what string) error {
+		code := autoclicker.ChordKey(binding.code)
+		masks := grabMasks(binding.code)
+		for _, key := range binding.keycodes {
+			if existing, ok := keyToCode[key]; ok && existing != code {
+				return fmt.Errorf("%s resolves to the same X11 keycode as another binding", what)
+			}
+			keyToCode[key] = code
+			keyMasks[key] = addMasks(keyMasks[key], masks)
+		}
+		for _, button := range binding.buttons {
+			if existing, ok := buttonToCode[button]; ok && existing != code {
+				return fmt.Errorf("%s resolves to the same X11 mouse button as another binding", what)
+			}
+			buttonToCode[button] = code
+			buttonMasks[button] = addMasks(buttonMasks[button], masks)
+		}
+		return nil
 	}
-	for _, key := range toggleBinding.keycodes {
-		if existing, ok := keyToCode[key]; ok && existing != toggleCode {
-			return fmt.Errorf("trigger and toggle resolve to same X11 keycode")
+	for _, binding := range triggerBindings {
+		if err := addGrabs(binding, "trigger"); err != nil {
+			return err
 		}
-		keyToCode[key] = toggleCode
 	}
-
-	buttonToCode := make(map[xproto.Button]uint16)
-	for _, button := range triggerBinding.buttons {
-		buttonToCode[button] = triggerCode
+	if err := addGrabs(toggleBinding, "toggle"); err != nil {
+		return err
 	}
-	for _, button := range toggleBinding.buttons {
-		if existing, ok := buttonToCode[button]; ok && existing != toggleCode {
-			return fmt.Errorf("trigger and toggle resolve to same X11 mouse button")
+
+	if panicCode != 0 {
+		panicBinding, err := r.resolveBinding(panicCode)
+		if err != nil {
+			return fmt.Errorf("panic binding: %w", err)
+		}
+		if err := addGrabs(panicBinding, "panic key"); err != nil {
+			return err
 		}
-		buttonToCode[button] = toggleCode
 	}
 
-	keys := make([]xproto.Keycode, 0, len(keyToCode))
-	for key := range keyToCode {
-		keys = append(keys, key)
+	keys := make([]keyGrab, 0, len(keyMasks))
+	for key, masks := range keyMasks {
+		for mods := range masks {
+			keys = append(keys, keyGrab{key: key, mods: mods})
+		}
 	}
-	sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].key != keys[j].key {
+			return keys[i].key < keys[j].key
+		}
+		return keys[i].mods < keys[j].mods
+	})
 
-	buttons := make([]xproto.Button, 0, len(buttonToCode))
-	for button := range buttonToCode {
-		buttons = append(buttons, button)
+	buttons := make([]buttonGrab, 0, len(buttonMasks))
+	for button, masks := range buttonMasks {
+		for mods := range masks {
+			buttons = append(buttons, buttonGrab{button: button, mods: mods})
+		}
 	}
-	sort.Slice(buttons, func(i, j int) bool { return buttons[i] < buttons[j] })
+	sort.Slice(buttons, func(i, j int) bool {
+		if buttons[i].button != buttons[j].button {
+			return buttons[i].button < buttons[j].button
+		}
+		return buttons[i].mods < buttons[j].mods
+	})
 
 	r.mu.Lock()
 	defer r.mu.Unlock()
@@ -389,6 +677,8 @@ func (r *Runtime) applyBindings(triggerCode, toggleCode uint16) error {
 
 	r.triggerCode = triggerCode
 	r.toggleCode = toggleCode
+	r.panicCode = panicCode
+	r.bindings = bindings
 	r.triggerBinding = triggerBinding
 	r.toggleBinding = toggleBinding
 	r.keyToCode = keyToCode
@@ -396,27 +686,58 @@ func (r *Runtime) applyBindings(triggerCode, toggleCode uint16) error {
 	if r.service != nil {
 		r.service.SetTriggerCode(triggerCode)
 		r.service.SetToggleCode(toggleCode)
+		r.service.SetPanicCode(panicCode)
 	}
 	return nil
 }
 
-func (r *Runtime) grabAllLocked(keys []xproto.Keycode, buttons []xproto.Button) error {
-	for _, key := range keys {
+// A chord is grabbed only under its own modifiers, with or without Caps Lock
+// and Num Lock, so the bare key still reaches other windows.
+func grabMasks(code uint16) []uint16 {
+	mods := autoclicker.ChordModifiers(code)
+	if mods == 0 {
+		return []uint16{xproto.ModMaskAny}
+	}
+	var mask uint16
+	for _, mod := range mods.Split() {
+		mask |= xModifierMasks[mod]
+	}
+	return []uint16{mask, mask | xproto.ModMaskLock, mask | xproto.ModMask2, mask | xproto.ModMaskLock | xproto.ModMask2}
+}
+
+func addMasks(set map[uint16]struct{}, masks []uint16) map[uint16]struct{} {
+	if set == nil {
+		set = make(map[uint16]struct{}, len(masks))
+	}
+	if _, ok := set[xproto.ModMaskAny]; ok {
+		return set
+	}
+	for _, mask := range masks {
+		if mask == xproto.ModMaskAny {
+			return map[uint16]struct{}{xproto.ModMaskAny: {}}
+		}
+		set[mask] = struct{}{}
+	}
+	return set
+}
+
+func (r *Runtime) grabAllLocked(keys []keyGrab, buttons []buttonGrab) error {
+	for _, grab := range keys {
 		if err := xproto.GrabKeyChecked(
 			r.conn,
 			false,
 			r.rootWin,
-			xproto.ModMaskAny,
-			key,
+			grab.mods,
+			grab.key,
 			xproto.GrabModeAsync,
 			xproto.GrabModeAsync,
 		).Check(); err != nil {
 			return err
 		}
-		r.grabbedKeys = append(r.grabbedKeys, key)
+		r.grabbedKeys = append(r.grabbedKeys, grab)
 	}
 
-	for _, button := range buttons {
+	for _, grab := range buttons {
 		if err := xproto.GrabButtonChecked(
 			r.conn,
 			false,
@@ -426,33 +747,34 @@ func (r *Runtime) grabAllLocked(keys []xproto.Keycode, buttons []xproto.Button)
 			xproto.GrabModeAsync,
 			xproto.WindowNone,
 			xproto.CursorNone,
-			byte(button),
-			xproto.ModMaskAny,
+			byte(grab.button),
+			grab.mods,
 		).Check(); err != nil {
 			return err
 		}
-		r.grabbedButtons = append(r.grabbedButtons, button)
+		r.grabbedButtons = append(r.grabbedButtons, grab)
 	}
 	return nil
 }
 
 func (r *Runtime) ungrabAllLocked() {
-	for _, key := range r.grabbedKeys {
-		xproto.UngrabKey(r.conn, key, r.rootWin, xproto.ModMaskAny)
+	for _, grab := range r.grabbedKeys {
+		xproto.UngrabKey(r.conn, grab.key, r.rootWin, grab.mods)
 	}
-	for _, button := range r.grabbedButtons {
-		xproto.UngrabButton(r.conn, byte(button), r.rootWin, xproto.ModMaskAny)
+	for _, grab := range r.grabbedButtons {
+		xproto.UngrabButton(r.conn, byte(grab.button), r.rootWin, grab.mods)
 	}
 	r.grabbedKeys = nil
 	r.grabbedButtons = nil
 }
 
 func (r *Runtime) resolveBinding(code uint16) (codeBinding, error) {
-	if button, ok := codeToXButton(code); ok {
+	key := autoclicker.ChordKey(code)
+	if button, ok := codeToXButton(key); ok {
 		return codeBinding{code: code, buttons: []xproto.Button{button}}, nil
 	}
 
-	keyName, ok := linuxCodeToXKeyString(code)
+	keyName, ok := linuxCodeToXKeyString(key)
 	if !ok {
 		return codeBinding{}, fmt.Errorf("unsupported X11 key code %s", linuxinput.FormatCodeName(code))
 	}
@@ -474,6 +796,29 @@ func (r *Runtime) resolveBinding(code uint16) (codeBinding, error) {
 	return codeBinding{code: code, keycodes: result}, nil
 }
 
+// Callers must hold injectMu.
+func (r *Runtime) outputTargetLocked(code uint16) (outputTarget, error) {
+	if target, ok := r.outputTargets[code]; ok {
+		return target, nil
+	}
+
+	binding, err := r.resolveBinding(code)
+	if err != nil {
+		return outputTarget{}, err
+	}
+	var target outputTarget
+	switch {
+	case len(binding.buttons) > 0:
+		target = outputTarget{isButton: true, detail: byte(binding.buttons[0])}
+	case len(binding.keycodes) > 0:
+		target = outputTarget{detail: byte(binding.keycodes[0])}
+	default:
+		return outputTarget{}, fmt.Errorf("unsupported X11 output %s", linuxinput.FormatCodeName(code))
+	}
+	r.outputTargets[code] = target
+	return target, nil
+}
+
 func ListInputDevices() ([]DeviceInfo, error) {
 	return []DeviceInfo{
 		{
@@ -531,6 +876,7 @@ func CaptureNextKeyCode(timeout time.Duration) (uint16, error) {
 		return 0, fmt.Errorf("failed to grab pointer (status=%d)", reply.Status)
 	}
 
+	var chords autoclicker.ChordCapture
 	deadline := time.Now().Add(timeout)
 	for {
 		event, xerr := conn.PollForEvent()
@@ -545,15 +891,125 @@ func CaptureNextKeyCode(timeout time.Duration) (uint16, error) {
 			continue
 		}
 
+		input := autoclicker.Event{Type: autoclicker.EventTypeKey}
+		ok := false
 		switch ev := event.(type) {
 		case xproto.ButtonPressEvent:
-			if code, ok := xButtonToCode(ev.Detail); ok {
-				return code, nil
+			input.Code, ok = xButtonToCode(ev.Detail)
+			input.Value = 1
+		case xproto.KeyPressEvent:
+			input.Code, ok = xKeyToLinuxCode(xu, ev.State, ev.Detail)
+			input.Value = 1
+		case xproto.KeyReleaseEvent:
+			input.Code, ok = xKeyToLinuxCode(xu, ev.State, ev.Detail)
+		}
+		if !ok {
+			continue
+		}
+		if code, ok := chords.Feed("x11-global", input); ok {
+			return code, nil
+		}
+	}
+}
+
+func xKeyToLinuxCode(xu *xgbutil.XUtil, state uint16, key xproto.Keycode) (uint16, bool) {
+	for _, mask := range xModifierMasks {
+		state &^= mask
+	}
+	return xLookupStringToLinuxCode(keybind.LookupString(xu, state, key))
+}
+
+func WatchKey(code uint16, stop <-chan struct{}, fn func(down bool, at time.Time)) error {
+	xu, err := xgbutil.NewConn()
+	if err != nil {
+		return err
+	}
+	conn := xu.Conn()
+	root := xu.RootWin()
+	keybind.Initialize(xu)
+	defer conn.Close()
+
+	button, isButton := codeToXButton(code)
+	if isButton {
+		defer xproto.UngrabPointer(conn, xproto.TimeCurrentTime)
+		if reply, err := xproto.GrabPointer(
+			conn,
+			false,
+			root,
+			xproto.EventMaskButtonPress|xproto.EventMaskButtonRelease,
+			xproto.GrabModeAsync,
+			xproto.GrabModeAsync,
+			xproto.WindowNone,
+			xproto.CursorNone,
+			xproto.TimeCurrentTime,
+		).Reply(); err != nil {
+			return err
+		} else if reply.Status != xproto.GrabStatusSuccess {
+			return fmt.Errorf("failed to grab pointer (status=%d)", reply.Status)
+		}
+	} else {
+		defer xproto.UngrabKeyboard(conn, xproto.TimeCurrentTime)
+		if reply, err := xproto.GrabKeyboard(
+			conn,
+			false,
+			root,
+			xproto.TimeCurrentTime,
+			xproto.GrabModeAsync,
+			xproto.GrabModeAsync,
+		).Reply(); err != nil {
+			return err
+		} else if reply.Status != xproto.GrabStatusSuccess {
+			return fmt.Errorf("failed to grab keyboard (status=%d)", reply.Status)
+		}
+	}
+
+	// Event times are server milliseconds; anchor them to the wall clock at
+	// the first event so intervals keep the server's precision.
+	var anchorWall time.Time
+	var anchorServer xproto.Timestamp
+	report := func(down bool, server xproto.Timestamp) {
+		if anchorWall.IsZero() {
+			anchorWall, anchorServer = time.Now(), server
+		}
+		fn(down, anchorWall.Add(time.Duration(int32(server-anchorServer))*time.Millisecond))
+	}
+	keyMatches := func(state uint16, key xproto.Keycode) bool {
+		lookup := keybind.LookupString(xu, state, key)
+		got, ok := xLookupStringToLinuxCode(lookup)
+		return ok && got == code
+	}
+
+	for {
+		select {
+		case <-stop:
+			return nil
+		default:
+		}
+		event, xerr := conn.PollForEvent()
+		if xerr != nil {
+			return xerr
+		}
+		if event == nil {
+			time.Sleep(time.Millisecond)
+			continue
+		}
+
+		switch ev := event.(type) {
+		case xproto.ButtonPressEvent:
+			if isButton && ev.Detail == button {
+				report(true, ev.Time)
+			}
+		case xproto.ButtonReleaseEvent:
+			if isButton && ev.Detail == button {
+				report(false, ev.Time)
 			}
 		case xproto.KeyPressEvent:
-			lookup := keybind.LookupString(xu, ev.State, ev.Detail)
-			if code, ok := xLookupStringToLinuxCode(lookup); ok {
-				return code, nil
+			if !isButton && keyMatches(ev.State, ev.Detail) {
+				report(true, ev.Time)
+			}
+		case xproto.KeyReleaseEvent:
+			if !isButton && keyMatches(ev.State, ev.Detail) {
+				report(false, ev.Time)
 			}
 		}
 	}
@@ -1,14 +1,42 @@
 package x11input
 
-import "time"
+import (
+	"time"
+
+	"clicker/internal/core/autoclicker"
+)
 
 type RuntimeConfig struct {
-	TriggerCode  uint16
-	ToggleCode   uint16
-	CPS          float64
-	ClickDown    time.Duration
-	JitterPixels int
-	StartEnabled bool
+	TriggerCode        uint16
+	ToggleCode         uint16
+	OutputCode         uint16
+	CPS                float64
+	Timing             autoclicker.TimingModel
+	Curve              autoclicker.RateCurve
+	ClickDown          time.Duration
+	ClickDownModel     autoclicker.ClickDownModel
+	JitterPixels       int
+	Pattern            autoclicker.Pattern
+	StartEnabled       bool
+	TriggerMode        autoclicker.TriggerMode
+	LatchThreshold     time.Duration
+	BurstCount         int
+	BurstCooldown      time.Duration
+	MaxCPSWindow       int
+	CatchUp            autoclicker.CatchUpPolicy
+	Ramp               autoclicker.Ramp
+	Ticks              autoclicker.Ticks
+	MaxHold            time.Duration
+	HoldDelay          time.Duration
+	DebouncePress      time.Duration
+	DebounceRelease    time.Duration
+	ToggleGesture      autoclicker.ToggleGesture
+	DoubleTapWindow    time.Duration
+	LongPressThreshold time.Duration
+	PanicCode          uint16
+	PanicAction        autoclicker.PanicAction
+	OnPanic            func(autoclicker.PanicAction)
+	Bindings           []autoclicker.Binding
 }
 
 type DeviceInfo struct {
@@ -0,0 +1,88 @@
+// Package autoclickertest provides fakes and a scenario runner for autoclicker tests.
+package autoclickertest
+
+import (
+	"sync"
+	"time"
+
+	"clicker/internal/core/autoclicker"
+)
+
+type Record struct {
+	At    time.Duration
+	Event autoclicker.Event
+}
+
+type Injector struct {
+	clock autoclicker.Clock
+	epoch time.Time
+
+	mu       sync.Mutex
+	records  []Record
+	closed   bool
+	err      error
+	failures int
+}
+
+func NewInjector(clock autoclicker.Clock) *Injector {
+	return &Injector{clock: clock, epoch: clock.Now()}
+}
+
+func (i *Injector) WriteEvents(events ...autoclicker.Event) error {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	if i.err != nil {
+		i.failures++
+		return i.err
+	}
+	var at time.Duration
+	if i.clock != nil {
+		at = i.clock.Now().Sub(i.epoch)
+	}
+	for _, event := range events {
+		i.records = append(i.records, Record{At: at, Event: event})
+	}
+	return nil
+}
+
+func (i *Injector) Close() error {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.closed = true
+	return nil
+}
+
+func (i *Injector) Fail(err error) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.err = err
+}
+
+func (i *Injector) Failures() int {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	return i.failures
+}
+
+func (i *Injector) Records() []Record {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	out := make([]Record, len(i.records))
+	copy(out, i.records)
+	return out
+}
+
+func (i *Injector) Events() []autoclicker.Event {
+	records := i.Records()
+	out := make([]autoclicker.Event, 0, len(records))
+	for _, record := range records {
+		out = append(out, record.Event)
+	}
+	return out
+}
+
+func (i *Injector) Closed() bool {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	return i.closed
+}
@@ -0,0 +1,46 @@
+package autoclickertest
+
+import (
+	"log/slog"
+	"sync"
+)
+
+type Entry struct {
+	Level slog.Level
+	Msg   string
+	Args  []any
+}
+
+type Logger struct {
+	mu      sync.Mutex
+	entries []Entry
+}
+
+func (l *Logger) Debug(msg string, args ...any) { l.log(slog.LevelDebug, msg, args) }
+func (l *Logger) Info(msg string, args ...any)  { l.log(slog.LevelInfo, msg, args) }
+func (l *Logger) Warn(msg string, args ...any)  { l.log(slog.LevelWarn, msg, args) }
+func (l *Logger) Error(msg string, args ...any) { l.log(slog.LevelError, msg, args) }
+
+func (l *Logger) log(level slog.Level, msg string, args []any) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.entries = append(l.entries, Entry{Level: level, Msg: msg, Args: append([]any(nil), args...)})
+}
+
+func (l *Logger) Entries() []Entry {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	out := make([]Entry, len(l.entries))
+	copy(out, l.entries)
+	return out
+}
+
+func (l *Logger) Messages(level slog.Level) []string {
+	var out []string
+	for _, entry := range l.Entries() {
+		if entry.Level >= level {
+			out = append(out, entry.Msg)
+		}
+	}
+	return out
+}
@@ -0,0 +1,128 @@
+package autoclickertest
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"clicker/internal/core/autoclicker"
+)
+
+const Source = "device"
+
+var Epoch = time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+func Config(startEnabled bool) autoclicker.Config {
+	return autoclicker.Config{
+		TriggerCode:    autoclicker.LeftButtonCode + 3,
+		ToggleCode:     autoclicker.LeftButtonCode + 4,
+		TriggerSources: map[string]struct{}{Source: {}},
+		ToggleSources:  map[string]struct{}{Source: {}},
+		GrabSources:    map[string]struct{}{},
+		CPS:            10,
+		StartEnabled:   startEnabled,
+	}
+}
+
+type Step struct {
+	At     time.Duration
+	Source string
+	Event  autoclicker.Event
+}
+
+func Press(at time.Duration, code uint16) Step {
+	return Step{At: at, Event: autoclicker.Event{Type: autoclicker.EventTypeKey, Code: code, Value: 1}}
+}
+
+func Release(at time.Duration, code uint16) Step {
+	return Step{At: at, Event: autoclicker.Event{Type: autoclicker.EventTypeKey, Code: code, Value: 0}}
+}
+
+type Scenario struct {
+	Config autoclicker.Config
+	Steps  []Step
+	Until  time.Duration
+}
+
+type Result struct {
+	Records []Record
+	Stats   autoclicker.Stats
+	Log     []Entry
+}
+
+func Run(t testing.TB, scenario Scenario) Result {
+	t.Helper()
+
+	clock := autoclicker.NewVirtualClock(Epoch)
+	injector := NewInjector(clock)
+	logger := &Logger{}
+	cfg := scenario.Config
+	cfg.Clock = clock
+	service, err := autoclicker.NewService(cfg, injector, logger)
+	if err != nil {
+		t.Fatalf("NewService() error = %v", err)
+	}
+	service.Start()
+
+	steps := slices.Clone(scenario.Steps)
+	slices.SortStableFunc(steps, func(a, b Step) int {
+		return cmp.Compare(a.At, b.At)
+	})
+	for _, step := range steps {
+		advance(service, clock, Epoch.Add(step.At))
+		source := step.Source
+		if source == "" {
+			source = Source
+		}
+		if !service.SubmitEvent(source, step.Event) {
+			service.Stop()
+			t.Fatalf("SubmitEvent() rejected step at %v", step.At)
+		}
+		service.WaitIdle()
+	}
+	advance(service, clock, Epoch.Add(scenario.Until))
+
+	stats := service.Stats()
+	service.Stop()
+	return Result{Records: injector.Records(), Stats: stats, Log: logger.Entries()}
+}
+
+func advance(service *autoclicker.Service, clock *autoclicker.VirtualClock, target time.Time) {
+	for {
+		service.WaitIdle()
+		next, ok := clock.NextDeadline()
+		if !ok || next.After(target) {
+			break
+		}
+		clock.AdvanceToNext()
+	}
+	if now := clock.Now(); target.After(now) {
+		clock.Advance(target.Sub(now))
+	}
+}
+
+var eventTypeNames = map[uint16]string{
+	autoclicker.EventTypeSyn: "syn",
+	autoclicker.EventTypeKey: "key",
+	autoclicker.EventTypeRel: "rel",
+	autoclicker.EventTypeAbs: "abs",
+}
+
+func Format(records []Record) string {
+	var b strings.Builder
+	for _, record := range records {
+		event := record.Event
+		if event.Type == autoclicker.EventTypeSyn {
+			continue
+		}
+		name, ok := eventTypeNames[event.Type]
+		if !ok {
+			name = fmt.Sprintf("type%d", event.Type)
+		}
+		fmt.Fprintf(&b, "%v %s %#x %d\n", record.At, name, event.Code, event.Value)
+	}
+	return b.String()
+}
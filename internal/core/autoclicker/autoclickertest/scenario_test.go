@@ -0,0 +1,149 @@
+package autoclickertest
+
+import (
+	"errors"
+	"log/slog"
+	"slices"
+	"testing"
+	"time"
+
+	"clicker/internal/core/autoclicker"
+)
+
+func TestRunHoldClicksGolden(t *testing.T) {
+	cfg := Config(true)
+	cfg.ClickDown = 20 * time.Millisecond
+
+	result := Run(t, Scenario{
+		Config: cfg,
+		Steps: []Step{
+			Press(0, cfg.TriggerCode),
+			Release(250*time.Millisecond, cfg.TriggerCode),
+		},
+		Until: 400 * time.Millisecond,
+	})
+
+	const want = `0s key 0x110 1
+20ms key 0x110 0
+100ms key 0x110 1
+120ms key 0x110 0
+200ms key 0x110 1
+220ms key 0x110 0
+`
+	if got := Format(result.Records); got != want {
+		t.Fatalf("injected events:\n%s\nwant:\n%s", got, want)
+	}
+	if result.Stats.Clicks != 3 || result.Stats.Holds != 1 {
+		t.Fatalf("Stats = %d clicks in %d holds, want 3 in 1", result.Stats.Clicks, result.Stats.Holds)
+	}
+}
+
+func TestRunLatchGolden(t *testing.T) {
+	cfg := Config(true)
+	cfg.TriggerMode = autoclicker.TriggerModeLatch
+
+	result := Run(t, Scenario{
+		Config: cfg,
+		Steps: []Step{
+			Press(0, cfg.TriggerCode),
+			Release(10*time.Millisecond, cfg.TriggerCode),
+			Press(150*time.Millisecond, cfg.TriggerCode),
+			Release(160*time.Millisecond, cfg.TriggerCode),
+		},
+		Until: time.Second,
+	})
+
+	const want = `0s key 0x110 1
+0s key 0x110 0
+100ms key 0x110 1
+100ms key 0x110 0
+`
+	if got := Format(result.Records); got != want {
+		t.Fatalf("injected events:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestRunToggleAndStopRelease(t *testing.T) {
+	cfg := Config(false)
+	cfg.ClickDown = time.Hour
+
+	result := Run(t, Scenario{
+		Config: cfg,
+		Steps: []Step{
+			Press(0, cfg.TriggerCode),
+			Press(50*time.Millisecond, cfg.ToggleCode),
+			Release(60*time.Millisecond, cfg.ToggleCode),
+			Press(70*time.Millisecond, cfg.TriggerCode),
+		},
+		Until: 80 * time.Millisecond,
+	})
+
+	// The down time is capped at the interval, which outlasts the
+	// scenario: the click is released when the service stops.
+	const want = `70ms key 0x110 1
+80ms key 0x110 0
+`
+	if got := Format(result.Records); got != want {
+		t.Fatalf("injected events:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestRunIsReproducibleWithRandomModels(t *testing.T) {
+	cfg := Config(true)
+	cfg.JitterPixels = 3
+	timing, err := autoclicker.GaussianTiming(15, 10*time.Millisecond)
+	if err != nil {
+		t.Fatalf("GaussianTiming() error = %v", err)
+	}
+	cfg.Timing = timing
+	scenario := Scenario{
+		Config: cfg,
+		Steps: []Step{
+			Press(0, cfg.TriggerCode),
+			Release(2*time.Second, cfg.TriggerCode),
+		},
+		Until: 3 * time.Second,
+	}
+
+	first := Run(t, scenario)
+	second := Run(t, scenario)
+	if !slices.Equal(first.Records, second.Records) {
+		t.Fatalf("expected identical records:\n%s\nvs\n%s", Format(first.Records), Format(second.Records))
+	}
+}
+
+func TestInjectorFailureIsCountedAndLogged(t *testing.T) {
+	clock := autoclicker.NewVirtualClock(Epoch)
+	injector := NewInjector(clock)
+	injector.Fail(errors.New("device gone"))
+	logger := &Logger{}
+
+	cfg := Config(true)
+	cfg.Clock = clock
+	service, err := autoclicker.NewService(cfg, injector, logger)
+	if err != nil {
+		t.Fatalf("NewService() error = %v", err)
+	}
+	service.Start()
+	defer service.Stop()
+
+	service.SubmitEvent(Source, Press(0, cfg.TriggerCode).Event)
+	service.WaitIdle()
+
+	if injector.Failures() != 1 || len(injector.Records()) != 0 {
+		t.Fatalf("expected one rejected write, got %d failures and %d records", injector.Failures(), len(injector.Records()))
+	}
+	if got := service.Stats().InjectorErrors; got != 1 {
+		t.Fatalf("InjectorErrors = %d, want 1", got)
+	}
+	if warnings := logger.Messages(slog.LevelWarn); !slices.Contains(warnings, "Failed to emit click down") {
+		t.Fatalf("expected a click down warning, got %v", warnings)
+	}
+
+	injector.Fail(nil)
+	clock.Advance(100 * time.Millisecond)
+	service.WaitIdle()
+	if records := injector.Records(); len(records) == 0 || records[0].At != 100*time.Millisecond {
+		t.Fatalf("expected clicking to resume at 100ms, got %v", records)
+	}
+}
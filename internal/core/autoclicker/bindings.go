@@ -0,0 +1,210 @@
+package autoclicker
+
+import (
+	"fmt"
+	"sync/atomic"
+	"time"
+)
+
+type bindingState struct {
+	timing       atomic.Pointer[timingSlot]
+	clickDown    atomic.Pointer[clickDownSlot]
+	curve        atomic.Pointer[curveSlot]
+	jitterPixels atomic.Int64
+	triggerCode  atomic.Uint32
+	outputCode   atomic.Uint32
+	pattern      atomic.Pointer[Pattern]
+	holding      atomic.Bool
+	pressSeq     atomic.Uint64
+	cutOff       atomic.Bool
+
+	// pressedSources, latched, pressedAt, unlatching, maxHoldTimer and the
+	// hold delay fields are guarded by Service.stateMu.
+	pressedSources map[string]struct{}
+	latched        bool
+	pressedAt      time.Time
+	unlatching     bool
+	maxHoldTimer   Timer
+	delaying       bool
+	delaySeq       uint64
+	holdDelayTimer Timer
+
+	// parked and parkedForWake tell whether the click loop sleeps and
+	// whether a wake ends that sleep. They are guarded by activity.mu.
+	parked        bool
+	parkedForWake bool
+	activity      *activity
+
+	wakeCh chan struct{}
+	stopCh chan struct{}
+	doneCh chan struct{}
+}
+
+func newBindingState(activity *activity, binding Binding) *bindingState {
+	state := &bindingState{
+		activity:       activity,
+		pressedSources: make(map[string]struct{}),
+		wakeCh:         make(chan struct{}, 1),
+		stopCh:         make(chan struct{}),
+		doneCh:         make(chan struct{}),
+	}
+	state.timing.Store(&timingSlot{model: binding.Timing})
+	state.clickDown.Store(&clickDownSlot{model: binding.ClickDownModel})
+	state.curve.Store(&curveSlot{model: binding.Curve})
+	state.jitterPixels.Store(int64(binding.JitterPixels))
+	state.triggerCode.Store(uint32(binding.TriggerCode))
+	state.outputCode.Store(uint32(binding.OutputCode))
+	pattern := binding.Pattern.clone()
+	state.pattern.Store(&pattern)
+	return state
+}
+
+func validateBinding(binding Binding) error {
+	if binding.Timing == nil && binding.CPS <= 0 {
+		return fmt.Errorf("cps must be > 0")
+	}
+	if binding.JitterPixels < 0 {
+		return fmt.Errorf("jitter must be >= 0")
+	}
+	if binding.TriggerCode == 0 {
+		return fmt.Errorf("binding trigger code is empty")
+	}
+	if ChordModifiers(binding.OutputCode) != 0 {
+		return fmt.Errorf("output code cannot be a chord")
+	}
+	return validatePattern(binding.Pattern)
+}
+
+func normalizeBinding(binding Binding) Binding {
+	if binding.OutputCode == 0 {
+		binding.OutputCode = LeftButtonCode
+	}
+	if binding.Timing == nil {
+		binding.Timing = fixedTiming{interval: CPSInterval(binding.CPS)}
+	}
+	if binding.ClickDownModel == nil {
+		binding.ClickDownModel = fixedClickDown{down: max(binding.ClickDown, 0)}
+	}
+	return binding
+}
+
+func (b *bindingState) snapshot() Binding {
+	binding := Binding{
+		TriggerCode:    b.currentTriggerCode(),
+		OutputCode:     b.currentOutputCode(),
+		CPS:            intervalCPS(b.currentTiming().Mean()),
+		Timing:         b.currentTiming(),
+		ClickDownModel: b.currentClickDown(),
+		Curve:          b.currentCurve().model,
+		JitterPixels:   int(b.currentJitterPixels()),
+		Pattern:        b.currentPattern().clone(),
+	}
+	if fixed, ok := binding.ClickDownModel.(fixedClickDown); ok {
+		binding.ClickDown = fixed.down
+	}
+	return binding
+}
+
+func (b *bindingState) currentTiming() TimingModel {
+	return b.timing.Load().model
+}
+
+func (b *bindingState) currentClickDown() ClickDownModel {
+	return b.clickDown.Load().model
+}
+
+func (b *bindingState) currentCurve() *curveSlot {
+	return b.curve.Load()
+}
+
+func (b *bindingState) currentJitterPixels() int32 {
+	pixels := b.jitterPixels.Load()
+	if pixels <= 0 {
+		return 0
+	}
+	return int32(pixels)
+}
+
+func (b *bindingState) currentTriggerCode() uint16 {
+	return uint16(b.triggerCode.Load())
+}
+
+func (b *bindingState) currentOutputCode() uint16 {
+	return uint16(b.outputCode.Load())
+}
+
+func (b *bindingState) currentPattern() Pattern {
+	return *b.pattern.Load()
+}
+
+func (b *bindingState) outputCodes() []uint16 {
+	return append([]uint16{b.currentOutputCode()}, b.currentPattern().Codes()...)
+}
+
+// Callers must hold Service.stateMu.
+func (b *bindingState) resetTrigger() {
+	b.holding.Store(false)
+	clear(b.pressedSources)
+	b.latched = false
+	b.unlatching = false
+	b.cutOff.Store(false)
+	b.stopMaxHold()
+	b.stopHoldDelay()
+}
+
+func (b *bindingState) signalWake() {
+	b.activity.wake(b)
+}
+
+func (s *Service) SetBindings(bindings []Binding) error {
+	next := make([]*bindingState, 0, len(bindings)+1)
+	for i, binding := range bindings {
+		if err := validateBinding(binding); err != nil {
+			return fmt.Errorf("binding %d: %w", i+1, err)
+		}
+		next = append(next, newBindingState(s.activity, normalizeBinding(binding)))
+	}
+
+	s.stateMu.Lock()
+	defer s.stateMu.Unlock()
+	defer s.syncHoldStats()
+
+	if s.stopped() {
+		return fmt.Errorf("service stopped")
+	}
+
+	s.replaceBindingsLocked(append([]*bindingState{s.primary}, next...))
+	return nil
+}
+
+// next[0] must be the primary binding. Callers must hold stateMu.
+func (s *Service) replaceBindingsLocked(next []*bindingState) {
+	previous := s.currentBindings()
+	s.bindings.Store(&next)
+
+	for _, b := range previous[1:] {
+		close(b.stopCh)
+		if s.started {
+			<-b.doneCh
+		}
+		s.releaseCodes(b.outputCodes()...)
+	}
+	if s.started {
+		for _, b := range next[1:] {
+			s.startClickLoop(b)
+		}
+	}
+}
+
+func (s *Service) Bindings() []Binding {
+	bindings := s.currentBindings()
+	out := make([]Binding, 0, len(bindings)-1)
+	for _, b := range bindings[1:] {
+		out = append(out, b.snapshot())
+	}
+	return out
+}
+
+func (s *Service) currentBindings() []*bindingState {
+	return *s.bindings.Load()
+}
@@ -0,0 +1,69 @@
+package autoclicker
+
+import (
+	"fmt"
+	"time"
+)
+
+type burstState struct {
+	pressSeq  uint64
+	remaining int
+	endedAt   time.Time
+}
+
+func (s *Service) SetBurst(count int, cooldown time.Duration) error {
+	if err := validateBurst(count, cooldown); err != nil {
+		return err
+	}
+	s.burstCount.Store(int64(count))
+	s.burstCooldownNanos.Store(cooldown.Nanoseconds())
+	for _, b := range s.currentBindings() {
+		b.signalWake()
+	}
+	return nil
+}
+
+func (s *Service) Burst() (int, time.Duration) {
+	return int(s.burstCount.Load()), time.Duration(s.burstCooldownNanos.Load())
+}
+
+func validateBurst(count int, cooldown time.Duration) error {
+	if count < 0 {
+		return fmt.Errorf("burst count must be >= 0")
+	}
+	if cooldown < 0 {
+		return fmt.Errorf("burst cooldown must be >= 0")
+	}
+	return nil
+}
+
+func (s *Service) nextBurstClick(b *bindingState, st *burstState) (wait time.Duration, ok bool) {
+	count, cooldown := s.Burst()
+	if count <= 0 {
+		return 0, true
+	}
+
+	if seq := b.pressSeq.Load(); seq != st.pressSeq {
+		if !st.endedAt.IsZero() {
+			if remaining := cooldown - s.clock.Now().Sub(st.endedAt); remaining > 0 {
+				return remaining, false
+			}
+		}
+		st.pressSeq = seq
+		st.remaining = count
+	}
+	if st.remaining <= 0 {
+		return 0, false
+	}
+	return 0, true
+}
+
+func (st *burstState) burstClicked(now time.Time) {
+	if st.remaining <= 0 {
+		return
+	}
+	st.remaining--
+	if st.remaining == 0 {
+		st.endedAt = now
+	}
+}
@@ -0,0 +1,196 @@
+package autoclicker
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+type Modifiers uint16
+
+const (
+	ModCtrl  Modifiers = 1 << 12
+	ModShift Modifiers = 1 << 13
+	ModAlt   Modifiers = 1 << 14
+	ModMeta  Modifiers = 1 << 15
+
+	modifierMask = ModCtrl | ModShift | ModAlt | ModMeta
+)
+
+var modifierNames = []struct {
+	mod  Modifiers
+	name string
+	keys [2]uint16
+}{
+	{ModCtrl, "CTRL", [2]uint16{29, 97}},   // KEY_LEFTCTRL, KEY_RIGHTCTRL
+	{ModShift, "SHIFT", [2]uint16{42, 54}}, // KEY_LEFTSHIFT, KEY_RIGHTSHIFT
+	{ModAlt, "ALT", [2]uint16{56, 100}},    // KEY_LEFTALT, KEY_RIGHTALT
+	{ModMeta, "META", [2]uint16{125, 126}}, // KEY_LEFTMETA, KEY_RIGHTMETA
+}
+
+var modifierAliases = map[string]Modifiers{
+	"CONTROL": ModCtrl,
+	"SUPER":   ModMeta,
+	"WIN":     ModMeta,
+}
+
+var modifierKeys = func() map[uint16]Modifiers {
+	keys := make(map[uint16]Modifiers, 2*len(modifierNames))
+	for _, entry := range modifierNames {
+		for _, code := range entry.keys {
+			keys[code] = entry.mod
+		}
+	}
+	return keys
+}()
+
+func Chord(key uint16, mods Modifiers) uint16 {
+	return ChordKey(key) | uint16(mods&modifierMask)
+}
+
+func ChordKey(code uint16) uint16 {
+	return code &^ uint16(modifierMask)
+}
+
+func ChordModifiers(code uint16) Modifiers {
+	return Modifiers(code) & modifierMask
+}
+
+func ModifierKey(code uint16) (Modifiers, bool) {
+	mod, ok := modifierKeys[code]
+	return mod, ok
+}
+
+func (m Modifiers) Split() []Modifiers {
+	var mods []Modifiers
+	for _, entry := range modifierNames {
+		if m&entry.mod != 0 {
+			mods = append(mods, entry.mod)
+		}
+	}
+	return mods
+}
+
+func (m Modifiers) Keys() []uint16 {
+	var keys []uint16
+	for _, entry := range modifierNames {
+		if m&entry.mod != 0 {
+			keys = append(keys, entry.keys[:]...)
+		}
+	}
+	return keys
+}
+
+func (m Modifiers) String() string {
+	names := make([]string, 0, len(modifierNames))
+	for _, entry := range modifierNames {
+		if m&entry.mod != 0 {
+			names = append(names, entry.name)
+		}
+	}
+	return strings.Join(names, "+")
+}
+
+func ParseChord(raw string, parseKey func(string) (uint16, error)) (uint16, error) {
+	parts := strings.Split(raw, "+")
+	var mods Modifiers
+	for _, part := range parts[:len(parts)-1] {
+		name := strings.ToUpper(strings.TrimSpace(part))
+		mod, ok := modifierAliases[name]
+		for _, entry := range modifierNames {
+			if entry.name == name {
+				mod, ok = entry.mod, true
+			}
+		}
+		if !ok {
+			return 0, fmt.Errorf("unknown modifier %q in %q (expected CTRL, SHIFT, ALT or META)", strings.TrimSpace(part), raw)
+		}
+		mods |= mod
+	}
+	key, err := parseKey(strings.TrimSpace(parts[len(parts)-1]))
+	if err != nil {
+		return 0, err
+	}
+	if ChordModifiers(key) != 0 {
+		return 0, fmt.Errorf("key code out of range: %d", key)
+	}
+	return Chord(key, mods), nil
+}
+
+func FormatChord(code uint16, formatKey func(uint16) string) string {
+	key := formatKey(ChordKey(code))
+	if mods := ChordModifiers(code); mods != 0 {
+		return mods.String() + "+" + key
+	}
+	return key
+}
+
+type ModifierState struct {
+	held map[string]map[uint16]struct{}
+}
+
+func (m *ModifierState) Update(source string, event Event) bool {
+	if event.Type != EventTypeKey {
+		return false
+	}
+	if _, ok := modifierKeys[event.Code]; !ok {
+		return false
+	}
+	keys := m.held[source]
+	switch event.Value {
+	case 0:
+		delete(keys, event.Code)
+	case 1, 2:
+		if keys == nil {
+			if m.held == nil {
+				m.held = make(map[string]map[uint16]struct{})
+			}
+			keys = make(map[uint16]struct{})
+			m.held[source] = keys
+		}
+		keys[event.Code] = struct{}{}
+	}
+	return true
+}
+
+func (m *ModifierState) Held() Modifiers {
+	var mods Modifiers
+	for _, keys := range m.held {
+		for code := range keys {
+			mods |= modifierKeys[code]
+		}
+	}
+	return mods
+}
+
+type ChordCapture struct {
+	mu   sync.Mutex
+	mods ModifierState
+	lone uint16
+}
+
+func (c *ChordCapture) Feed(source string, event Event) (uint16, bool) {
+	if event.Type != EventTypeKey {
+		return 0, false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.mods.Update(source, event) {
+		switch event.Value {
+		case 1:
+			c.lone = event.Code
+		case 0:
+			if c.lone == event.Code {
+				c.lone = 0
+				return event.Code, true
+			}
+		}
+		return 0, false
+	}
+	if event.Value != 1 {
+		return 0, false
+	}
+	c.lone = 0
+	return Chord(event.Code, c.mods.Held()), true
+}
@@ -0,0 +1,162 @@
+package autoclicker_test
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"clicker/internal/core/autoclicker"
+	"clicker/internal/core/autoclicker/autoclickertest"
+)
+
+const (
+	keyLeftCtrl  = 29
+	keyLeftShift = 42
+	keyF8        = 66
+)
+
+func parseTestKey(raw string) (uint16, error) {
+	if raw == "KEY_F8" {
+		return keyF8, nil
+	}
+	code, err := strconv.ParseUint(raw, 10, 16)
+	if err != nil {
+		return 0, fmt.Errorf("unknown key %q", raw)
+	}
+	return uint16(code), nil
+}
+
+func TestParseChord(t *testing.T) {
+	tests := []struct {
+		raw  string
+		want uint16
+	}{
+		{"KEY_F8", keyF8},
+		{"CTRL+SHIFT+KEY_F8", autoclicker.Chord(keyF8, autoclicker.ModCtrl|autoclicker.ModShift)},
+		{"shift+ctrl+KEY_F8", autoclicker.Chord(keyF8, autoclicker.ModCtrl|autoclicker.ModShift)},
+		{"Super + KEY_F8", autoclicker.Chord(keyF8, autoclicker.ModMeta)},
+	}
+	for _, tt := range tests {
+		got, err := autoclicker.ParseChord(tt.raw, parseTestKey)
+		if err != nil {
+			t.Fatalf("ParseChord(%q) error = %v", tt.raw, err)
+		}
+		if got != tt.want {
+			t.Fatalf("ParseChord(%q) = %#x, want %#x", tt.raw, got, tt.want)
+		}
+	}
+
+	for _, raw := range []string{"HYPER+KEY_F8", "CTRL+", "CTRL+4096"} {
+		if _, err := autoclicker.ParseChord(raw, parseTestKey); err == nil {
+			t.Fatalf("ParseChord(%q) accepted an invalid chord", raw)
+		}
+	}
+
+	chord := autoclicker.Chord(keyF8, autoclicker.ModAlt|autoclicker.ModCtrl)
+	if got := autoclicker.FormatChord(chord, func(uint16) string { return "KEY_F8" }); got != "CTRL+ALT+KEY_F8" {
+		t.Fatalf("FormatChord() = %q, want %q", got, "CTRL+ALT+KEY_F8")
+	}
+}
+
+func TestChordToggleLeavesBareKeyAlone(t *testing.T) {
+	cfg := autoclickertest.Config(false)
+	cfg.ToggleCode = autoclicker.Chord(keyF8, autoclicker.ModCtrl|autoclicker.ModShift)
+
+	result := autoclickertest.Run(t, autoclickertest.Scenario{
+		Config: cfg,
+		Steps: []autoclickertest.Step{
+			// The bare key and a partial chord do not toggle.
+			autoclickertest.Press(0, keyF8),
+			autoclickertest.Release(10*time.Millisecond, keyF8),
+			autoclickertest.Press(20*time.Millisecond, keyLeftCtrl),
+			autoclickertest.Press(30*time.Millisecond, keyF8),
+			autoclickertest.Release(40*time.Millisecond, keyF8),
+			autoclickertest.Press(50*time.Millisecond, cfg.TriggerCode),
+			autoclickertest.Release(150*time.Millisecond, cfg.TriggerCode),
+			// Modifiers held on another source count too.
+			{At: 200 * time.Millisecond, Source: "keyboard", Event: autoclickertest.Press(0, keyLeftShift).Event},
+			autoclickertest.Press(210*time.Millisecond, keyF8),
+			autoclickertest.Release(220*time.Millisecond, keyF8),
+			autoclickertest.Press(300*time.Millisecond, cfg.TriggerCode),
+			autoclickertest.Release(450*time.Millisecond, cfg.TriggerCode),
+		},
+		Until: time.Second,
+	})
+
+	want := []time.Duration{300 * time.Millisecond, 400 * time.Millisecond}
+	if got := clickStarts(result.Records, autoclicker.LeftButtonCode); !reflect.DeepEqual(got, want) {
+		t.Fatalf("clicks at %v, want %v", got, want)
+	}
+}
+
+func TestChordTriggerEndsOnKeyRelease(t *testing.T) {
+	cfg := autoclickertest.Config(true)
+	trigger := cfg.TriggerCode
+	cfg.TriggerCode = autoclicker.Chord(trigger, autoclicker.ModCtrl)
+
+	result := autoclickertest.Run(t, autoclickertest.Scenario{
+		Config: cfg,
+		Steps: []autoclickertest.Step{
+			autoclickertest.Press(0, keyLeftCtrl),
+			autoclickertest.Press(10*time.Millisecond, trigger),
+			// Letting go of the modifier first does not leave the trigger
+			// held.
+			autoclickertest.Release(150*time.Millisecond, keyLeftCtrl),
+			autoclickertest.Release(250*time.Millisecond, trigger),
+			// Without the modifier the key is not the trigger.
+			autoclickertest.Press(400*time.Millisecond, trigger),
+			autoclickertest.Release(600*time.Millisecond, trigger),
+		},
+		Until: time.Second,
+	})
+
+	want := []time.Duration{10 * time.Millisecond, 110 * time.Millisecond, 210 * time.Millisecond}
+	if got := clickStarts(result.Records, autoclicker.LeftButtonCode); !reflect.DeepEqual(got, want) {
+		t.Fatalf("clicks at %v, want %v", got, want)
+	}
+}
+
+func TestChordCapture(t *testing.T) {
+	key := func(code uint16, value int32) autoclicker.Event {
+		return autoclicker.Event{Type: autoclicker.EventTypeKey, Code: code, Value: value}
+	}
+	var capture autoclicker.ChordCapture
+
+	for _, event := range []autoclicker.Event{key(keyLeftCtrl, 1), key(keyLeftShift, 1)} {
+		if _, ok := capture.Feed("keyboard", event); ok {
+			t.Fatalf("Feed(%v) captured before the key", event)
+		}
+	}
+	code, ok := capture.Feed("keyboard", key(keyF8, 1))
+	if want := autoclicker.Chord(keyF8, autoclicker.ModCtrl|autoclicker.ModShift); !ok || code != want {
+		t.Fatalf("Feed() = %#x, %v, want %#x", code, ok, want)
+	}
+	capture.Feed("keyboard", key(keyF8, 0))
+	capture.Feed("keyboard", key(keyLeftShift, 0))
+	capture.Feed("keyboard", key(keyLeftCtrl, 0))
+
+	capture.Feed("keyboard", key(keyLeftShift, 1))
+	code, ok = capture.Feed("keyboard", key(keyLeftShift, 0))
+	if !ok || code != keyLeftShift {
+		t.Fatalf("lone modifier Feed() = %#x, %v, want %#x", code, ok, keyLeftShift)
+	}
+}
+
+func TestChordValidation(t *testing.T) {
+	chord := autoclicker.Chord(keyF8, autoclicker.ModCtrl)
+
+	cfg := autoclickertest.Config(true)
+	cfg.OutputCode = chord
+	if _, err := autoclicker.NewService(cfg, autoclickertest.NewInjector(autoclicker.SystemClock()), &autoclickertest.Logger{}); err == nil || !strings.Contains(err.Error(), "chord") {
+		t.Fatalf("NewService() error = %v, want a chord output error", err)
+	}
+
+	cfg = autoclickertest.Config(true)
+	cfg.PanicCode = chord
+	if _, err := autoclicker.NewService(cfg, autoclickertest.NewInjector(autoclicker.SystemClock()), &autoclickertest.Logger{}); err == nil || !strings.Contains(err.Error(), "chord") {
+		t.Fatalf("NewService() error = %v, want a chord panic error", err)
+	}
+}
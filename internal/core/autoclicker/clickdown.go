@@ -0,0 +1,116 @@
+package autoclicker
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"time"
+)
+
+type ClickDownModel interface {
+	NextDown(rng *rand.Rand) time.Duration
+	String() string
+}
+
+type fixedClickDown struct {
+	down time.Duration
+}
+
+func FixedClickDown(down time.Duration) (ClickDownModel, error) {
+	if down < 0 {
+		return nil, fmt.Errorf("click down must be >= 0")
+	}
+	return fixedClickDown{down: down}, nil
+}
+
+func (m fixedClickDown) NextDown(*rand.Rand) time.Duration {
+	return m.down
+}
+
+func (m fixedClickDown) String() string {
+	return fmt.Sprintf("fixed %v", m.down)
+}
+
+type uniformClickDown struct {
+	min time.Duration
+	max time.Duration
+}
+
+func UniformClickDown(min, max time.Duration) (ClickDownModel, error) {
+	if min < 0 || max < 0 {
+		return nil, fmt.Errorf("click down must be >= 0")
+	}
+	if max < min {
+		min, max = max, min
+	}
+	return uniformClickDown{min: min, max: max}, nil
+}
+
+func (m uniformClickDown) NextDown(rng *rand.Rand) time.Duration {
+	if m.max == m.min {
+		return m.min
+	}
+	return m.min + time.Duration(rng.Int63n(int64(m.max-m.min)+1))
+}
+
+func (m uniformClickDown) String() string {
+	return fmt.Sprintf("uniform %v-%v", m.min, m.max)
+}
+
+type gaussianClickDown struct {
+	mean   time.Duration
+	stddev time.Duration
+}
+
+func GaussianClickDown(mean, stddev time.Duration) (ClickDownModel, error) {
+	if mean < 0 {
+		return nil, fmt.Errorf("click down must be >= 0")
+	}
+	if stddev < 0 {
+		return nil, fmt.Errorf("stddev must be >= 0")
+	}
+	return gaussianClickDown{mean: mean, stddev: stddev}, nil
+}
+
+func (m gaussianClickDown) NextDown(rng *rand.Rand) time.Duration {
+	down := time.Duration(float64(m.mean) + rng.NormFloat64()*float64(m.stddev))
+	return max(down, 0)
+}
+
+func (m gaussianClickDown) String() string {
+	return fmt.Sprintf("gaussian %v ±%v", m.mean, m.stddev)
+}
+
+type logNormalClickDown struct {
+	mean  time.Duration
+	mu    float64
+	sigma float64
+}
+
+func LogNormalClickDown(mean, stddev time.Duration) (ClickDownModel, error) {
+	if mean <= 0 {
+		return nil, fmt.Errorf("click down must be > 0")
+	}
+	if stddev < 0 {
+		return nil, fmt.Errorf("stddev must be >= 0")
+	}
+	ratio := float64(stddev) / float64(mean)
+	sigma2 := math.Log1p(ratio * ratio)
+	return logNormalClickDown{
+		mean:  mean,
+		mu:    math.Log(float64(mean)) - sigma2/2,
+		sigma: math.Sqrt(sigma2),
+	}, nil
+}
+
+func (m logNormalClickDown) NextDown(rng *rand.Rand) time.Duration {
+	return time.Duration(math.Exp(m.mu + m.sigma*rng.NormFloat64()))
+}
+
+func (m logNormalClickDown) String() string {
+	return fmt.Sprintf("log-normal %v σ=%.2f", m.mean, m.sigma)
+}
+
+type clickDownSlot struct {
+	model ClickDownModel
+}
@@ -0,0 +1,98 @@
+package autoclicker_test
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+
+	"clicker/internal/core/autoclicker"
+	"clicker/internal/core/autoclicker/autoclickertest"
+)
+
+func TestUniformClickDownStaysWithinRange(t *testing.T) {
+	model, err := autoclicker.UniformClickDown(20*time.Millisecond, 8*time.Millisecond)
+	if err != nil {
+		t.Fatalf("UniformClickDown() error = %v", err)
+	}
+	rng := rand.New(rand.NewSource(1))
+	seen := make(map[time.Duration]struct{})
+	for i := 0; i < 1000; i++ {
+		down := model.NextDown(rng)
+		if down < 8*time.Millisecond || down > 20*time.Millisecond {
+			t.Fatalf("down %v outside [8ms, 20ms]", down)
+		}
+		seen[down] = struct{}{}
+	}
+	if len(seen) < 100 {
+		t.Fatalf("expected varied down durations, got %d distinct values", len(seen))
+	}
+}
+
+func TestGaussianClickDownNeverNegative(t *testing.T) {
+	model, err := autoclicker.GaussianClickDown(time.Millisecond, 50*time.Millisecond)
+	if err != nil {
+		t.Fatalf("GaussianClickDown() error = %v", err)
+	}
+	rng := rand.New(rand.NewSource(1))
+	for i := 0; i < 1000; i++ {
+		if down := model.NextDown(rng); down < 0 {
+			t.Fatalf("negative down duration %v", down)
+		}
+	}
+}
+
+func TestClickDownConstructorsRejectInvalidParameters(t *testing.T) {
+	if _, err := autoclicker.FixedClickDown(-time.Millisecond); err == nil {
+		t.Fatalf("expected negative fixed down to fail")
+	}
+	if _, err := autoclicker.UniformClickDown(-time.Millisecond, time.Millisecond); err == nil {
+		t.Fatalf("expected negative uniform bound to fail")
+	}
+	if _, err := autoclicker.GaussianClickDown(10*time.Millisecond, -time.Millisecond); err == nil {
+		t.Fatalf("expected negative stddev to fail")
+	}
+	if _, err := autoclicker.LogNormalClickDown(0, time.Millisecond); err == nil {
+		t.Fatalf("expected zero log-normal mean to fail")
+	}
+}
+
+func TestClickOnceCapsDownAtInterval(t *testing.T) {
+	cfg := autoclickertest.Config(true)
+	cfg.TriggerCode = autoclicker.LeftButtonCode + 2
+	cfg.ToggleCode = cfg.TriggerCode + 1
+
+	injector := &autoclickertest.Injector{}
+	service, err := autoclicker.NewService(cfg, injector, &autoclickertest.Logger{})
+	if err != nil {
+		t.Fatalf("NewService() error = %v", err)
+	}
+
+	start := time.Now()
+	if ok := service.ClickOnce(service.Primary(), rand.New(rand.NewSource(1)), []uint16{service.Primary().OutputCode()}, 5*time.Millisecond, time.Hour); !ok {
+		t.Fatalf("clickOnce() returned false")
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("click stayed down for %v, expected it to be capped at the interval", elapsed)
+	}
+	assertReleaseSuffix(t, injector.Events())
+}
+
+func TestSetClickDownModelRejectsNil(t *testing.T) {
+	service, err := autoclicker.NewService(autoclickertest.Config(true), &autoclickertest.Injector{}, &autoclickertest.Logger{})
+	if err != nil {
+		t.Fatalf("NewService() error = %v", err)
+	}
+	if err := service.SetClickDownModel(nil); err == nil {
+		t.Fatalf("expected nil click down model to be rejected")
+	}
+	model, err := autoclicker.UniformClickDown(5*time.Millisecond, 15*time.Millisecond)
+	if err != nil {
+		t.Fatalf("UniformClickDown() error = %v", err)
+	}
+	if err := service.SetClickDownModel(model); err != nil {
+		t.Fatalf("SetClickDownModel() error = %v", err)
+	}
+	if got := service.Primary().ClickDownModel(); got != model {
+		t.Fatalf("primary click down model = %v, want %v", got, model)
+	}
+}
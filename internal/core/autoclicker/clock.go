@@ -0,0 +1,144 @@
+package autoclicker
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+type Clock interface {
+	Now() time.Time
+	AfterFunc(d time.Duration, f func()) Timer
+}
+
+type Timer interface {
+	Stop() bool
+}
+
+func SystemClock() Clock {
+	return systemClock{}
+}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+func (systemClock) AfterFunc(d time.Duration, f func()) Timer {
+	return time.AfterFunc(d, f)
+}
+
+type VirtualClock struct {
+	mu      sync.Mutex
+	cond    *sync.Cond
+	now     time.Time
+	pending []*virtualTimer
+}
+
+func NewVirtualClock(start time.Time) *VirtualClock {
+	clock := &VirtualClock{now: start}
+	clock.cond = sync.NewCond(&clock.mu)
+	return clock
+}
+
+type virtualTimer struct {
+	clock    *VirtualClock
+	deadline time.Time
+	f        func()
+}
+
+func (c *VirtualClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *VirtualClock) AfterFunc(d time.Duration, f func()) Timer {
+	if d <= 0 {
+		f()
+		return &virtualTimer{clock: c}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	timer := &virtualTimer{clock: c, deadline: c.now.Add(d), f: f}
+	c.pending = append(c.pending, timer)
+	sort.SliceStable(c.pending, func(i, j int) bool {
+		return c.pending[i].deadline.Before(c.pending[j].deadline)
+	})
+	c.cond.Broadcast()
+	return timer
+}
+
+func (c *VirtualClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.advanceTo(c.now.Add(d))
+}
+
+func (c *VirtualClock) AdvanceToNext() (time.Duration, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.pending) == 0 {
+		return 0, false
+	}
+	step := max(c.pending[0].deadline.Sub(c.now), 0)
+	c.advanceTo(c.pending[0].deadline)
+	return step, true
+}
+
+func (c *VirtualClock) NextDeadline() (time.Time, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.pending) == 0 {
+		return time.Time{}, false
+	}
+	return c.pending[0].deadline, true
+}
+
+func (c *VirtualClock) BlockUntil(n int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for len(c.pending) < n {
+		c.cond.Wait()
+	}
+}
+
+func (c *VirtualClock) Pending() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.pending)
+}
+
+// Callers must hold mu; it is released while each timer runs.
+func (c *VirtualClock) advanceTo(target time.Time) {
+	for len(c.pending) > 0 && !c.pending[0].deadline.After(target) {
+		timer := c.pending[0]
+		c.pending = c.pending[1:]
+		if timer.deadline.After(c.now) {
+			c.now = timer.deadline
+		}
+		c.mu.Unlock()
+		timer.f()
+		c.mu.Lock()
+	}
+	if target.After(c.now) {
+		c.now = target
+	}
+	c.cond.Broadcast()
+}
+
+func (t *virtualTimer) Stop() bool {
+	c := t.clock
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for i, pending := range c.pending {
+		if pending == t {
+			c.pending = append(c.pending[:i], c.pending[i+1:]...)
+			c.cond.Broadcast()
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,219 @@
+package autoclicker_test
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"clicker/internal/core/autoclicker"
+	"clicker/internal/core/autoclicker/autoclickertest"
+)
+
+func keyTimes(records []autoclickertest.Record, code uint16, value int32) []time.Duration {
+	var out []time.Duration
+	for _, record := range records {
+		if record.Event == (autoclicker.Event{Type: autoclicker.EventTypeKey, Code: code, Value: value}) {
+			out = append(out, record.At)
+		}
+	}
+	return out
+}
+
+// stepClock waits for the service to sleep on clock and then wakes it,
+// steps times in a row.
+func stepClock(clock *autoclicker.VirtualClock, steps int) {
+	for range steps {
+		clock.BlockUntil(1)
+		clock.AdvanceToNext()
+	}
+}
+
+func waitForClicks(t *testing.T, events <-chan autoclicker.StateEvent, count int) {
+	t.Helper()
+	for range count {
+		nextStateEvent(t, events, autoclicker.StateClick)
+	}
+}
+
+func startVirtualService(t *testing.T, cfg autoclicker.Config) (*autoclicker.Service, *autoclicker.VirtualClock, *autoclickertest.Injector) {
+	t.Helper()
+	clock := autoclicker.NewVirtualClock(autoclickertest.Epoch)
+	injector := autoclickertest.NewInjector(clock)
+	cfg.Clock = clock
+	service, err := autoclicker.NewService(cfg, injector, &autoclickertest.Logger{})
+	if err != nil {
+		t.Fatalf("NewService() error = %v", err)
+	}
+	service.Start()
+	t.Cleanup(service.Stop)
+	return service, clock, injector
+}
+
+func TestVirtualClockFiresTimersInDeadlineOrder(t *testing.T) {
+	clock := autoclicker.NewVirtualClock(autoclickertest.Epoch)
+	var fired []time.Duration
+	record := func() {
+		fired = append(fired, clock.Now().Sub(autoclickertest.Epoch))
+	}
+	clock.AfterFunc(30*time.Millisecond, record)
+	clock.AfterFunc(10*time.Millisecond, record)
+	stopped := clock.AfterFunc(20*time.Millisecond, record)
+	if !stopped.Stop() {
+		t.Fatalf("expected Stop() to report a pending timer")
+	}
+
+	step, ok := clock.AdvanceToNext()
+	if !ok || step != 10*time.Millisecond {
+		t.Fatalf("AdvanceToNext() = %v, %v; want 10ms, true", step, ok)
+	}
+	clock.Advance(time.Second)
+
+	want := []time.Duration{10 * time.Millisecond, 30 * time.Millisecond}
+	if !reflect.DeepEqual(fired, want) {
+		t.Fatalf("timers fired at %v, want %v", fired, want)
+	}
+	if now := clock.Now(); now != autoclickertest.Epoch.Add(1010*time.Millisecond) {
+		t.Fatalf("Now() = %v, want 1.01s", now.Sub(autoclickertest.Epoch))
+	}
+	if _, ok := clock.AdvanceToNext(); ok {
+		t.Fatalf("expected no pending timers")
+	}
+}
+
+func TestVirtualClockClicksOnExactSchedule(t *testing.T) {
+	cfg := autoclickertest.Config(true)
+	cfg.TriggerCode = autoclicker.LeftButtonCode + 3
+	cfg.ToggleCode = autoclicker.LeftButtonCode + 5
+	cfg.CPS = 10
+	cfg.ClickDown = 20 * time.Millisecond
+
+	service, clock, injector := startVirtualService(t, cfg)
+	service.SubmitEvent("device", autoclicker.Event{Type: autoclicker.EventTypeKey, Code: cfg.TriggerCode, Value: 1})
+
+	// Every click sleeps once for its down time and once until the next
+	// deadline.
+	stepClock(clock, 10)
+	clock.BlockUntil(1)
+
+	wantDowns := []time.Duration{0, 100, 200, 300, 400, 500}
+	wantUps := []time.Duration{20, 120, 220, 320, 420}
+	for i := range wantDowns {
+		wantDowns[i] *= time.Millisecond
+	}
+	for i := range wantUps {
+		wantUps[i] *= time.Millisecond
+	}
+	if got := keyTimes(injector.Records(), autoclicker.LeftButtonCode, 1); !reflect.DeepEqual(got, wantDowns) {
+		t.Fatalf("click downs at %v, want %v", got, wantDowns)
+	}
+	if got := keyTimes(injector.Records(), autoclicker.LeftButtonCode, 0); !reflect.DeepEqual(got, wantUps) {
+		t.Fatalf("click ups at %v, want %v", got, wantUps)
+	}
+
+	stats := service.Stats()
+	if stats.Clicks != 5 {
+		t.Fatalf("Clicks = %d, want 5", stats.Clicks)
+	}
+	if stats.MeanLateness != 0 || stats.MissedDeadlines != 0 {
+		t.Fatalf("expected an on-time schedule, got lateness=%v missed=%d", stats.MeanLateness, stats.MissedDeadlines)
+	}
+}
+
+func TestVirtualClockBurstCooldown(t *testing.T) {
+	cfg := autoclickertest.Config(true)
+	cfg.TriggerCode = autoclicker.LeftButtonCode + 3
+	cfg.ToggleCode = autoclicker.LeftButtonCode + 5
+	cfg.CPS = 10
+	cfg.BurstCount = 2
+	cfg.BurstCooldown = time.Second
+
+	service, clock, injector := startVirtualService(t, cfg)
+	clicks, cancel := service.Subscribe(16)
+	defer cancel()
+	press := autoclicker.Event{Type: autoclicker.EventTypeKey, Code: cfg.TriggerCode, Value: 1}
+	release := autoclicker.Event{Type: autoclicker.EventTypeKey, Code: cfg.TriggerCode, Value: 0}
+
+	service.SubmitEvent("device", press)
+	// Zero down time never sleeps: one wait separates the two clicks.
+	stepClock(clock, 1)
+	waitForClicks(t, clicks, 2)
+
+	service.SubmitEvent("device", release)
+	service.SubmitEvent("device", press)
+	// The next burst first sleeps out the cooldown.
+	stepClock(clock, 2)
+	waitForClicks(t, clicks, 2)
+
+	want := []time.Duration{0, 100 * time.Millisecond, 1100 * time.Millisecond, 1200 * time.Millisecond}
+	if got := keyTimes(injector.Records(), autoclicker.LeftButtonCode, 1); !reflect.DeepEqual(got, want) {
+		t.Fatalf("click downs at %v, want %v", got, want)
+	}
+}
+
+func TestVirtualClockMakesRandomizedClickingReproducible(t *testing.T) {
+	run := func() []autoclickertest.Record {
+		cfg := autoclickertest.Config(true)
+		cfg.TriggerCode = autoclicker.LeftButtonCode + 3
+		cfg.ToggleCode = autoclicker.LeftButtonCode + 5
+		cfg.JitterPixels = 2
+		timing, err := autoclicker.UniformTiming(8, 12)
+		if err != nil {
+			t.Fatalf("UniformTiming() error = %v", err)
+		}
+		down, err := autoclicker.UniformClickDown(10*time.Millisecond, 30*time.Millisecond)
+		if err != nil {
+			t.Fatalf("UniformClickDown() error = %v", err)
+		}
+		cfg.Timing = timing
+		cfg.ClickDownModel = down
+
+		service, clock, injector := startVirtualService(t, cfg)
+		service.SubmitEvent("device", autoclicker.Event{Type: autoclicker.EventTypeKey, Code: cfg.TriggerCode, Value: 1})
+		stepClock(clock, 40)
+		clock.BlockUntil(1)
+		service.Stop()
+		return injector.Records()
+	}
+
+	first := run()
+	if second := run(); !reflect.DeepEqual(first, second) {
+		t.Fatalf("expected identical event sequences from the same virtual clock")
+	}
+
+	downs := make([]time.Duration, 0, 20)
+	var ups []time.Duration
+	var x, y int32
+	for _, item := range first {
+		switch item.Event.Type {
+		case autoclicker.EventTypeRel:
+			if item.Event.Value < -2 || item.Event.Value > 2 {
+				t.Fatalf("jitter move %d exceeds 2px", item.Event.Value)
+			}
+			if item.Event.Code == autoclicker.RelXCode {
+				x += item.Event.Value
+			} else {
+				y += item.Event.Value
+			}
+		case autoclicker.EventTypeKey:
+			if item.Event.Value == 1 {
+				if x < -2 || x > 2 || y < -2 || y > 2 {
+					t.Fatalf("click at offset (%d,%d) exceeds 2px", x, y)
+				}
+				downs = append(downs, item.At)
+			} else {
+				ups = append(ups, item.At)
+			}
+		}
+	}
+	for i := 1; i < len(downs); i++ {
+		if interval := downs[i] - downs[i-1]; interval < autoclicker.CPSInterval(12) || interval > autoclicker.CPSInterval(8) {
+			t.Fatalf("interval %d = %v, want within [%v, %v]", i, interval, autoclicker.CPSInterval(12), autoclicker.CPSInterval(8))
+		}
+	}
+	// The last click is still down when Stop releases it.
+	for i, up := range ups[:len(ups)-1] {
+		if down := up - downs[i]; down < 10*time.Millisecond || down > 30*time.Millisecond {
+			t.Fatalf("click %d stayed down %v, want within [10ms, 30ms]", i, down)
+		}
+	}
+}
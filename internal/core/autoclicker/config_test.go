@@ -0,0 +1,129 @@
+package autoclicker_test
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"clicker/internal/core/autoclicker"
+	"clicker/internal/core/autoclicker/autoclickertest"
+)
+
+func TestUpdateConfigAppliesSourcesAndGrab(t *testing.T) {
+	injector := &autoclickertest.Injector{}
+	service, err := autoclicker.NewService(autoclickertest.Config(true), injector, &autoclickertest.Logger{})
+	if err != nil {
+		t.Fatalf("NewService() error = %v", err)
+	}
+
+	cfg := autoclickertest.Config(true)
+	cfg.TriggerCode = autoclicker.LeftButtonCode + 3
+	cfg.ToggleCode = autoclicker.LeftButtonCode + 4
+	cfg.TriggerSources = map[string]struct{}{"mouse": {}}
+	cfg.ToggleSources = map[string]struct{}{"mouse": {}}
+	cfg.GrabSources = map[string]struct{}{"mouse": {}}
+	cfg.GrabEnabled = true
+	cfg.PassThroughTrigger = true
+	if err := service.UpdateConfig(cfg); err != nil {
+		t.Fatalf("UpdateConfig() error = %v", err)
+	}
+
+	service.HandleEvent("device", autoclicker.Event{Type: autoclicker.EventTypeKey, Code: cfg.TriggerCode, Value: 1})
+	if service.Primary().Holding() {
+		t.Fatalf("expected the old source to be ignored")
+	}
+
+	press := autoclicker.Event{Type: autoclicker.EventTypeKey, Code: cfg.TriggerCode, Value: 1}
+	service.HandleEvent("mouse", press)
+	if !service.Primary().Holding() {
+		t.Fatalf("expected the new source to trigger")
+	}
+	if events := injector.Events(); len(events) == 0 || events[len(events)-1] != press {
+		t.Fatalf("expected the grabbed trigger to pass through, got %#v", events)
+	}
+
+	service.HandleEvent("mouse", autoclicker.Event{Type: autoclicker.EventTypeKey, Code: cfg.ToggleCode, Value: 1})
+	if service.IsEnabled() {
+		t.Fatalf("expected the new toggle code to disable clicking")
+	}
+}
+
+func TestUpdateConfigReleasesHeldOutputAndKeepsClicking(t *testing.T) {
+	clock := autoclicker.NewVirtualClock(autoclickertest.Epoch)
+	injector := autoclickertest.NewInjector(clock)
+	cfg := autoclickertest.Config(true)
+	cfg.TriggerCode = autoclicker.LeftButtonCode + 3
+	cfg.ToggleCode = autoclicker.LeftButtonCode + 4
+	cfg.ClickDown = 50 * time.Millisecond
+	cfg.Clock = clock
+	service, err := autoclicker.NewService(cfg, injector, &autoclickertest.Logger{})
+	if err != nil {
+		t.Fatalf("NewService() error = %v", err)
+	}
+	service.Start()
+	defer service.Stop()
+
+	service.SubmitEvent("device", autoclicker.Event{Type: autoclicker.EventTypeKey, Code: cfg.TriggerCode, Value: 1})
+	service.WaitIdle()
+	if !service.IsCodeHeld(autoclicker.LeftButtonCode) {
+		t.Fatalf("expected a click to be down")
+	}
+
+	cfg.Clock = nil
+	cfg.OutputCode = autoclicker.LeftButtonCode + 2
+	cfg.CPS = 20
+	cfg.ClickDown = 0
+	if err := service.UpdateConfig(cfg); err != nil {
+		t.Fatalf("UpdateConfig() error = %v", err)
+	}
+	if service.IsCodeHeld(autoclicker.LeftButtonCode) {
+		t.Fatalf("expected UpdateConfig to release the held click")
+	}
+	if ups := keyTimes(injector.Records(), autoclicker.LeftButtonCode, 0); len(ups) != 1 || ups[0] != 0 {
+		t.Fatalf("expected the held click to be released at once, got ups at %v", ups)
+	}
+
+	service.SubmitEvent("device", autoclicker.Event{Type: autoclicker.EventTypeKey, Code: cfg.TriggerCode, Value: 1})
+	for range 3 {
+		service.WaitIdle()
+		clock.AdvanceToNext()
+	}
+	service.WaitIdle()
+	// The click in progress finishes on its old schedule; the new output
+	// and rate apply from the next deadline on.
+	want := []time.Duration{100 * time.Millisecond, 150 * time.Millisecond}
+	if got := keyTimes(injector.Records(), cfg.OutputCode, 1); !reflect.DeepEqual(got, want) {
+		t.Fatalf("new output clicked at %v, want %v", got, want)
+	}
+}
+
+func TestUpdateConfigRejectsInvalidConfig(t *testing.T) {
+	service, err := autoclicker.NewService(autoclickertest.Config(false), &autoclickertest.Injector{}, &autoclickertest.Logger{})
+	if err != nil {
+		t.Fatalf("NewService() error = %v", err)
+	}
+
+	cfg := autoclickertest.Config(true)
+	cfg.CPS = 0
+	if err := service.UpdateConfig(cfg); err == nil {
+		t.Fatalf("expected zero CPS to be rejected")
+	}
+	cfg = autoclickertest.Config(true)
+	cfg.Clock = autoclicker.NewVirtualClock(autoclickertest.Epoch)
+	if err := service.UpdateConfig(cfg); err == nil {
+		t.Fatalf("expected a different clock to be rejected")
+	}
+	if service.IsEnabled() {
+		t.Fatalf("expected a rejected config to leave the service untouched")
+	}
+
+	events, cancel := service.Subscribe(4)
+	defer cancel()
+	if err := service.UpdateConfig(autoclickertest.Config(true)); err != nil {
+		t.Fatalf("UpdateConfig() error = %v", err)
+	}
+	if !service.IsEnabled() {
+		t.Fatalf("expected StartEnabled to enable the service")
+	}
+	nextStateEvent(t, events, autoclicker.StateEnabled)
+}
@@ -0,0 +1,59 @@
+package autoclicker
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+const cpsWindowSpan = time.Second
+
+func (s *Service) SetMaxCPSWindow(limit int) error {
+	if limit < 0 {
+		return fmt.Errorf("max cps window must be >= 0")
+	}
+	s.maxCPSWindow.Store(int64(limit))
+	for _, b := range s.currentBindings() {
+		b.signalWake()
+	}
+	return nil
+}
+
+func (s *Service) MaxCPSWindow() int {
+	return int(s.maxCPSWindow.Load())
+}
+
+type cpsWindow struct {
+	mu     sync.Mutex
+	starts []time.Time
+	ticks  tickCount
+}
+
+func (s *Service) reserveClick(now time.Time) time.Duration {
+	limit := int(s.maxCPSWindow.Load())
+	ticks := s.Ticks()
+	w := &s.cpsWindow
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	cutoff := now.Add(-cpsWindowSpan)
+	drop := 0
+	for drop < len(w.starts) && !w.starts[drop].After(cutoff) {
+		drop++
+	}
+	if drop > 0 {
+		w.starts = append(w.starts[:0], w.starts[drop:]...)
+	}
+	// Clicks are counted without a cap too, so a cap set later sees them.
+	var wait time.Duration
+	if limit > 0 && len(w.starts) >= limit {
+		wait = w.starts[len(w.starts)-limit].Add(cpsWindowSpan).Sub(now)
+	}
+	wait = max(wait, w.ticks.wait(ticks, now))
+	if wait > 0 {
+		return wait
+	}
+	w.starts = append(w.starts, now)
+	w.ticks.claim(ticks, now)
+	return 0
+}
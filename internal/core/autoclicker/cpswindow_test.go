@@ -0,0 +1,91 @@
+package autoclicker_test
+
+import (
+	"reflect"
+	"slices"
+	"testing"
+	"time"
+
+	"clicker/internal/core/autoclicker"
+	"clicker/internal/core/autoclicker/autoclickertest"
+)
+
+func TestMaxCPSWindowDefersClicks(t *testing.T) {
+	cfg := autoclickertest.Config(true)
+	cfg.CPS = 20
+	cfg.MaxCPSWindow = 10
+
+	result := autoclickertest.Run(t, autoclickertest.Scenario{
+		Config: cfg,
+		Steps: []autoclickertest.Step{
+			autoclickertest.Press(0, cfg.TriggerCode),
+			autoclickertest.Release(1900*time.Millisecond, cfg.TriggerCode),
+		},
+		Until: 3 * time.Second,
+	})
+
+	var want []time.Duration
+	for _, run := range []time.Duration{0, time.Second} {
+		for i := range 10 {
+			want = append(want, run+time.Duration(i)*50*time.Millisecond)
+		}
+	}
+	if got := clickStarts(result.Records, autoclicker.LeftButtonCode); !reflect.DeepEqual(got, want) {
+		t.Fatalf("clicks at %v, want %v", got, want)
+	}
+	if result.Stats.DeferredClicks != 2 {
+		t.Fatalf("DeferredClicks = %d, want 2", result.Stats.DeferredClicks)
+	}
+}
+
+func TestMaxCPSWindowSpansBindings(t *testing.T) {
+	const extraTrigger = autoclicker.LeftButtonCode + 5
+	const extraOutput = autoclicker.LeftButtonCode + 1
+	cfg := autoclickertest.Config(true)
+	cfg.Bindings = []autoclicker.Binding{{TriggerCode: extraTrigger, OutputCode: extraOutput, CPS: 10}}
+	cfg.MaxCPSWindow = 15
+
+	result := autoclickertest.Run(t, autoclickertest.Scenario{
+		Config: cfg,
+		Steps: []autoclickertest.Step{
+			autoclickertest.Press(0, cfg.TriggerCode),
+			autoclickertest.Press(0, extraTrigger),
+			autoclickertest.Release(1950*time.Millisecond, cfg.TriggerCode),
+			autoclickertest.Release(1950*time.Millisecond, extraTrigger),
+		},
+		Until: 3 * time.Second,
+	})
+
+	starts := append(clickStarts(result.Records, autoclicker.LeftButtonCode), clickStarts(result.Records, extraOutput)...)
+	slices.Sort(starts)
+	if len(starts) != 30 {
+		t.Fatalf("%d clicks at %v, want 30", len(starts), starts)
+	}
+	for i := range starts[15:] {
+		if span := starts[i+15] - starts[i]; span < time.Second {
+			t.Fatalf("16 clicks within %v: %v", span, starts[i:i+16])
+		}
+	}
+	if result.Stats.DeferredClicks != 4 {
+		t.Fatalf("DeferredClicks = %d, want 4", result.Stats.DeferredClicks)
+	}
+}
+
+func TestMaxCPSWindowValidation(t *testing.T) {
+	cfg := autoclickertest.Config(true)
+	cfg.MaxCPSWindow = -1
+	if _, err := autoclicker.NewService(cfg, autoclickertest.NewInjector(autoclicker.SystemClock()), &autoclickertest.Logger{}); err == nil {
+		t.Fatalf("NewService() accepted a negative click cap")
+	}
+
+	service, err := autoclicker.NewService(autoclickertest.Config(true), autoclickertest.NewInjector(autoclicker.SystemClock()), &autoclickertest.Logger{})
+	if err != nil {
+		t.Fatalf("NewService() error = %v", err)
+	}
+	if err := service.SetMaxCPSWindow(-1); err == nil {
+		t.Fatalf("SetMaxCPSWindow() accepted a negative cap")
+	}
+	if err := service.SetMaxCPSWindow(12); err != nil || service.MaxCPSWindow() != 12 {
+		t.Fatalf("SetMaxCPSWindow() = %v, MaxCPSWindow() = %v", err, service.MaxCPSWindow())
+	}
+}
@@ -0,0 +1,207 @@
+package autoclicker
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"math/rand"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type RateCurve interface {
+	Start(rng *rand.Rand) func(elapsed time.Duration, baseCPS float64) float64
+	String() string
+}
+
+type sineCurve struct {
+	amplitude float64
+	period    time.Duration
+}
+
+func SineCurve(amplitude float64, period time.Duration) (RateCurve, error) {
+	if amplitude < 0 || amplitude >= 1 {
+		return nil, fmt.Errorf("curve amplitude must be within [0, 1)")
+	}
+	if period <= 0 {
+		return nil, fmt.Errorf("curve period must be > 0")
+	}
+	return sineCurve{amplitude: amplitude, period: period}, nil
+}
+
+func (c sineCurve) Start(*rand.Rand) func(time.Duration, float64) float64 {
+	return func(elapsed time.Duration, baseCPS float64) float64 {
+		phase := 2 * math.Pi * float64(elapsed) / float64(c.period)
+		return baseCPS * (1 + c.amplitude*math.Sin(phase))
+	}
+}
+
+func (c sineCurve) String() string {
+	return fmt.Sprintf("sine ±%.0f%% every %v", c.amplitude*100, c.period)
+}
+
+type randomWalkCurve struct {
+	amplitude float64
+	period    time.Duration
+}
+
+func RandomWalkCurve(amplitude float64, period time.Duration) (RateCurve, error) {
+	if amplitude < 0 || amplitude >= 1 {
+		return nil, fmt.Errorf("curve amplitude must be within [0, 1)")
+	}
+	if period <= 0 {
+		return nil, fmt.Errorf("curve period must be > 0")
+	}
+	return randomWalkCurve{amplitude: amplitude, period: period}, nil
+}
+
+func (c randomWalkCurve) Start(rng *rand.Rand) func(time.Duration, float64) float64 {
+	var offset float64
+	var last time.Duration
+	return func(elapsed time.Duration, baseCPS float64) float64 {
+		if step := elapsed - last; step > 0 {
+			offset += rng.NormFloat64() * c.amplitude * math.Sqrt(float64(step)/float64(c.period))
+			// Reflect off the bounds so the walk does not stick to them.
+			if offset > c.amplitude {
+				offset = 2*c.amplitude - offset
+			}
+			if offset < -c.amplitude {
+				offset = -2*c.amplitude - offset
+			}
+			offset = max(-c.amplitude, min(offset, c.amplitude))
+			last = elapsed
+		}
+		return baseCPS * (1 + offset)
+	}
+}
+
+func (c randomWalkCurve) String() string {
+	return fmt.Sprintf("random walk ±%.0f%% over %v", c.amplitude*100, c.period)
+}
+
+type SchedulePoint struct {
+	At  time.Duration
+	CPS float64
+}
+
+type scheduleCurve struct {
+	points []SchedulePoint
+}
+
+func ScheduleCurve(points []SchedulePoint) (RateCurve, error) {
+	if len(points) == 0 {
+		return nil, fmt.Errorf("schedule has no points")
+	}
+	sorted := make([]SchedulePoint, len(points))
+	copy(sorted, points)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].At < sorted[j].At })
+	for _, point := range sorted {
+		if point.At < 0 {
+			return nil, fmt.Errorf("schedule offset %v must be >= 0", point.At)
+		}
+		if point.CPS <= 0 {
+			return nil, fmt.Errorf("schedule cps at %v must be > 0", point.At)
+		}
+	}
+	return scheduleCurve{points: sorted}, nil
+}
+
+func (c scheduleCurve) Start(*rand.Rand) func(time.Duration, float64) float64 {
+	return func(elapsed time.Duration, _ float64) float64 {
+		return c.rateAt(elapsed)
+	}
+}
+
+func (c scheduleCurve) rateAt(elapsed time.Duration) float64 {
+	i := sort.Search(len(c.points), func(i int) bool { return c.points[i].At > elapsed })
+	switch {
+	case i == 0:
+		return c.points[0].CPS
+	case i == len(c.points):
+		return c.points[i-1].CPS
+	}
+	from, to := c.points[i-1], c.points[i]
+	frac := float64(elapsed-from.At) / float64(to.At-from.At)
+	return from.CPS + frac*(to.CPS-from.CPS)
+}
+
+func (c scheduleCurve) String() string {
+	last := c.points[len(c.points)-1]
+	return fmt.Sprintf("schedule of %d points to %.2f cps at %v", len(c.points), last.CPS, last.At)
+}
+
+func ParseRateSchedule(r io.Reader) (RateCurve, error) {
+	var points []SchedulePoint
+	scanner := bufio.NewScanner(r)
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		fields := strings.Fields(text)
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("schedule line %d: expected OFFSET CPS", line)
+		}
+		at, err := time.ParseDuration(fields[0])
+		if err != nil {
+			return nil, fmt.Errorf("schedule line %d: invalid offset %q", line, fields[0])
+		}
+		cps, err := strconv.ParseFloat(fields[1], 64)
+		if err != nil {
+			return nil, fmt.Errorf("schedule line %d: invalid cps %q", line, fields[1])
+		}
+		points = append(points, SchedulePoint{At: at, CPS: cps})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return ScheduleCurve(points)
+}
+
+type curveSlot struct {
+	model RateCurve
+}
+
+func (s *Service) SetRateCurve(curve RateCurve) {
+	s.primary.curve.Store(&curveSlot{model: curve})
+}
+
+func (s *Service) RateCurve() RateCurve {
+	return s.primary.currentCurve().model
+}
+
+type curveRun struct {
+	slot      *curveSlot
+	rate      func(time.Duration, float64) float64
+	startedAt time.Time
+}
+
+func (c *curveRun) reset() {
+	c.slot = nil
+	c.rate = nil
+}
+
+func (c *curveRun) scale(slot *curveSlot, timing TimingModel, interval time.Duration, now time.Time, rng *rand.Rand) time.Duration {
+	if slot.model == nil {
+		c.reset()
+		return interval
+	}
+	if c.slot != slot {
+		if c.rate == nil {
+			c.startedAt = now
+		}
+		c.slot = slot
+		c.rate = slot.model.Start(rng)
+	}
+	base := intervalCPS(timing.Mean())
+	cps := c.rate(now.Sub(c.startedAt), base)
+	if cps <= 0 || base <= 0 {
+		return interval
+	}
+	return max(time.Duration(float64(interval)*base/cps), minTimingInterval)
+}
@@ -0,0 +1,116 @@
+package autoclicker_test
+
+import (
+	"math"
+	"math/rand"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"clicker/internal/core/autoclicker"
+	"clicker/internal/core/autoclicker/autoclickertest"
+)
+
+func TestSineCurveWobblesAroundBase(t *testing.T) {
+	curve, err := autoclicker.SineCurve(0.25, time.Second)
+	if err != nil {
+		t.Fatalf("SineCurve() error = %v", err)
+	}
+	rate := curve.Start(rand.New(rand.NewSource(1)))
+	for _, tc := range []struct {
+		elapsed time.Duration
+		want    float64
+	}{
+		{0, 10},
+		{250 * time.Millisecond, 12.5},
+		{750 * time.Millisecond, 7.5},
+		{time.Second, 10},
+	} {
+		if got := rate(tc.elapsed, 10); math.Abs(got-tc.want) > 1e-9 {
+			t.Fatalf("rate(%v) = %v, want %v", tc.elapsed, got, tc.want)
+		}
+	}
+}
+
+func TestRandomWalkCurveStaysWithinAmplitude(t *testing.T) {
+	curve, err := autoclicker.RandomWalkCurve(0.2, 500*time.Millisecond)
+	if err != nil {
+		t.Fatalf("RandomWalkCurve() error = %v", err)
+	}
+	rate := curve.Start(rand.New(rand.NewSource(1)))
+	lo, hi := math.Inf(1), math.Inf(-1)
+	for i := range 5000 {
+		got := rate(time.Duration(i)*50*time.Millisecond, 10)
+		lo = math.Min(lo, got)
+		hi = math.Max(hi, got)
+	}
+	if lo < 8-1e-9 || hi > 12+1e-9 {
+		t.Fatalf("walk left [8, 12]: [%v, %v]", lo, hi)
+	}
+	if hi-lo < 2 {
+		t.Fatalf("walk barely moved: [%v, %v]", lo, hi)
+	}
+}
+
+func TestParseRateScheduleInterpolates(t *testing.T) {
+	curve, err := autoclicker.ParseRateSchedule(strings.NewReader("# warm up\n0s 5\n\n1s 15\n2s 10\n"))
+	if err != nil {
+		t.Fatalf("ParseRateSchedule() error = %v", err)
+	}
+	rate := curve.Start(nil)
+	for _, tc := range []struct {
+		elapsed time.Duration
+		want    float64
+	}{
+		{0, 5},
+		{500 * time.Millisecond, 10},
+		{1500 * time.Millisecond, 12.5},
+		{time.Minute, 10},
+	} {
+		if got := rate(tc.elapsed, 99); math.Abs(got-tc.want) > 1e-9 {
+			t.Fatalf("rate(%v) = %v, want %v", tc.elapsed, got, tc.want)
+		}
+	}
+
+	for _, raw := range []string{"", "1s", "soon 10", "1s fast", "1s 0"} {
+		if _, err := autoclicker.ParseRateSchedule(strings.NewReader(raw)); err == nil {
+			t.Fatalf("ParseRateSchedule(%q) succeeded, want an error", raw)
+		}
+	}
+}
+
+func TestScheduleCurveDrivesClickIntervals(t *testing.T) {
+	curve, err := autoclicker.ScheduleCurve([]autoclicker.SchedulePoint{{At: 0, CPS: 5}, {At: 400 * time.Millisecond, CPS: 20}})
+	if err != nil {
+		t.Fatalf("ScheduleCurve() error = %v", err)
+	}
+	cfg := autoclickertest.Config(true)
+	cfg.TriggerCode = autoclicker.LeftButtonCode + 3
+	cfg.ToggleCode = autoclicker.LeftButtonCode + 5
+	cfg.CPS = 10
+	cfg.Curve = curve
+
+	service, clock, injector := startVirtualService(t, cfg)
+	service.SubmitEvent("device", autoclicker.Event{Type: autoclicker.EventTypeKey, Code: cfg.TriggerCode, Value: 1})
+	stepClock(clock, 5)
+	clock.BlockUntil(1)
+
+	// 5 CPS at the start and 12.5 CPS at 200ms, speeding up towards 20 CPS
+	// from there.
+	want := []time.Duration{0, 200 * time.Millisecond, 280 * time.Millisecond}
+	got := keyTimes(injector.Records(), autoclicker.LeftButtonCode, 1)
+	if len(got) < len(want) || !reflect.DeepEqual(got[:len(want)], want) {
+		t.Fatalf("click downs at %v, want %v first", got, want)
+	}
+	for i := len(want); i < len(got); i++ {
+		if interval := got[i] - got[i-1]; interval >= 80*time.Millisecond || interval < 50*time.Millisecond {
+			t.Fatalf("interval %d = %v, want within [50ms, 80ms)", i, interval)
+		}
+	}
+
+	service.SetRateCurve(nil)
+	if service.RateCurve() != nil {
+		t.Fatalf("expected SetRateCurve(nil) to clear the curve")
+	}
+}
@@ -0,0 +1,128 @@
+package autoclicker
+
+import (
+	"fmt"
+	"time"
+)
+
+func (s *Service) SetDebounce(press, release time.Duration) error {
+	if err := validateDebounce(press, release); err != nil {
+		return err
+	}
+	s.debouncePressNanos.Store(press.Nanoseconds())
+	s.debounceReleaseNanos.Store(release.Nanoseconds())
+	return nil
+}
+
+func (s *Service) Debounce() (press, release time.Duration) {
+	return time.Duration(s.debouncePressNanos.Load()), time.Duration(s.debounceReleaseNanos.Load())
+}
+
+func validateDebounce(press, release time.Duration) error {
+	if press < 0 {
+		return fmt.Errorf("press debounce must be >= 0")
+	}
+	if release < 0 {
+		return fmt.Errorf("release debounce must be >= 0")
+	}
+	return nil
+}
+
+type sourceKey struct {
+	source string
+	code   uint16
+}
+
+type debounceState struct {
+	down      bool
+	pressedAt time.Time
+	pending   uint64
+	timer     Timer
+}
+
+type debouncer struct {
+	keys map[sourceKey]*debounceState
+	seq  uint64
+}
+
+func (s *Service) debounceEvent(item sourcedEvent) bool {
+	event := item.event
+	if event.Type != EventTypeKey {
+		return true
+	}
+	key := sourceKey{source: item.source, code: event.Code}
+	state := s.debounce.keys[key]
+	if item.debounceSeq != 0 {
+		// A held back release came due; a press may have dropped it since.
+		if state == nil || state.pending != item.debounceSeq {
+			return false
+		}
+		state.pending = 0
+		state.timer = nil
+		state.down = false
+		return true
+	}
+	if state != nil && state.pending != 0 {
+		switch event.Value {
+		case 1:
+			state.timer.Stop()
+			state.timer = nil
+			state.pending = 0
+			s.bounces.Add(1)
+			return false
+		case 0:
+			return false
+		}
+		return true
+	}
+
+	press, release := s.Debounce()
+	if (press <= 0 && release <= 0) || !s.isTriggerOrToggleKey(event.Code) {
+		return true
+	}
+	if state == nil {
+		if s.debounce.keys == nil {
+			s.debounce.keys = make(map[sourceKey]*debounceState)
+		}
+		state = &debounceState{}
+		s.debounce.keys[key] = state
+	}
+	now := s.clock.Now()
+	switch event.Value {
+	case 1:
+		state.down = true
+		state.pressedAt = now
+	case 0:
+		if !state.down {
+			return true
+		}
+		wait := release
+		if rest := press - now.Sub(state.pressedAt); rest > wait {
+			wait = rest
+		}
+		if wait <= 0 {
+			state.down = false
+			return true
+		}
+		s.debounce.seq++
+		seq := s.debounce.seq
+		state.pending = seq
+		state.timer = s.clock.AfterFunc(wait, func() {
+			s.enqueue(sourcedEvent{source: item.source, event: event, panics: s.panics.Load(), debounceSeq: seq})
+		})
+		return false
+	}
+	return true
+}
+
+func (s *Service) isTriggerOrToggleKey(code uint16) bool {
+	if ChordKey(s.currentToggleCode()) == code {
+		return true
+	}
+	for _, b := range s.currentBindings() {
+		if ChordKey(b.currentTriggerCode()) == code {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,110 @@
+package autoclicker_test
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"clicker/internal/core/autoclicker"
+	"clicker/internal/core/autoclicker/autoclickertest"
+)
+
+func TestDebounceFiltersTriggerChatter(t *testing.T) {
+	cfg := autoclickertest.Config(true)
+	cfg.DebouncePress = 20 * time.Millisecond
+	cfg.DebounceRelease = 10 * time.Millisecond
+
+	result := autoclickertest.Run(t, autoclickertest.Scenario{
+		Config: cfg,
+		Steps: []autoclickertest.Step{
+			// Chatter as the switch closes.
+			autoclickertest.Press(0, cfg.TriggerCode),
+			autoclickertest.Release(2*time.Millisecond, cfg.TriggerCode),
+			autoclickertest.Press(4*time.Millisecond, cfg.TriggerCode),
+			// A bounce in the middle of the hold.
+			autoclickertest.Release(250*time.Millisecond, cfg.TriggerCode),
+			autoclickertest.Press(253*time.Millisecond, cfg.TriggerCode),
+			// The real release takes effect at 560ms.
+			autoclickertest.Release(550*time.Millisecond, cfg.TriggerCode),
+			// A short press still clicks once its release is due.
+			autoclickertest.Press(800*time.Millisecond, cfg.TriggerCode),
+			autoclickertest.Release(805*time.Millisecond, cfg.TriggerCode),
+		},
+		Until: 2 * time.Second,
+	})
+
+	want := []time.Duration{
+		0, 100 * time.Millisecond, 200 * time.Millisecond, 300 * time.Millisecond, 400 * time.Millisecond, 500 * time.Millisecond,
+		800 * time.Millisecond,
+	}
+	if got := clickStarts(result.Records, autoclicker.LeftButtonCode); !reflect.DeepEqual(got, want) {
+		t.Fatalf("clicks at %v, want %v", got, want)
+	}
+	if result.Stats.Holds != 2 {
+		t.Fatalf("Holds = %d, want 2", result.Stats.Holds)
+	}
+	if result.Stats.Bounces != 2 {
+		t.Fatalf("Bounces = %d, want 2", result.Stats.Bounces)
+	}
+}
+
+func TestDebounceIsPerSource(t *testing.T) {
+	cfg := autoclickertest.Config(false)
+	cfg.ToggleSources["other"] = struct{}{}
+	cfg.DebounceRelease = 10 * time.Millisecond
+
+	result := autoclickertest.Run(t, autoclickertest.Scenario{
+		Config: cfg,
+		Steps: []autoclickertest.Step{
+			// A bouncing toggle flips clicking once.
+			autoclickertest.Press(0, cfg.ToggleCode),
+			autoclickertest.Release(3*time.Millisecond, cfg.ToggleCode),
+			autoclickertest.Press(5*time.Millisecond, cfg.ToggleCode),
+			autoclickertest.Release(50*time.Millisecond, cfg.ToggleCode),
+			// Off again; the release is held back until 210ms, and a press
+			// on another source meanwhile is not a bounce of it.
+			autoclickertest.Press(100*time.Millisecond, cfg.ToggleCode),
+			autoclickertest.Release(200*time.Millisecond, cfg.ToggleCode),
+			{At: 205 * time.Millisecond, Source: "other", Event: autoclickertest.Press(0, cfg.ToggleCode).Event},
+			{At: 250 * time.Millisecond, Source: "other", Event: autoclickertest.Release(0, cfg.ToggleCode).Event},
+			autoclickertest.Press(300*time.Millisecond, cfg.TriggerCode),
+			autoclickertest.Release(350*time.Millisecond, cfg.TriggerCode),
+		},
+		Until: time.Second,
+	})
+
+	want := []time.Duration{300 * time.Millisecond}
+	if got := clickStarts(result.Records, autoclicker.LeftButtonCode); !reflect.DeepEqual(got, want) {
+		t.Fatalf("clicks at %v, want %v", got, want)
+	}
+	if result.Stats.Bounces != 1 {
+		t.Fatalf("Bounces = %d, want 1", result.Stats.Bounces)
+	}
+}
+
+func TestDebounceValidation(t *testing.T) {
+	for _, mutate := range []func(*autoclicker.Config){
+		func(cfg *autoclicker.Config) { cfg.DebouncePress = -time.Millisecond },
+		func(cfg *autoclicker.Config) { cfg.DebounceRelease = -time.Millisecond },
+	} {
+		cfg := autoclickertest.Config(true)
+		mutate(&cfg)
+		if _, err := autoclicker.NewService(cfg, autoclickertest.NewInjector(autoclicker.SystemClock()), &autoclickertest.Logger{}); err == nil {
+			t.Fatalf("NewService() accepted a negative debounce window")
+		}
+	}
+
+	service, err := autoclicker.NewService(autoclickertest.Config(true), autoclickertest.NewInjector(autoclicker.SystemClock()), &autoclickertest.Logger{})
+	if err != nil {
+		t.Fatalf("NewService() error = %v", err)
+	}
+	if err := service.SetDebounce(5*time.Millisecond, -time.Millisecond); err == nil {
+		t.Fatalf("SetDebounce() accepted a negative window")
+	}
+	if err := service.SetDebounce(5*time.Millisecond, 8*time.Millisecond); err != nil {
+		t.Fatalf("SetDebounce() error = %v", err)
+	}
+	if press, release := service.Debounce(); press != 5*time.Millisecond || release != 8*time.Millisecond {
+		t.Fatalf("Debounce() = %v, %v", press, release)
+	}
+}
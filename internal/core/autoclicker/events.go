@@ -0,0 +1,102 @@
+package autoclicker
+
+import (
+	"sync"
+	"time"
+)
+
+type StateEventKind uint8
+
+const (
+	StateEnabled StateEventKind = iota + 1
+	StateDisabled
+	StateTriggerDown
+	StateTriggerUp
+	StateClick
+	StateInjectorError
+	StateStopped
+	StateHoldCutoff
+	StatePanic
+)
+
+var stateEventKindNames = map[StateEventKind]string{
+	StateEnabled:       "enabled",
+	StateDisabled:      "disabled",
+	StateTriggerDown:   "trigger-down",
+	StateTriggerUp:     "trigger-up",
+	StateClick:         "click",
+	StateInjectorError: "injector-error",
+	StateStopped:       "stopped",
+	StateHoldCutoff:    "hold-cutoff",
+	StatePanic:         "panic",
+}
+
+func (k StateEventKind) String() string {
+	if name, ok := stateEventKindNames[k]; ok {
+		return name
+	}
+	return "unknown"
+}
+
+type StateEvent struct {
+	Kind   StateEventKind
+	At     time.Time
+	Source string
+	Code   uint16
+	Err    error
+}
+
+type subscriber struct {
+	ch        chan StateEvent
+	closeOnce sync.Once
+}
+
+func (sub *subscriber) close() {
+	sub.closeOnce.Do(func() {
+		close(sub.ch)
+	})
+}
+
+func (s *Service) Subscribe(buffer int) (events <-chan StateEvent, cancel func()) {
+	sub := &subscriber{ch: make(chan StateEvent, max(buffer, 1))}
+
+	s.subsMu.Lock()
+	if s.stopped() {
+		s.subsMu.Unlock()
+		sub.close()
+		return sub.ch, func() {}
+	}
+	s.subs[sub] = struct{}{}
+	s.subsMu.Unlock()
+
+	return sub.ch, func() {
+		s.subsMu.Lock()
+		delete(s.subs, sub)
+		s.subsMu.Unlock()
+		sub.close()
+	}
+}
+
+func (s *Service) publish(event StateEvent) {
+	event.At = s.clock.Now()
+
+	s.subsMu.Lock()
+	defer s.subsMu.Unlock()
+	for sub := range s.subs {
+		select {
+		case sub.ch <- event:
+		default:
+		}
+	}
+}
+
+func (s *Service) closeSubscribers() {
+	s.publish(StateEvent{Kind: StateStopped})
+
+	s.subsMu.Lock()
+	defer s.subsMu.Unlock()
+	for sub := range s.subs {
+		sub.close()
+		delete(s.subs, sub)
+	}
+}
@@ -0,0 +1,108 @@
+package autoclicker_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"clicker/internal/core/autoclicker"
+	"clicker/internal/core/autoclicker/autoclickertest"
+)
+
+func nextStateEvent(t *testing.T, events <-chan autoclicker.StateEvent, kind autoclicker.StateEventKind) autoclicker.StateEvent {
+	t.Helper()
+	deadline := time.After(time.Second)
+	for {
+		select {
+		case event, ok := <-events:
+			if !ok {
+				t.Fatalf("subscription closed while waiting for %s", kind)
+			}
+			if event.Kind == kind {
+				return event
+			}
+		case <-deadline:
+			t.Fatalf("timed out waiting for %s", kind)
+		}
+	}
+}
+
+func TestSubscribeReportsStateChanges(t *testing.T) {
+	cfg := autoclickertest.Config(false)
+	cfg.TriggerCode = autoclicker.LeftButtonCode + 3
+	cfg.ToggleCode = autoclicker.LeftButtonCode + 5
+	cfg.CPS = 200
+
+	service, err := autoclicker.NewService(cfg, &autoclickertest.Injector{}, &autoclickertest.Logger{})
+	if err != nil {
+		t.Fatalf("NewService() error = %v", err)
+	}
+	events, cancel := service.Subscribe(64)
+	defer cancel()
+	service.Start()
+	defer service.Stop()
+
+	service.SetEnabled(true)
+	nextStateEvent(t, events, autoclicker.StateEnabled)
+
+	service.SubmitEvent("device", autoclicker.Event{Type: autoclicker.EventTypeKey, Code: cfg.TriggerCode, Value: 1})
+	down := nextStateEvent(t, events, autoclicker.StateTriggerDown)
+	if down.Source != "device" || down.Code != cfg.TriggerCode {
+		t.Fatalf("unexpected trigger down event: %#v", down)
+	}
+	click := nextStateEvent(t, events, autoclicker.StateClick)
+	if click.Code != autoclicker.LeftButtonCode {
+		t.Fatalf("click event code = %d, want %d", click.Code, autoclicker.LeftButtonCode)
+	}
+
+	service.SubmitEvent("device", autoclicker.Event{Type: autoclicker.EventTypeKey, Code: cfg.TriggerCode, Value: 0})
+	nextStateEvent(t, events, autoclicker.StateTriggerUp)
+
+	service.SetEnabled(false)
+	nextStateEvent(t, events, autoclicker.StateDisabled)
+}
+
+func TestSubscribeReportsInjectorErrors(t *testing.T) {
+	injector := &autoclickertest.Injector{}
+	injector.Fail(errors.New("injector unavailable"))
+	service, err := autoclicker.NewService(autoclickertest.Config(true), injector, &autoclickertest.Logger{})
+	if err != nil {
+		t.Fatalf("NewService() error = %v", err)
+	}
+	events, cancel := service.Subscribe(4)
+	defer cancel()
+
+	_ = service.WriteEvents(autoclicker.Event{Type: autoclicker.EventTypeSyn, Code: autoclicker.SynReportCode})
+	if event := nextStateEvent(t, events, autoclicker.StateInjectorError); event.Err == nil {
+		t.Fatalf("expected injector error event to carry the error")
+	}
+}
+
+func TestSubscriptionClosesOnStopAndCancel(t *testing.T) {
+	service, err := autoclicker.NewService(autoclickertest.Config(true), &autoclickertest.Injector{}, &autoclickertest.Logger{})
+	if err != nil {
+		t.Fatalf("NewService() error = %v", err)
+	}
+
+	cancelled, cancel := service.Subscribe(4)
+	cancel()
+	cancel()
+	if _, ok := <-cancelled; ok {
+		t.Fatalf("expected cancelled subscription to be closed")
+	}
+
+	events, cancel := service.Subscribe(4)
+	defer cancel()
+	service.Start()
+	service.Stop()
+
+	nextStateEvent(t, events, autoclicker.StateStopped)
+	if _, ok := <-events; ok {
+		t.Fatalf("expected subscription to close after stop")
+	}
+
+	late, _ := service.Subscribe(4)
+	if _, ok := <-late; ok {
+		t.Fatalf("expected subscription after stop to be closed")
+	}
+}
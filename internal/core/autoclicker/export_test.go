@@ -0,0 +1,47 @@
+package autoclicker
+
+import (
+	"math/rand"
+	"time"
+)
+
+const MinTimingInterval = minTimingInterval
+
+func (s *Service) Primary() *bindingState { return s.primary }
+
+func (s *Service) BindingStates() []*bindingState { return s.currentBindings() }
+
+func (s *Service) WriteEvents(events ...Event) error { return s.writeEvents(events...) }
+
+func (s *Service) IsCodeHeld(code uint16) bool { return s.isCodeHeld(code) }
+
+func (s *Service) HandleEvent(source string, event Event) { s.handleEvent(source, event) }
+
+func (s *Service) HandleTriggerEvent(b *bindingState, source string, value int32) {
+	s.handleTriggerEvent(b, source, value)
+}
+
+func (s *Service) WaitWithWake(b *bindingState, d time.Duration) bool { return s.waitWithWake(b, d) }
+
+func (s *Service) ClickOnce(b *bindingState, rng *rand.Rand, codes []uint16, interval, down time.Duration) bool {
+	return s.clickOnce(b, rng, codes, interval, down)
+}
+
+// AgePress moves the start of the current press of b back by d.
+func (s *Service) AgePress(b *bindingState, d time.Duration) {
+	s.stateMu.Lock()
+	defer s.stateMu.Unlock()
+	b.pressedAt = b.pressedAt.Add(-d)
+}
+
+func (b *bindingState) Holding() bool { return b.holding.Load() }
+
+func (b *bindingState) WakeCh() <-chan struct{} { return b.wakeCh }
+
+func (b *bindingState) SignalWake() { b.signalWake() }
+
+func (b *bindingState) OutputCode() uint16 { return b.currentOutputCode() }
+
+func (b *bindingState) JitterPixels() int32 { return b.currentJitterPixels() }
+
+func (b *bindingState) ClickDownModel() ClickDownModel { return b.currentClickDown() }
@@ -0,0 +1,160 @@
+package autoclicker
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+type ToggleGesture uint8
+
+const (
+	ToggleSingle ToggleGesture = iota
+	ToggleDoubleTap
+	ToggleLongPress
+)
+
+const (
+	DefaultDoubleTapWindow    = 300 * time.Millisecond
+	DefaultLongPressThreshold = 500 * time.Millisecond
+)
+
+var toggleGestureNames = map[ToggleGesture]string{
+	ToggleSingle:    "single",
+	ToggleDoubleTap: "double",
+	ToggleLongPress: "long",
+}
+
+func (g ToggleGesture) String() string {
+	if name, ok := toggleGestureNames[g]; ok {
+		return name
+	}
+	return fmt.Sprintf("ToggleGesture(%d)", uint8(g))
+}
+
+func ParseToggleGesture(raw string) (ToggleGesture, error) {
+	value := strings.ToLower(strings.TrimSpace(raw))
+	for gesture, name := range toggleGestureNames {
+		if value == name {
+			return gesture, nil
+		}
+	}
+	return ToggleSingle, fmt.Errorf("unknown toggle gesture %q (expected single, double or long)", raw)
+}
+
+func normalizeToggleGesture(cfg Config) (Config, error) {
+	if _, ok := toggleGestureNames[cfg.ToggleGesture]; !ok {
+		return cfg, fmt.Errorf("invalid toggle gesture %d", cfg.ToggleGesture)
+	}
+	if cfg.DoubleTapWindow < 0 {
+		return cfg, fmt.Errorf("double-tap window must be >= 0")
+	}
+	if cfg.LongPressThreshold < 0 {
+		return cfg, fmt.Errorf("long-press threshold must be >= 0")
+	}
+	if cfg.DoubleTapWindow == 0 {
+		cfg.DoubleTapWindow = DefaultDoubleTapWindow
+	}
+	if cfg.LongPressThreshold == 0 {
+		cfg.LongPressThreshold = DefaultLongPressThreshold
+	}
+	return cfg, nil
+}
+
+// Long-press timers fire off the event loop, so gestureState has its own
+// lock. SetEnabled is never called with it held.
+type gestureState struct {
+	mu       sync.Mutex
+	lastTap  time.Time
+	held     bool
+	pressSeq uint64
+	timer    Timer
+}
+
+func (s *Service) handleToggleEvent(value int32) {
+	cfg := s.config()
+	switch cfg.ToggleGesture {
+	case ToggleDoubleTap:
+		if value != 1 {
+			return
+		}
+		now := s.clock.Now()
+		s.gesture.mu.Lock()
+		double := !s.gesture.lastTap.IsZero() && now.Sub(s.gesture.lastTap) <= cfg.DoubleTapWindow
+		if double {
+			s.gesture.lastTap = time.Time{}
+		} else {
+			s.gesture.lastTap = now
+		}
+		s.gesture.mu.Unlock()
+		if double {
+			s.SetEnabled(!s.enabled.Load())
+		}
+	case ToggleLongPress:
+		s.gesture.mu.Lock()
+		defer s.gesture.mu.Unlock()
+		switch value {
+		case 1:
+			if s.gesture.held {
+				return
+			}
+			s.gesture.held = true
+		case 0:
+			if !s.gesture.held {
+				return
+			}
+			s.gesture.held = false
+		default:
+			return
+		}
+		s.gesture.pressSeq++
+		s.gesture.stopTimer()
+		if s.gesture.held {
+			seq := s.gesture.pressSeq
+			s.gesture.timer = s.clock.AfterFunc(cfg.LongPressThreshold, func() {
+				s.longPressed(seq)
+			})
+		}
+	default:
+		if value == 1 {
+			s.SetEnabled(!s.enabled.Load())
+		}
+	}
+}
+
+func (s *Service) longPressed(seq uint64) {
+	s.gesture.mu.Lock()
+	current := s.gesture.held && s.gesture.pressSeq == seq
+	if current {
+		s.gesture.timer = nil
+	}
+	s.gesture.mu.Unlock()
+	if !current || s.stopped() {
+		return
+	}
+	s.SetEnabled(!s.enabled.Load())
+}
+
+func (s *Service) toggleHeld() bool {
+	s.gesture.mu.Lock()
+	defer s.gesture.mu.Unlock()
+	return s.gesture.held
+}
+
+func (s *Service) resetGesture() {
+	s.gesture.mu.Lock()
+	defer s.gesture.mu.Unlock()
+	s.gesture.lastTap = time.Time{}
+	s.gesture.held = false
+	s.gesture.pressSeq++
+	s.gesture.stopTimer()
+}
+
+// Callers must hold mu.
+func (g *gestureState) stopTimer() {
+	if g.timer != nil {
+		g.timer.Stop()
+		g.timer = nil
+	}
+}
@@ -0,0 +1,146 @@
+package autoclicker_test
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"clicker/internal/core/autoclicker"
+	"clicker/internal/core/autoclicker/autoclickertest"
+)
+
+func TestToggleDoubleTap(t *testing.T) {
+	cfg := autoclickertest.Config(false)
+	cfg.ToggleGesture = autoclicker.ToggleDoubleTap
+	cfg.DoubleTapWindow = 300 * time.Millisecond
+
+	result := autoclickertest.Run(t, autoclickertest.Scenario{
+		Config: cfg,
+		Steps: []autoclickertest.Step{
+			// A single tap does not toggle.
+			autoclickertest.Press(0, cfg.ToggleCode),
+			autoclickertest.Release(50*time.Millisecond, cfg.ToggleCode),
+			autoclickertest.Press(100*time.Millisecond, cfg.TriggerCode),
+			autoclickertest.Release(150*time.Millisecond, cfg.TriggerCode),
+			// Too slow for a double tap with the first press, but it starts
+			// the one the next press completes.
+			autoclickertest.Press(500*time.Millisecond, cfg.ToggleCode),
+			autoclickertest.Release(550*time.Millisecond, cfg.ToggleCode),
+			autoclickertest.Press(700*time.Millisecond, cfg.ToggleCode),
+			autoclickertest.Release(750*time.Millisecond, cfg.ToggleCode),
+			autoclickertest.Press(800*time.Millisecond, cfg.TriggerCode),
+			autoclickertest.Release(950*time.Millisecond, cfg.TriggerCode),
+		},
+		Until: 2 * time.Second,
+	})
+
+	want := []time.Duration{800 * time.Millisecond, 900 * time.Millisecond}
+	if got := clickStarts(result.Records, autoclicker.LeftButtonCode); !reflect.DeepEqual(got, want) {
+		t.Fatalf("clicks at %v, want %v", got, want)
+	}
+}
+
+func TestToggleLongPress(t *testing.T) {
+	cfg := autoclickertest.Config(false)
+	cfg.ToggleGesture = autoclicker.ToggleLongPress
+	cfg.LongPressThreshold = 500 * time.Millisecond
+	repeat := autoclickertest.Press(800*time.Millisecond, cfg.ToggleCode)
+	repeat.Event.Value = 2
+
+	result := autoclickertest.Run(t, autoclickertest.Scenario{
+		Config: cfg,
+		Steps: []autoclickertest.Step{
+			// Released before the threshold.
+			autoclickertest.Press(0, cfg.ToggleCode),
+			autoclickertest.Release(300*time.Millisecond, cfg.ToggleCode),
+			autoclickertest.Press(350*time.Millisecond, cfg.TriggerCode),
+			autoclickertest.Release(450*time.Millisecond, cfg.TriggerCode),
+			// Toggles at 1s while still held; key repeat changes nothing.
+			autoclickertest.Press(500*time.Millisecond, cfg.ToggleCode),
+			repeat,
+			autoclickertest.Press(1100*time.Millisecond, cfg.TriggerCode),
+			autoclickertest.Release(1200*time.Millisecond, cfg.ToggleCode),
+			autoclickertest.Release(1250*time.Millisecond, cfg.TriggerCode),
+		},
+		Until: 3 * time.Second,
+	})
+
+	want := []time.Duration{1100 * time.Millisecond, 1200 * time.Millisecond}
+	if got := clickStarts(result.Records, autoclicker.LeftButtonCode); !reflect.DeepEqual(got, want) {
+		t.Fatalf("clicks at %v, want %v", got, want)
+	}
+}
+
+func TestToggleLongPressModifierReleasedFirst(t *testing.T) {
+	cfg := autoclickertest.Config(false)
+	cfg.ToggleCode = autoclicker.Chord(keyF8, autoclicker.ModCtrl)
+	cfg.ToggleGesture = autoclicker.ToggleLongPress
+	cfg.LongPressThreshold = 500 * time.Millisecond
+
+	result := autoclickertest.Run(t, autoclickertest.Scenario{
+		Config: cfg,
+		Steps: []autoclickertest.Step{
+			// The key is released before the threshold, after the modifier.
+			autoclickertest.Press(0, keyLeftCtrl),
+			autoclickertest.Press(10*time.Millisecond, keyF8),
+			autoclickertest.Release(100*time.Millisecond, keyLeftCtrl),
+			autoclickertest.Release(200*time.Millisecond, keyF8),
+			autoclickertest.Press(800*time.Millisecond, cfg.TriggerCode),
+			autoclickertest.Release(1000*time.Millisecond, cfg.TriggerCode),
+		},
+		Until: 2 * time.Second,
+	})
+
+	if got := clickStarts(result.Records, autoclicker.LeftButtonCode); len(got) != 0 {
+		t.Fatalf("clicks at %v, want none", got)
+	}
+}
+
+func TestPanicCancelsLongPress(t *testing.T) {
+	cfg := autoclickertest.Config(false)
+	cfg.ToggleGesture = autoclicker.ToggleLongPress
+	cfg.LongPressThreshold = 500 * time.Millisecond
+	cfg.PanicCode = autoclicker.LeftButtonCode + 5
+
+	result := autoclickertest.Run(t, autoclickertest.Scenario{
+		Config: cfg,
+		Steps: []autoclickertest.Step{
+			// The toggle is still held when the threshold passes.
+			autoclickertest.Press(0, cfg.ToggleCode),
+			autoclickertest.Press(200*time.Millisecond, cfg.PanicCode),
+			autoclickertest.Release(210*time.Millisecond, cfg.PanicCode),
+			autoclickertest.Release(900*time.Millisecond, cfg.ToggleCode),
+			autoclickertest.Press(1000*time.Millisecond, cfg.TriggerCode),
+			autoclickertest.Release(1200*time.Millisecond, cfg.TriggerCode),
+		},
+		Until: 2 * time.Second,
+	})
+
+	if got := clickStarts(result.Records, autoclicker.LeftButtonCode); len(got) != 0 {
+		t.Fatalf("clicks at %v, want none", got)
+	}
+}
+
+func TestToggleGestureValidation(t *testing.T) {
+	for _, mutate := range []func(*autoclicker.Config){
+		func(cfg *autoclicker.Config) { cfg.ToggleGesture = 7 },
+		func(cfg *autoclicker.Config) { cfg.DoubleTapWindow = -time.Millisecond },
+		func(cfg *autoclicker.Config) { cfg.LongPressThreshold = -time.Millisecond },
+	} {
+		cfg := autoclickertest.Config(true)
+		mutate(&cfg)
+		if _, err := autoclicker.NewService(cfg, autoclickertest.NewInjector(autoclicker.SystemClock()), &autoclickertest.Logger{}); err == nil {
+			t.Fatalf("NewService() accepted an invalid toggle gesture config")
+		}
+	}
+
+	for _, gesture := range []autoclicker.ToggleGesture{autoclicker.ToggleSingle, autoclicker.ToggleDoubleTap, autoclicker.ToggleLongPress} {
+		parsed, err := autoclicker.ParseToggleGesture(gesture.String())
+		if err != nil || parsed != gesture {
+			t.Fatalf("ParseToggleGesture(%q) = %v, %v", gesture, parsed, err)
+		}
+	}
+	if _, err := autoclicker.ParseToggleGesture("triple"); err == nil {
+		t.Fatalf("expected an unknown toggle gesture to be rejected")
+	}
+}
@@ -0,0 +1,61 @@
+package autoclicker
+
+import (
+	"fmt"
+	"time"
+)
+
+func (s *Service) SetHoldDelay(d time.Duration) error {
+	if d < 0 {
+		return fmt.Errorf("hold delay must be >= 0")
+	}
+	s.holdDelayNanos.Store(d.Nanoseconds())
+	return nil
+}
+
+func (s *Service) HoldDelay() time.Duration {
+	return time.Duration(s.holdDelayNanos.Load())
+}
+
+// Callers must hold stateMu.
+func (s *Service) armHoldDelay(b *bindingState, delay time.Duration) {
+	b.stopHoldDelay()
+	b.delaying = true
+	b.delaySeq++
+	seq := b.delaySeq
+	b.holdDelayTimer = s.clock.AfterFunc(delay, func() {
+		s.holdDelayElapsed(b, seq)
+	})
+}
+
+func (s *Service) holdDelayElapsed(b *bindingState, seq uint64) {
+	s.stateMu.Lock()
+	defer s.stateMu.Unlock()
+	defer s.syncHoldStats()
+
+	if s.stopped() || !b.delaying || b.delaySeq != seq || !s.enabled.Load() {
+		return
+	}
+	b.delaying = false
+	b.holdDelayTimer = nil
+	s.handleTriggerPress(b)
+	s.maybeNeutralizeTriggerHold(b)
+}
+
+func (s *Service) replayShortPress(release Event) {
+	_ = s.writeEvents(
+		Event{Type: EventTypeKey, Code: release.Code, Value: 1},
+		Event{Type: EventTypeSyn, Code: SynReportCode, Value: 0},
+		release,
+		Event{Type: EventTypeSyn, Code: SynReportCode, Value: 0},
+	)
+}
+
+// Callers must hold Service.stateMu.
+func (b *bindingState) stopHoldDelay() {
+	b.delaying = false
+	if b.holdDelayTimer != nil {
+		b.holdDelayTimer.Stop()
+		b.holdDelayTimer = nil
+	}
+}
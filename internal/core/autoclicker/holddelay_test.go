@@ -0,0 +1,92 @@
+package autoclicker_test
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"clicker/internal/core/autoclicker"
+	"clicker/internal/core/autoclicker/autoclickertest"
+)
+
+func TestHoldDelayPassesShortPressThrough(t *testing.T) {
+	cfg := autoclickertest.Config(true)
+	cfg.TriggerCode = autoclicker.LeftButtonCode
+	cfg.GrabSources = map[string]struct{}{autoclickertest.Source: {}}
+	cfg.GrabEnabled = true
+	cfg.HoldDelay = 200 * time.Millisecond
+
+	result := autoclickertest.Run(t, autoclickertest.Scenario{
+		Config: cfg,
+		Steps: []autoclickertest.Step{
+			// Released within the delay: one ordinary click on release.
+			autoclickertest.Press(0, cfg.TriggerCode),
+			autoclickertest.Release(100*time.Millisecond, cfg.TriggerCode),
+			// Held past it: clicking starts once the delay is up.
+			autoclickertest.Press(500*time.Millisecond, cfg.TriggerCode),
+			autoclickertest.Release(950*time.Millisecond, cfg.TriggerCode),
+		},
+		Until: 2 * time.Second,
+	})
+
+	want := "" +
+		"100ms key 0x110 1\n" +
+		"100ms key 0x110 0\n" +
+		"700ms key 0x110 1\n" +
+		"700ms key 0x110 0\n" +
+		"800ms key 0x110 1\n" +
+		"800ms key 0x110 0\n" +
+		"900ms key 0x110 1\n" +
+		"900ms key 0x110 0\n"
+	if got := autoclickertest.Format(result.Records); got != want {
+		t.Fatalf("records:\n%s\nwant:\n%s", got, want)
+	}
+	if result.Stats.Holds != 1 {
+		t.Fatalf("Holds = %d, want 1", result.Stats.Holds)
+	}
+}
+
+func TestHoldDelayWithoutGrab(t *testing.T) {
+	cfg := autoclickertest.Config(true)
+	cfg.TriggerSources["other"] = struct{}{}
+	cfg.HoldDelay = 150 * time.Millisecond
+
+	result := autoclickertest.Run(t, autoclickertest.Scenario{
+		Config: cfg,
+		Steps: []autoclickertest.Step{
+			// The source saw the short press itself; nothing is written.
+			autoclickertest.Press(0, cfg.TriggerCode),
+			autoclickertest.Release(100*time.Millisecond, cfg.TriggerCode),
+			// A second source keeps the press going past the delay.
+			autoclickertest.Press(200*time.Millisecond, cfg.TriggerCode),
+			{At: 250 * time.Millisecond, Source: "other", Event: autoclickertest.Press(0, cfg.TriggerCode).Event},
+			autoclickertest.Release(300*time.Millisecond, cfg.TriggerCode),
+			{At: 600 * time.Millisecond, Source: "other", Event: autoclickertest.Release(0, cfg.TriggerCode).Event},
+		},
+		Until: time.Second,
+	})
+
+	want := []time.Duration{350 * time.Millisecond, 450 * time.Millisecond, 550 * time.Millisecond}
+	if got := clickStarts(result.Records, autoclicker.LeftButtonCode); !reflect.DeepEqual(got, want) {
+		t.Fatalf("clicks at %v, want %v", got, want)
+	}
+}
+
+func TestHoldDelayValidation(t *testing.T) {
+	cfg := autoclickertest.Config(true)
+	cfg.HoldDelay = -time.Millisecond
+	if _, err := autoclicker.NewService(cfg, autoclickertest.NewInjector(autoclicker.SystemClock()), &autoclickertest.Logger{}); err == nil {
+		t.Fatalf("NewService() accepted a negative hold delay")
+	}
+
+	service, err := autoclicker.NewService(autoclickertest.Config(true), autoclickertest.NewInjector(autoclicker.SystemClock()), &autoclickertest.Logger{})
+	if err != nil {
+		t.Fatalf("NewService() error = %v", err)
+	}
+	if err := service.SetHoldDelay(-time.Millisecond); err == nil {
+		t.Fatalf("SetHoldDelay() accepted a negative delay")
+	}
+	if err := service.SetHoldDelay(time.Second); err != nil || service.HoldDelay() != time.Second {
+		t.Fatalf("SetHoldDelay() = %v, HoldDelay() = %v", err, service.HoldDelay())
+	}
+}
@@ -0,0 +1,78 @@
+package autoclicker
+
+import "sync"
+
+type activity struct {
+	mu   sync.Mutex
+	cond *sync.Cond
+	busy int
+}
+
+func newActivity() *activity {
+	a := &activity{}
+	a.cond = sync.NewCond(&a.mu)
+	return a
+}
+
+func (a *activity) add(delta int) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.busy += delta
+	a.cond.Broadcast()
+}
+
+func (a *activity) park(b *bindingState, wake bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if wake && len(b.wakeCh) > 0 {
+		return
+	}
+	b.parked = true
+	b.parkedForWake = wake
+	a.busy--
+	a.cond.Broadcast()
+}
+
+func (a *activity) resume(b *bindingState) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.resumeLocked(b)
+}
+
+func (a *activity) resumeLocked(b *bindingState) {
+	if !b.parked {
+		return
+	}
+	b.parked = false
+	a.busy++
+	a.cond.Broadcast()
+}
+
+func (a *activity) wake(b *bindingState) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	select {
+	case b.wakeCh <- struct{}{}:
+	default:
+	}
+	if b.parked && b.parkedForWake {
+		a.resumeLocked(b)
+	}
+}
+
+func (a *activity) wait(stop <-chan struct{}) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	for a.busy > 0 {
+		select {
+		case <-stop:
+			return
+		default:
+		}
+		a.cond.Wait()
+	}
+}
+
+func (s *Service) WaitIdle() {
+	s.activity.wait(s.stopCh)
+}
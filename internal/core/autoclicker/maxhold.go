@@ -0,0 +1,57 @@
+package autoclicker
+
+import (
+	"fmt"
+	"time"
+)
+
+func (s *Service) SetMaxHold(d time.Duration) error {
+	if d < 0 {
+		return fmt.Errorf("max hold must be >= 0")
+	}
+	s.maxHoldNanos.Store(d.Nanoseconds())
+	return nil
+}
+
+func (s *Service) MaxHold() time.Duration {
+	return time.Duration(s.maxHoldNanos.Load())
+}
+
+// Callers must hold stateMu.
+func (s *Service) armMaxHold(b *bindingState) {
+	b.stopMaxHold()
+	limit := s.MaxHold()
+	if limit <= 0 {
+		return
+	}
+	seq := b.pressSeq.Load()
+	b.maxHoldTimer = s.clock.AfterFunc(limit, func() {
+		s.cutOffHold(b, seq, limit)
+	})
+}
+
+func (s *Service) cutOffHold(b *bindingState, seq uint64, limit time.Duration) {
+	s.stateMu.Lock()
+	defer s.stateMu.Unlock()
+	defer s.syncHoldStats()
+
+	if s.stopped() || b.pressSeq.Load() != seq || len(b.pressedSources) == 0 {
+		return
+	}
+	b.resetTrigger()
+	b.cutOff.Store(true)
+	s.releaseCodes(b.outputCodes()...)
+	b.signalWake()
+
+	trigger := b.currentTriggerCode()
+	s.logger.Warn("Trigger held too long, stopped clicking", "trigger", trigger, "max_hold", limit)
+	s.publish(StateEvent{Kind: StateHoldCutoff, Code: trigger})
+}
+
+// Callers must hold Service.stateMu.
+func (b *bindingState) stopMaxHold() {
+	if b.maxHoldTimer != nil {
+		b.maxHoldTimer.Stop()
+		b.maxHoldTimer = nil
+	}
+}
@@ -0,0 +1,138 @@
+package autoclicker_test
+
+import (
+	"log/slog"
+	"reflect"
+	"testing"
+	"time"
+
+	"clicker/internal/core/autoclicker"
+	"clicker/internal/core/autoclicker/autoclickertest"
+)
+
+func TestMaxHoldCutsOffMissedRelease(t *testing.T) {
+	cfg := autoclickertest.Config(true)
+	cfg.MaxHold = 450 * time.Millisecond
+	repeat := autoclickertest.Press(700*time.Millisecond, cfg.TriggerCode)
+	repeat.Event.Value = 2
+
+	result := autoclickertest.Run(t, autoclickertest.Scenario{
+		Config: cfg,
+		Steps: []autoclickertest.Step{
+			// The release of this press never arrives.
+			autoclickertest.Press(0, cfg.TriggerCode),
+			// Key repeat of the stuck trigger does not restart clicking.
+			repeat,
+			// A new press does, and is cut off in turn.
+			autoclickertest.Press(time.Second, cfg.TriggerCode),
+		},
+		Until: 3 * time.Second,
+	})
+
+	want := []time.Duration{
+		0, 100 * time.Millisecond, 200 * time.Millisecond, 300 * time.Millisecond, 400 * time.Millisecond,
+		time.Second, 1100 * time.Millisecond, 1200 * time.Millisecond, 1300 * time.Millisecond, 1400 * time.Millisecond,
+	}
+	if got := clickStarts(result.Records, autoclicker.LeftButtonCode); !reflect.DeepEqual(got, want) {
+		t.Fatalf("clicks at %v, want %v", got, want)
+	}
+	if result.Stats.Holds != 2 {
+		t.Fatalf("Holds = %d, want 2", result.Stats.Holds)
+	}
+	var warnings int
+	for _, entry := range result.Log {
+		if entry.Level == slog.LevelWarn && entry.Msg == "Trigger held too long, stopped clicking" {
+			warnings++
+		}
+	}
+	if warnings != 2 {
+		t.Fatalf("logged %d cutoff warnings, want 2: %v", warnings, result.Log)
+	}
+}
+
+func TestMaxHoldSkipsRampDown(t *testing.T) {
+	cfg := autoclickertest.Config(true)
+	cfg.MaxHold = 250 * time.Millisecond
+	cfg.Ramp = autoclicker.Ramp{Down: time.Second}
+
+	result := autoclickertest.Run(t, autoclickertest.Scenario{
+		Config: cfg,
+		Steps:  []autoclickertest.Step{autoclickertest.Press(0, cfg.TriggerCode)},
+		Until:  2 * time.Second,
+	})
+
+	want := []time.Duration{0, 100 * time.Millisecond, 200 * time.Millisecond}
+	if got := clickStarts(result.Records, autoclicker.LeftButtonCode); !reflect.DeepEqual(got, want) {
+		t.Fatalf("clicks at %v, want %v", got, want)
+	}
+}
+
+func TestMaxHoldIgnoresReleasedPresses(t *testing.T) {
+	cfg := autoclickertest.Config(true)
+	cfg.MaxHold = 250 * time.Millisecond
+
+	result := autoclickertest.Run(t, autoclickertest.Scenario{
+		Config: cfg,
+		Steps: []autoclickertest.Step{
+			autoclickertest.Press(0, cfg.TriggerCode),
+			autoclickertest.Release(150*time.Millisecond, cfg.TriggerCode),
+			// Pressed again before the first press's cutoff would fire.
+			autoclickertest.Press(200*time.Millisecond, cfg.TriggerCode),
+			autoclickertest.Release(400*time.Millisecond, cfg.TriggerCode),
+		},
+		Until: time.Second,
+	})
+
+	want := []time.Duration{0, 100 * time.Millisecond, 200 * time.Millisecond, 300 * time.Millisecond, 400 * time.Millisecond}
+	if got := clickStarts(result.Records, autoclicker.LeftButtonCode); !reflect.DeepEqual(got, want) {
+		t.Fatalf("clicks at %v, want %v", got, want)
+	}
+	for _, entry := range result.Log {
+		if entry.Level == slog.LevelWarn {
+			t.Fatalf("unexpected warning %q", entry.Msg)
+		}
+	}
+}
+
+func TestMaxHoldPublishesCutoff(t *testing.T) {
+	clock := autoclicker.NewVirtualClock(autoclickertest.Epoch)
+	cfg := autoclickertest.Config(true)
+	cfg.Clock = clock
+	service, err := autoclicker.NewService(cfg, autoclickertest.NewInjector(clock), &autoclickertest.Logger{})
+	if err != nil {
+		t.Fatalf("NewService() error = %v", err)
+	}
+	if err := service.SetMaxHold(-time.Second); err == nil {
+		t.Fatalf("SetMaxHold() accepted a negative duration")
+	}
+	if err := service.SetMaxHold(time.Second); err != nil {
+		t.Fatalf("SetMaxHold() error = %v", err)
+	}
+	events, cancel := service.Subscribe(64)
+	defer cancel()
+	service.Start()
+	defer service.Stop()
+
+	service.SubmitEvent(autoclickertest.Source, autoclicker.Event{Type: autoclicker.EventTypeKey, Code: cfg.TriggerCode, Value: 1})
+	service.WaitIdle()
+	clock.Advance(2 * time.Second)
+	service.WaitIdle()
+
+	for {
+		select {
+		case event := <-events:
+			if event.Kind != autoclicker.StateHoldCutoff {
+				continue
+			}
+			if event.Code != cfg.TriggerCode {
+				t.Fatalf("cutoff code = %d, want %d", event.Code, cfg.TriggerCode)
+			}
+			if want := autoclickertest.Epoch.Add(time.Second); !event.At.Equal(want) {
+				t.Fatalf("cutoff at %v, want %v", event.At, want)
+			}
+			return
+		default:
+			t.Fatalf("no hold cutoff event published")
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package autoclicker
+
+import (
+	"fmt"
+	"strings"
+)
+
+type PanicAction uint8
+
+const (
+	PanicDisable PanicAction = iota
+	PanicUngrab
+	PanicExit
+)
+
+var panicActionNames = map[PanicAction]string{
+	PanicDisable: "disable",
+	PanicUngrab:  "ungrab",
+	PanicExit:    "exit",
+}
+
+func (a PanicAction) String() string {
+	if name, ok := panicActionNames[a]; ok {
+		return name
+	}
+	return fmt.Sprintf("PanicAction(%d)", uint8(a))
+}
+
+func ParsePanicAction(raw string) (PanicAction, error) {
+	value := strings.ToLower(strings.TrimSpace(raw))
+	for action, name := range panicActionNames {
+		if value == name {
+			return action, nil
+		}
+	}
+	return PanicDisable, fmt.Errorf("unknown panic action %q (expected disable, ungrab or exit)", raw)
+}
+
+func validatePanic(cfg Config) error {
+	if _, ok := panicActionNames[cfg.PanicAction]; !ok {
+		return fmt.Errorf("invalid panic action %d", cfg.PanicAction)
+	}
+	if cfg.PanicCode == 0 {
+		return nil
+	}
+	// Panic events skip the event queue, so the modifier state a chord
+	// needs is not known when they arrive.
+	if ChordModifiers(cfg.PanicCode) != 0 {
+		return fmt.Errorf("panic code cannot be a chord")
+	}
+	if cfg.PanicCode == ChordKey(cfg.ToggleCode) {
+		return fmt.Errorf("panic code must differ from the toggle code")
+	}
+	if cfg.PanicCode == ChordKey(cfg.TriggerCode) {
+		return fmt.Errorf("panic code must differ from the trigger code")
+	}
+	for i, binding := range cfg.Bindings {
+		if cfg.PanicCode == ChordKey(binding.TriggerCode) {
+			return fmt.Errorf("binding %d: panic code must differ from the trigger code", i+1)
+		}
+	}
+	return nil
+}
+
+func (s *Service) SetPanicCode(code uint16) {
+	s.panicCode.Store(uint32(code))
+}
+
+func (s *Service) PanicCode() uint16 {
+	return uint16(s.panicCode.Load())
+}
+
+func (s *Service) Panic() {
+	if s.stopped() {
+		return
+	}
+	s.panics.Add(1)
+	s.resetGesture()
+	s.SetEnabled(false)
+	s.releaseHeldCodes()
+
+	cfg := s.config()
+	s.logger.Warn("Panic key pressed, clicking disabled", "action", cfg.PanicAction)
+	s.publish(StateEvent{Kind: StatePanic})
+	if cfg.OnPanic != nil {
+		cfg.OnPanic(cfg.PanicAction)
+	}
+}
+
+func (s *Service) isPanicEvent(source string, event Event) bool {
+	code := s.PanicCode()
+	if code == 0 || event.Type != EventTypeKey || event.Code != code {
+		return false
+	}
+	if s.isKnownSource(source) {
+		return true
+	}
+	_, ok := s.config().PanicSources[source]
+	return ok
+}
@@ -0,0 +1,107 @@
+package autoclicker_test
+
+import (
+	"testing"
+	"time"
+
+	"clicker/internal/core/autoclicker"
+	"clicker/internal/core/autoclicker/autoclickertest"
+)
+
+func TestPanicReleasesHeldClickAndDisables(t *testing.T) {
+	cfg := autoclickertest.Config(true)
+	cfg.ClickDown = 80 * time.Millisecond
+	cfg.PanicCode = autoclicker.LeftButtonCode + 5
+	var actions []autoclicker.PanicAction
+	cfg.PanicAction = autoclicker.PanicUngrab
+	cfg.OnPanic = func(action autoclicker.PanicAction) {
+		actions = append(actions, action)
+	}
+
+	result := autoclickertest.Run(t, autoclickertest.Scenario{
+		Config: cfg,
+		Steps: []autoclickertest.Step{
+			autoclickertest.Press(0, cfg.TriggerCode),
+			autoclickertest.Press(230*time.Millisecond, cfg.PanicCode),
+			autoclickertest.Release(240*time.Millisecond, cfg.PanicCode),
+		},
+		Until: time.Second,
+	})
+
+	var released bool
+	for _, record := range result.Records {
+		if record.Event.Type != autoclicker.EventTypeKey || record.Event.Code != autoclicker.LeftButtonCode {
+			continue
+		}
+		if record.At == 230*time.Millisecond && record.Event.Value == 0 {
+			released = true
+		}
+		if record.At > 230*time.Millisecond && record.Event.Value == 1 {
+			t.Fatalf("clicked at %v after the panic", record.At)
+		}
+	}
+	if !released {
+		t.Fatalf("held click not released at the panic:\n%s", autoclickertest.Format(result.Records))
+	}
+	if len(actions) != 1 || actions[0] != autoclicker.PanicUngrab {
+		t.Fatalf("OnPanic called with %v, want [ungrab]", actions)
+	}
+}
+
+func TestPanicPreemptsQueuedEvents(t *testing.T) {
+	clock := autoclicker.NewVirtualClock(autoclickertest.Epoch)
+	cfg := autoclickertest.Config(false)
+	cfg.Clock = clock
+	cfg.PanicCode = autoclicker.LeftButtonCode + 5
+	service, err := autoclicker.NewService(cfg, autoclickertest.NewInjector(clock), &autoclickertest.Logger{})
+	if err != nil {
+		t.Fatalf("NewService() error = %v", err)
+	}
+	events, cancel := service.Subscribe(64)
+	defer cancel()
+
+	// The event loop is not running yet, so the toggle press stays queued
+	// while the panic code is handled right away.
+	toggle := autoclicker.Event{Type: autoclicker.EventTypeKey, Code: cfg.ToggleCode, Value: 1}
+	service.SubmitEvent(autoclickertest.Source, toggle)
+	service.SubmitEvent(autoclickertest.Source, autoclicker.Event{Type: autoclicker.EventTypeKey, Code: cfg.PanicCode, Value: 1})
+	if event := <-events; event.Kind != autoclicker.StatePanic {
+		t.Fatalf("first event = %v, want panic", event.Kind)
+	}
+
+	service.Start()
+	defer service.Stop()
+	service.WaitIdle()
+	if service.IsEnabled() {
+		t.Fatalf("toggle queued before the panic enabled clicking")
+	}
+
+	service.SubmitEvent(autoclickertest.Source, toggle)
+	service.WaitIdle()
+	if !service.IsEnabled() {
+		t.Fatalf("toggle pressed after the panic did not enable clicking")
+	}
+}
+
+func TestPanicCodeValidation(t *testing.T) {
+	cfg := autoclickertest.Config(true)
+	cfg.PanicCode = cfg.ToggleCode
+	if _, err := autoclicker.NewService(cfg, autoclickertest.NewInjector(autoclicker.SystemClock()), &autoclickertest.Logger{}); err == nil {
+		t.Fatalf("expected a panic code equal to the toggle code to be rejected")
+	}
+	cfg.PanicCode = autoclicker.LeftButtonCode + 5
+	cfg.Bindings = []autoclicker.Binding{{TriggerCode: cfg.PanicCode, CPS: 5}}
+	if _, err := autoclicker.NewService(cfg, autoclickertest.NewInjector(autoclicker.SystemClock()), &autoclickertest.Logger{}); err == nil {
+		t.Fatalf("expected a panic code equal to a binding trigger to be rejected")
+	}
+
+	for _, action := range []autoclicker.PanicAction{autoclicker.PanicDisable, autoclicker.PanicUngrab, autoclicker.PanicExit} {
+		parsed, err := autoclicker.ParsePanicAction(action.String())
+		if err != nil || parsed != action {
+			t.Fatalf("ParsePanicAction(%q) = %v, %v", action, parsed, err)
+		}
+	}
+	if _, err := autoclicker.ParsePanicAction("reboot"); err == nil {
+		t.Fatalf("expected an unknown panic action to be rejected")
+	}
+}
@@ -0,0 +1,118 @@
+package autoclicker
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+type PatternStep struct {
+	Codes []uint16
+	Hold  time.Duration
+	Gap   time.Duration
+}
+
+type Pattern []PatternStep
+
+func (p Pattern) String() string {
+	if len(p) == 0 {
+		return "off"
+	}
+	steps := make([]string, 0, len(p))
+	for _, step := range p {
+		codes := make([]string, 0, len(step.Codes))
+		for _, code := range step.Codes {
+			codes = append(codes, fmt.Sprintf("%#x", code))
+		}
+		steps = append(steps, fmt.Sprintf("%s hold %v gap %v", strings.Join(codes, "&"), step.Hold, step.Gap))
+	}
+	return strings.Join(steps, ", ")
+}
+
+func (p Pattern) Codes() []uint16 {
+	var codes []uint16
+	for _, step := range p {
+		for _, code := range step.Codes {
+			if !slices.Contains(codes, code) {
+				codes = append(codes, code)
+			}
+		}
+	}
+	return codes
+}
+
+func (p Pattern) clone() Pattern {
+	if len(p) == 0 {
+		return nil
+	}
+	out := make(Pattern, len(p))
+	for i, step := range p {
+		step.Codes = slices.Clone(step.Codes)
+		out[i] = step
+	}
+	return out
+}
+
+func validatePattern(p Pattern) error {
+	for i, step := range p {
+		if len(step.Codes) == 0 {
+			return fmt.Errorf("pattern step %d has no codes", i+1)
+		}
+		for j, code := range step.Codes {
+			if code == 0 {
+				return fmt.Errorf("pattern step %d: code is empty", i+1)
+			}
+			if ChordModifiers(code) != 0 {
+				return fmt.Errorf("pattern step %d: code cannot be a chord", i+1)
+			}
+			if slices.Contains(step.Codes[:j], code) {
+				return fmt.Errorf("pattern step %d: %#x is pressed twice", i+1, code)
+			}
+		}
+		if step.Hold < 0 {
+			return fmt.Errorf("pattern step %d: hold must be >= 0", i+1)
+		}
+		if step.Gap < 0 {
+			return fmt.Errorf("pattern step %d: gap must be >= 0", i+1)
+		}
+	}
+	return nil
+}
+
+func (s *Service) SetPattern(p Pattern) error {
+	if err := validatePattern(p); err != nil {
+		return err
+	}
+	p = p.clone()
+
+	s.stateMu.Lock()
+	defer s.stateMu.Unlock()
+
+	previous := s.primary.currentPattern().Codes()
+	s.primary.pattern.Store(&p)
+	s.releaseCodes(previous...)
+	return nil
+}
+
+func (s *Service) Pattern() Pattern {
+	return s.primary.currentPattern().clone()
+}
+
+type patternRun struct {
+	pressSeq uint64
+	next     int
+}
+
+func (r *patternRun) step(p Pattern, pressSeq uint64) (PatternStep, bool) {
+	if len(p) == 0 {
+		return PatternStep{}, false
+	}
+	if r.pressSeq != pressSeq || r.next >= len(p) {
+		r.pressSeq = pressSeq
+		r.next = 0
+	}
+	step := p[r.next]
+	r.next = (r.next + 1) % len(p)
+	return step, true
+}
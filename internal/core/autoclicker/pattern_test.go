@@ -0,0 +1,108 @@
+package autoclicker_test
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"clicker/internal/core/autoclicker"
+	"clicker/internal/core/autoclicker/autoclickertest"
+)
+
+const rightButtonCode = autoclicker.LeftButtonCode + 1
+
+func TestPatternAlternatesButtons(t *testing.T) {
+	cfg := autoclickertest.Config(true)
+	cfg.Pattern = autoclicker.Pattern{
+		{Codes: []uint16{autoclicker.LeftButtonCode}, Hold: 20 * time.Millisecond, Gap: 30 * time.Millisecond},
+		{Codes: []uint16{rightButtonCode}, Hold: 10 * time.Millisecond, Gap: 40 * time.Millisecond},
+	}
+
+	result := autoclickertest.Run(t, autoclickertest.Scenario{
+		Config: cfg,
+		Steps: []autoclickertest.Step{
+			autoclickertest.Press(0, cfg.TriggerCode),
+			autoclickertest.Release(180*time.Millisecond, cfg.TriggerCode),
+		},
+		Until: time.Second,
+	})
+
+	want := "" +
+		"0s key 0x110 1\n" +
+		"20ms key 0x110 0\n" +
+		"50ms key 0x111 1\n" +
+		"60ms key 0x111 0\n" +
+		"100ms key 0x110 1\n" +
+		"120ms key 0x110 0\n" +
+		"150ms key 0x111 1\n" +
+		"160ms key 0x111 0\n"
+	if got := autoclickertest.Format(result.Records); got != want {
+		t.Fatalf("records:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestPatternPressesTogetherAndRestartsEveryHold(t *testing.T) {
+	cfg := autoclickertest.Config(true)
+	left := []uint16{autoclicker.LeftButtonCode}
+	cfg.Pattern = autoclicker.Pattern{
+		{Codes: left},
+		{Codes: left},
+		{Codes: []uint16{autoclicker.LeftButtonCode, rightButtonCode}},
+	}
+
+	result := autoclickertest.Run(t, autoclickertest.Scenario{
+		Config: cfg,
+		Steps: []autoclickertest.Step{
+			autoclickertest.Press(0, cfg.TriggerCode),
+			autoclickertest.Release(350*time.Millisecond, cfg.TriggerCode),
+			autoclickertest.Press(500*time.Millisecond, cfg.TriggerCode),
+			autoclickertest.Release(650*time.Millisecond, cfg.TriggerCode),
+		},
+		Until: time.Second,
+	})
+
+	wantLeft := []time.Duration{0, 100 * time.Millisecond, 200 * time.Millisecond, 300 * time.Millisecond, 500 * time.Millisecond, 600 * time.Millisecond}
+	if got := clickStarts(result.Records, autoclicker.LeftButtonCode); !reflect.DeepEqual(got, wantLeft) {
+		t.Fatalf("left clicks at %v, want %v", got, wantLeft)
+	}
+	wantRight := []time.Duration{200 * time.Millisecond}
+	if got := clickStarts(result.Records, rightButtonCode); !reflect.DeepEqual(got, wantRight) {
+		t.Fatalf("right clicks at %v, want %v", got, wantRight)
+	}
+	if result.Stats.Clicks != 6 {
+		t.Fatalf("Clicks = %d, want 6", result.Stats.Clicks)
+	}
+}
+
+func TestPatternValidation(t *testing.T) {
+	left := []uint16{autoclicker.LeftButtonCode}
+	for _, pattern := range []autoclicker.Pattern{
+		{{}},
+		{{Codes: []uint16{0}}},
+		{{Codes: []uint16{autoclicker.Chord(autoclicker.LeftButtonCode, autoclicker.ModCtrl)}}},
+		{{Codes: []uint16{autoclicker.LeftButtonCode, autoclicker.LeftButtonCode}}},
+		{{Codes: left, Hold: -time.Millisecond}},
+		{{Codes: left, Gap: -time.Millisecond}},
+	} {
+		cfg := autoclickertest.Config(true)
+		cfg.Pattern = pattern
+		if _, err := autoclicker.NewService(cfg, autoclickertest.NewInjector(autoclicker.SystemClock()), &autoclickertest.Logger{}); err == nil {
+			t.Fatalf("NewService() accepted pattern %v", pattern)
+		}
+	}
+
+	service, err := autoclicker.NewService(autoclickertest.Config(true), autoclickertest.NewInjector(autoclicker.SystemClock()), &autoclickertest.Logger{})
+	if err != nil {
+		t.Fatalf("NewService() error = %v", err)
+	}
+	pattern := autoclicker.Pattern{{Codes: left}, {Codes: []uint16{rightButtonCode}}}
+	if err := service.SetPattern(pattern); err != nil {
+		t.Fatalf("SetPattern() error = %v", err)
+	}
+	if got := service.Pattern(); !reflect.DeepEqual(got, pattern) {
+		t.Fatalf("Pattern() = %v, want %v", got, pattern)
+	}
+	if got, want := pattern.Codes(), []uint16{autoclicker.LeftButtonCode, rightButtonCode}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("Codes() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,154 @@
+package autoclicker
+
+import (
+	"fmt"
+	"time"
+)
+
+const DefaultTypingPause = time.Second
+
+const maxKeyboardCode = 0xff
+
+func normalizePause(cfg Config) (Config, error) {
+	if cfg.PauseFor < 0 {
+		return cfg, fmt.Errorf("pause duration must be >= 0")
+	}
+	if cfg.TypingPause < 0 {
+		return cfg, fmt.Errorf("typing pause must be >= 0")
+	}
+	if cfg.TypingPause == 0 {
+		cfg.TypingPause = DefaultTypingPause
+	}
+	for _, code := range cfg.PauseCodes {
+		if err := validatePauseCode(cfg, code); err != nil {
+			return cfg, err
+		}
+	}
+	return cfg, nil
+}
+
+func validatePauseCode(cfg Config, code uint16) error {
+	if code == 0 {
+		return fmt.Errorf("pause code is empty")
+	}
+	if ChordModifiers(code) != 0 {
+		return fmt.Errorf("pause code cannot be a chord")
+	}
+	if code == ChordKey(cfg.ToggleCode) {
+		return fmt.Errorf("pause code must differ from the toggle code")
+	}
+	if code == ChordKey(cfg.TriggerCode) {
+		return fmt.Errorf("pause code must differ from the trigger code")
+	}
+	if code == cfg.PanicCode {
+		return fmt.Errorf("pause code must differ from the panic code")
+	}
+	for i, binding := range cfg.Bindings {
+		if code == ChordKey(binding.TriggerCode) {
+			return fmt.Errorf("binding %d: pause code must differ from the trigger code", i+1)
+		}
+	}
+	return nil
+}
+
+type pauseState struct {
+	held map[sourceKey]struct{}
+}
+
+func (s *Service) watchPause(source string, event Event) {
+	if event.Type != EventTypeKey {
+		return
+	}
+	key := sourceKey{source: source, code: event.Code}
+	if _, held := s.pause.held[key]; held && event.Value == 0 {
+		delete(s.pause.held, key)
+		s.pausesHeld.Store(int64(len(s.pause.held)))
+		s.wakeBindings()
+		return
+	}
+	if event.Value != 1 {
+		return
+	}
+
+	cfg := s.config()
+	if s.isPauseCode(event.Code) && s.isPauseSource(source) {
+		if s.pause.held == nil {
+			s.pause.held = make(map[sourceKey]struct{})
+		}
+		s.pause.held[key] = struct{}{}
+		s.pausesHeld.Store(int64(len(s.pause.held)))
+		s.extendPause(cfg.PauseFor)
+		return
+	}
+	if cfg.PauseWhileTyping && s.isTypedKey(source, event.Code) {
+		s.extendPause(cfg.TypingPause)
+	}
+}
+
+func (s *Service) extendPause(d time.Duration) {
+	if d <= 0 {
+		return
+	}
+	until := s.clock.Now().Add(d).UnixNano()
+	for {
+		current := s.pauseUntilNanos.Load()
+		if current >= until || s.pauseUntilNanos.CompareAndSwap(current, until) {
+			return
+		}
+	}
+}
+
+func (s *Service) pauseRemaining() (time.Duration, bool) {
+	if s.pausesHeld.Load() > 0 {
+		return 0, true
+	}
+	until := s.pauseUntilNanos.Load()
+	if until == 0 {
+		return 0, false
+	}
+	if wait := time.Unix(0, until).Sub(s.clock.Now()); wait > 0 {
+		return wait, true
+	}
+	return 0, false
+}
+
+func (s *Service) Paused() bool {
+	_, paused := s.pauseRemaining()
+	return paused
+}
+
+func (s *Service) isPauseCode(code uint16) bool {
+	for _, pause := range s.config().PauseCodes {
+		if pause == code {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Service) isPauseSource(source string) bool {
+	if s.isKnownSource(source) {
+		return true
+	}
+	_, ok := s.config().PauseSources[source]
+	return ok
+}
+
+func (s *Service) isTypedKey(source string, code uint16) bool {
+	if code == 0 || code > maxKeyboardCode {
+		return false
+	}
+	if _, ok := s.config().PauseSources[source]; !ok && !s.isToggleSource(source) {
+		return false
+	}
+	if _, ok := ModifierKey(code); ok {
+		return false
+	}
+	return !s.isTriggerOrToggleKey(code)
+}
+
+func (s *Service) wakeBindings() {
+	for _, b := range s.currentBindings() {
+		b.signalWake()
+	}
+}
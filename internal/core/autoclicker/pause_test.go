@@ -0,0 +1,100 @@
+package autoclicker_test
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"clicker/internal/core/autoclicker"
+	"clicker/internal/core/autoclicker/autoclickertest"
+)
+
+const (
+	keyA     = 30
+	keyEnter = 28
+)
+
+func TestPauseCodeSuspendsClicking(t *testing.T) {
+	cfg := autoclickertest.Config(true)
+	cfg.PauseCodes = []uint16{keyEnter}
+	cfg.PauseFor = 300 * time.Millisecond
+
+	result := autoclickertest.Run(t, autoclickertest.Scenario{
+		Config: cfg,
+		Steps: []autoclickertest.Step{
+			autoclickertest.Press(0, cfg.TriggerCode),
+			// A tap pauses until 300ms after the press.
+			autoclickertest.Press(250*time.Millisecond, keyEnter),
+			autoclickertest.Release(300*time.Millisecond, keyEnter),
+			// Held longer, it pauses until it is released.
+			autoclickertest.Press(800*time.Millisecond, keyEnter),
+			autoclickertest.Release(1200*time.Millisecond, keyEnter),
+			autoclickertest.Release(1450*time.Millisecond, cfg.TriggerCode),
+		},
+		Until: 2 * time.Second,
+	})
+
+	want := []time.Duration{
+		0, 100 * time.Millisecond, 200 * time.Millisecond,
+		550 * time.Millisecond, 650 * time.Millisecond, 750 * time.Millisecond,
+		1200 * time.Millisecond, 1300 * time.Millisecond, 1400 * time.Millisecond,
+	}
+	if got := clickStarts(result.Records, autoclicker.LeftButtonCode); !reflect.DeepEqual(got, want) {
+		t.Fatalf("clicks at %v, want %v", got, want)
+	}
+	if result.Stats.Holds != 1 {
+		t.Fatalf("Holds = %d, want 1", result.Stats.Holds)
+	}
+}
+
+func TestPauseWhileTyping(t *testing.T) {
+	cfg := autoclickertest.Config(true)
+	cfg.ToggleSources["keyboard"] = struct{}{}
+	cfg.TriggerSources["mouse"] = struct{}{}
+	cfg.PauseWhileTyping = true
+	cfg.TypingPause = 200 * time.Millisecond
+	typed := func(at time.Duration, source string, code uint16) autoclickertest.Step {
+		return autoclickertest.Step{At: at, Source: source, Event: autoclickertest.Press(0, code).Event}
+	}
+
+	result := autoclickertest.Run(t, autoclickertest.Scenario{
+		Config: cfg,
+		Steps: []autoclickertest.Step{
+			autoclickertest.Press(0, cfg.TriggerCode),
+			typed(150*time.Millisecond, "keyboard", keyA),
+			// Modifiers and keys from trigger-only sources are not typing.
+			typed(500*time.Millisecond, "keyboard", keyLeftShift),
+			typed(700*time.Millisecond, "mouse", keyA),
+			autoclickertest.Release(1000*time.Millisecond, cfg.TriggerCode),
+		},
+		Until: 2 * time.Second,
+	})
+
+	want := []time.Duration{
+		0, 100 * time.Millisecond,
+		350 * time.Millisecond, 450 * time.Millisecond, 550 * time.Millisecond, 650 * time.Millisecond,
+		750 * time.Millisecond, 850 * time.Millisecond, 950 * time.Millisecond,
+	}
+	if got := clickStarts(result.Records, autoclicker.LeftButtonCode); !reflect.DeepEqual(got, want) {
+		t.Fatalf("clicks at %v, want %v", got, want)
+	}
+}
+
+func TestPauseValidation(t *testing.T) {
+	for _, mutate := range []func(*autoclicker.Config){
+		func(cfg *autoclicker.Config) { cfg.PauseCodes = []uint16{0} },
+		func(cfg *autoclicker.Config) { cfg.PauseCodes = []uint16{cfg.TriggerCode} },
+		func(cfg *autoclicker.Config) { cfg.PauseCodes = []uint16{cfg.ToggleCode} },
+		func(cfg *autoclicker.Config) {
+			cfg.PauseCodes = []uint16{autoclicker.Chord(keyEnter, autoclicker.ModCtrl)}
+		},
+		func(cfg *autoclicker.Config) { cfg.PauseFor = -time.Millisecond },
+		func(cfg *autoclicker.Config) { cfg.TypingPause = -time.Millisecond },
+	} {
+		cfg := autoclickertest.Config(true)
+		mutate(&cfg)
+		if _, err := autoclicker.NewService(cfg, autoclickertest.NewInjector(autoclicker.SystemClock()), &autoclickertest.Logger{}); err == nil {
+			t.Fatalf("NewService() accepted an invalid pause config")
+		}
+	}
+}
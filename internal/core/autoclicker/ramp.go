@@ -0,0 +1,94 @@
+package autoclicker
+
+import (
+	"fmt"
+	"time"
+)
+
+const DefaultRampStart = 0.5
+
+type Ramp struct {
+	Up    time.Duration
+	Down  time.Duration
+	Start float64
+}
+
+func (r Ramp) String() string {
+	if r.Up <= 0 && r.Down <= 0 {
+		return "off"
+	}
+	return fmt.Sprintf("up %v down %v from %.0f%%", r.Up, r.Down, r.Start*100)
+}
+
+func normalizeRamp(r Ramp) (Ramp, error) {
+	if r.Up < 0 {
+		return r, fmt.Errorf("ramp up must be >= 0")
+	}
+	if r.Down < 0 {
+		return r, fmt.Errorf("ramp down must be >= 0")
+	}
+	if r.Start < 0 || r.Start > 1 {
+		return r, fmt.Errorf("ramp start must be within (0, 1]")
+	}
+	if r.Start == 0 {
+		r.Start = DefaultRampStart
+	}
+	return r, nil
+}
+
+func (s *Service) SetRamp(r Ramp) error {
+	r, err := normalizeRamp(r)
+	if err != nil {
+		return err
+	}
+	s.ramp.Store(&r)
+	return nil
+}
+
+func (s *Service) Ramp() Ramp {
+	return *s.ramp.Load()
+}
+
+type rampState struct {
+	startedAt  time.Time
+	releasedAt time.Time
+}
+
+func (st *rampState) reset() {
+	st.startedAt = time.Time{}
+	st.releasedAt = time.Time{}
+}
+
+func (st *rampState) held() {
+	st.releasedAt = time.Time{}
+}
+
+func (st *rampState) easing(r Ramp, now time.Time) bool {
+	if r.Down <= 0 || st.startedAt.IsZero() {
+		return false
+	}
+	if st.releasedAt.IsZero() {
+		st.releasedAt = now
+	}
+	return now.Sub(st.releasedAt) < r.Down
+}
+
+func (st *rampState) scale(r Ramp, interval time.Duration, now time.Time) time.Duration {
+	if st.startedAt.IsZero() {
+		st.startedAt = now
+	}
+	factor := 1.0
+	if r.Up > 0 {
+		if elapsed := now.Sub(st.startedAt); elapsed < r.Up {
+			// Quadratic ease-out from the start rate to the full rate.
+			x := 1 - float64(elapsed)/float64(r.Up)
+			factor = r.Start + (1-r.Start)*(1-x*x)
+		}
+	}
+	if r.Down > 0 && !st.releasedAt.IsZero() {
+		// Quadratic ease-in from the current rate down to the start rate.
+		y := min(float64(now.Sub(st.releasedAt))/float64(r.Down), 1)
+		factor = min(factor, 1-(1-r.Start)*y*y)
+	}
+	return time.Duration(float64(interval) / factor)
+}
@@ -0,0 +1,108 @@
+package autoclicker_test
+
+import (
+	"testing"
+	"time"
+
+	"clicker/internal/core/autoclicker"
+	"clicker/internal/core/autoclicker/autoclickertest"
+)
+
+func clickStarts(records []autoclickertest.Record, code uint16) []time.Duration {
+	return keyTimes(records, code, 1)
+}
+
+func TestRampEasesRateInAndOut(t *testing.T) {
+	cfg := autoclickertest.Config(true)
+	cfg.Ramp = autoclicker.Ramp{Up: 400 * time.Millisecond, Down: 300 * time.Millisecond, Start: 0.5}
+
+	result := autoclickertest.Run(t, autoclickertest.Scenario{
+		Config: cfg,
+		Steps: []autoclickertest.Step{
+			autoclickertest.Press(0, cfg.TriggerCode),
+			autoclickertest.Release(650*time.Millisecond, cfg.TriggerCode),
+		},
+		Until: 2 * time.Second,
+	})
+
+	starts := clickStarts(result.Records, autoclicker.LeftButtonCode)
+	if len(starts) != 9 {
+		t.Fatalf("got %d clicks at %v, want 9", len(starts), starts)
+	}
+	intervals := make([]time.Duration, len(starts)-1)
+	for i := range intervals {
+		intervals[i] = starts[i+1] - starts[i]
+	}
+
+	// The first click runs at half the rate and the ramp levels off into
+	// the full 10 CPS within 400ms.
+	if intervals[0] != 200*time.Millisecond {
+		t.Fatalf("first interval = %v, want 200ms", intervals[0])
+	}
+	if !(intervals[0] > intervals[1] && intervals[1] > intervals[2] && intervals[2] > 100*time.Millisecond) {
+		t.Fatalf("ramp up intervals %v do not shrink towards 100ms", intervals[:3])
+	}
+	for i := 3; i < 7; i++ {
+		if intervals[i] != 100*time.Millisecond {
+			t.Fatalf("interval %d = %v, want 100ms at full rate", i, intervals[i])
+		}
+	}
+	// The release is seen at the click after it and clicking slows down
+	// for another 300ms.
+	if intervals[7] <= 100*time.Millisecond {
+		t.Fatalf("ramp down interval = %v, want longer than 100ms", intervals[7])
+	}
+	released := starts[7]
+	if last := starts[len(starts)-1]; last-released >= 300*time.Millisecond {
+		t.Fatalf("last click %v after the release was seen, want within 300ms", last-released)
+	}
+}
+
+func TestRampOffKeepsFullRate(t *testing.T) {
+	cfg := autoclickertest.Config(true)
+
+	result := autoclickertest.Run(t, autoclickertest.Scenario{
+		Config: cfg,
+		Steps: []autoclickertest.Step{
+			autoclickertest.Press(0, cfg.TriggerCode),
+			autoclickertest.Release(250*time.Millisecond, cfg.TriggerCode),
+		},
+		Until: time.Second,
+	})
+
+	want := []time.Duration{0, 100 * time.Millisecond, 200 * time.Millisecond}
+	starts := clickStarts(result.Records, autoclicker.LeftButtonCode)
+	if len(starts) != len(want) {
+		t.Fatalf("clicks at %v, want %v", starts, want)
+	}
+	for i := range want {
+		if starts[i] != want[i] {
+			t.Fatalf("clicks at %v, want %v", starts, want)
+		}
+	}
+}
+
+func TestSetRampValidates(t *testing.T) {
+	cfg := autoclickertest.Config(true)
+	service, err := autoclicker.NewService(cfg, &autoclickertest.Injector{}, &autoclickertest.Logger{})
+	if err != nil {
+		t.Fatalf("NewService() error = %v", err)
+	}
+	defer service.Stop()
+
+	for _, ramp := range []autoclicker.Ramp{
+		{Up: -time.Millisecond},
+		{Down: -time.Millisecond},
+		{Start: 1.5},
+	} {
+		if err := service.SetRamp(ramp); err == nil {
+			t.Fatalf("SetRamp(%+v) succeeded, want an error", ramp)
+		}
+	}
+	if err := service.SetRamp(autoclicker.Ramp{Up: time.Second}); err != nil {
+		t.Fatalf("SetRamp() error = %v", err)
+	}
+	if got := service.Ramp(); got.Up != time.Second || got.Start != autoclicker.DefaultRampStart {
+		t.Fatalf("Ramp() = %+v, want 1s up from the default start", got)
+	}
+}
@@ -0,0 +1,267 @@
+package autoclicker
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const DefaultRhythmMaxGap = time.Second
+
+type RhythmSample struct {
+	Interval time.Duration
+	Hold     time.Duration
+}
+
+type Rhythm struct {
+	Samples []RhythmSample
+}
+
+func (r Rhythm) validate() error {
+	if len(r.Samples) == 0 {
+		return fmt.Errorf("rhythm has no samples")
+	}
+	for i, sample := range r.Samples {
+		if sample.Interval <= 0 {
+			return fmt.Errorf("rhythm sample %d: interval must be > 0", i+1)
+		}
+		if sample.Hold < 0 {
+			return fmt.Errorf("rhythm sample %d: hold must be >= 0", i+1)
+		}
+	}
+	return nil
+}
+
+type RhythmRecorder struct {
+	MaxGap time.Duration
+
+	samples   []RhythmSample
+	pressedAt time.Time
+	hold      time.Duration
+	pressed   bool
+	released  bool
+}
+
+func (r *RhythmRecorder) Press(at time.Time) {
+	if r.pressed {
+		return
+	}
+	maxGap := r.MaxGap
+	if maxGap <= 0 {
+		maxGap = DefaultRhythmMaxGap
+	}
+	if r.released {
+		if interval := at.Sub(r.pressedAt); interval > 0 && interval <= maxGap {
+			r.samples = append(r.samples, RhythmSample{Interval: interval, Hold: r.hold})
+		}
+	}
+	r.pressedAt = at
+	r.pressed = true
+	r.released = false
+}
+
+func (r *RhythmRecorder) Release(at time.Time) {
+	if !r.pressed {
+		return
+	}
+	r.hold = max(at.Sub(r.pressedAt), 0)
+	r.pressed = false
+	r.released = true
+}
+
+func (r *RhythmRecorder) Len() int {
+	return len(r.samples)
+}
+
+func (r *RhythmRecorder) Rhythm() Rhythm {
+	samples := make([]RhythmSample, len(r.samples))
+	copy(samples, r.samples)
+	return Rhythm{Samples: samples}
+}
+
+type RhythmPlayback uint8
+
+const (
+	RhythmReplay RhythmPlayback = iota
+	RhythmResample
+)
+
+var rhythmPlaybackNames = map[RhythmPlayback]string{
+	RhythmReplay:   "replay",
+	RhythmResample: "resample",
+}
+
+func (p RhythmPlayback) String() string {
+	if name, ok := rhythmPlaybackNames[p]; ok {
+		return name
+	}
+	return fmt.Sprintf("RhythmPlayback(%d)", uint8(p))
+}
+
+func ParseRhythmPlayback(raw string) (RhythmPlayback, error) {
+	value := strings.ToLower(strings.TrimSpace(raw))
+	for playback, name := range rhythmPlaybackNames {
+		if value == name {
+			return playback, nil
+		}
+	}
+	return RhythmReplay, fmt.Errorf("unknown rhythm playback %q (expected replay or resample)", raw)
+}
+
+type rhythmPlayer struct {
+	samples  []RhythmSample
+	playback RhythmPlayback
+	mean     time.Duration
+
+	mu      sync.Mutex
+	next    int
+	current int
+}
+
+func RhythmModels(rhythm Rhythm, playback RhythmPlayback) (TimingModel, ClickDownModel, error) {
+	if err := rhythm.validate(); err != nil {
+		return nil, nil, err
+	}
+	if _, ok := rhythmPlaybackNames[playback]; !ok {
+		return nil, nil, fmt.Errorf("invalid rhythm playback %d", playback)
+	}
+	var total time.Duration
+	for _, sample := range rhythm.Samples {
+		total += sample.Interval
+	}
+	player := &rhythmPlayer{
+		samples:  append([]RhythmSample(nil), rhythm.Samples...),
+		playback: playback,
+		mean:     total / time.Duration(len(rhythm.Samples)),
+	}
+	return player, rhythmHolds{player: player}, nil
+}
+
+func (p *rhythmPlayer) NextInterval(rng *rand.Rand) time.Duration {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.playback == RhythmResample {
+		p.current = rng.Intn(len(p.samples))
+	} else {
+		p.current = p.next
+		p.next = (p.next + 1) % len(p.samples)
+	}
+	return p.samples[p.current].Interval
+}
+
+func (p *rhythmPlayer) Mean() time.Duration {
+	return p.mean
+}
+
+func (p *rhythmPlayer) String() string {
+	return fmt.Sprintf("rhythm %s of %d clicks at %.2f cps", p.playback, len(p.samples), intervalCPS(p.mean))
+}
+
+type rhythmHolds struct {
+	player *rhythmPlayer
+}
+
+func (h rhythmHolds) NextDown(*rand.Rand) time.Duration {
+	h.player.mu.Lock()
+	defer h.player.mu.Unlock()
+	return h.player.samples[h.player.current].Hold
+}
+
+func (h rhythmHolds) String() string {
+	return fmt.Sprintf("rhythm %s", h.player.playback)
+}
+
+type rhythmFile struct {
+	Samples []rhythmFileSample `json:"samples"`
+}
+
+type rhythmFileSample struct {
+	IntervalMS float64 `json:"interval_ms"`
+	HoldMS     float64 `json:"hold_ms"`
+}
+
+func (r Rhythm) WriteJSON(w io.Writer) error {
+	file := rhythmFile{Samples: make([]rhythmFileSample, 0, len(r.Samples))}
+	for _, sample := range r.Samples {
+		file.Samples = append(file.Samples, rhythmFileSample{
+			IntervalMS: durationMS(sample.Interval),
+			HoldMS:     durationMS(sample.Hold),
+		})
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(file)
+}
+
+func ReadRhythmJSON(r io.Reader) (Rhythm, error) {
+	var file rhythmFile
+	if err := json.NewDecoder(r).Decode(&file); err != nil {
+		return Rhythm{}, fmt.Errorf("invalid rhythm json: %w", err)
+	}
+	rhythm := Rhythm{Samples: make([]RhythmSample, 0, len(file.Samples))}
+	for _, sample := range file.Samples {
+		rhythm.Samples = append(rhythm.Samples, RhythmSample{
+			Interval: msDuration(sample.IntervalMS),
+			Hold:     msDuration(sample.HoldMS),
+		})
+	}
+	return rhythm, rhythm.validate()
+}
+
+func (r Rhythm) WriteCSV(w io.Writer) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write([]string{"interval_ms", "hold_ms"}); err != nil {
+		return err
+	}
+	for _, sample := range r.Samples {
+		record := []string{
+			strconv.FormatFloat(durationMS(sample.Interval), 'f', -1, 64),
+			strconv.FormatFloat(durationMS(sample.Hold), 'f', -1, 64),
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+func ReadRhythmCSV(r io.Reader) (Rhythm, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = 2
+	reader.TrimLeadingSpace = true
+	records, err := reader.ReadAll()
+	if err != nil {
+		return Rhythm{}, fmt.Errorf("invalid rhythm csv: %w", err)
+	}
+	if len(records) > 0 && records[0][0] == "interval_ms" {
+		records = records[1:]
+	}
+	rhythm := Rhythm{Samples: make([]RhythmSample, 0, len(records))}
+	for i, record := range records {
+		interval, err := strconv.ParseFloat(record[0], 64)
+		if err != nil {
+			return Rhythm{}, fmt.Errorf("rhythm csv row %d: invalid interval %q", i+1, record[0])
+		}
+		hold, err := strconv.ParseFloat(record[1], 64)
+		if err != nil {
+			return Rhythm{}, fmt.Errorf("rhythm csv row %d: invalid hold %q", i+1, record[1])
+		}
+		rhythm.Samples = append(rhythm.Samples, RhythmSample{Interval: msDuration(interval), Hold: msDuration(hold)})
+	}
+	return rhythm, rhythm.validate()
+}
+
+func durationMS(d time.Duration) float64 {
+	return float64(d.Microseconds()) / 1000
+}
+
+func msDuration(ms float64) time.Duration {
+	return time.Duration(ms * float64(time.Millisecond))
+}
@@ -0,0 +1,169 @@
+package autoclicker_test
+
+import (
+	"bytes"
+	"math/rand"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"clicker/internal/core/autoclicker"
+	"clicker/internal/core/autoclicker/autoclickertest"
+)
+
+func TestRhythmRecorderPairsIntervalsWithHolds(t *testing.T) {
+	var recorder autoclicker.RhythmRecorder
+	at := func(ms int) time.Time {
+		return autoclickertest.Epoch.Add(time.Duration(ms) * time.Millisecond)
+	}
+	recorder.Press(at(0))
+	recorder.Press(at(5)) // autorepeat
+	recorder.Release(at(30))
+	recorder.Press(at(120))
+	recorder.Release(at(140))
+	recorder.Press(at(250))
+	recorder.Release(at(290))
+	// A long pause starts a new run instead of adding a 3s interval.
+	recorder.Press(at(3290))
+	recorder.Release(at(3300))
+	recorder.Press(at(3400))
+
+	want := []autoclicker.RhythmSample{
+		{Interval: 120 * time.Millisecond, Hold: 30 * time.Millisecond},
+		{Interval: 130 * time.Millisecond, Hold: 20 * time.Millisecond},
+		{Interval: 110 * time.Millisecond, Hold: 10 * time.Millisecond},
+	}
+	if got := recorder.Rhythm().Samples; !reflect.DeepEqual(got, want) {
+		t.Fatalf("Rhythm() = %v, want %v", got, want)
+	}
+}
+
+func TestRhythmModelsReplayInOrder(t *testing.T) {
+	rhythm := autoclicker.Rhythm{Samples: []autoclicker.RhythmSample{
+		{Interval: 100 * time.Millisecond, Hold: 10 * time.Millisecond},
+		{Interval: 200 * time.Millisecond, Hold: 20 * time.Millisecond},
+	}}
+	timing, down, err := autoclicker.RhythmModels(rhythm, autoclicker.RhythmReplay)
+	if err != nil {
+		t.Fatalf("RhythmModels() error = %v", err)
+	}
+	if timing.Mean() != 150*time.Millisecond {
+		t.Fatalf("Mean() = %v, want 150ms", timing.Mean())
+	}
+	for i := range 5 {
+		want := rhythm.Samples[i%2]
+		if got := timing.NextInterval(nil); got != want.Interval {
+			t.Fatalf("click %d interval = %v, want %v", i, got, want.Interval)
+		}
+		if got := down.NextDown(nil); got != want.Hold {
+			t.Fatalf("click %d hold = %v, want %v", i, got, want.Hold)
+		}
+	}
+}
+
+func TestRhythmModelsResampleKeepsPairs(t *testing.T) {
+	rhythm := autoclicker.Rhythm{Samples: []autoclicker.RhythmSample{
+		{Interval: 100 * time.Millisecond, Hold: 10 * time.Millisecond},
+		{Interval: 200 * time.Millisecond, Hold: 20 * time.Millisecond},
+		{Interval: 300 * time.Millisecond, Hold: 30 * time.Millisecond},
+	}}
+	timing, down, err := autoclicker.RhythmModels(rhythm, autoclicker.RhythmResample)
+	if err != nil {
+		t.Fatalf("RhythmModels() error = %v", err)
+	}
+	rng := rand.New(rand.NewSource(1))
+	seen := make(map[time.Duration]bool)
+	for range 100 {
+		interval := timing.NextInterval(rng)
+		if hold := down.NextDown(rng); hold != interval/10 {
+			t.Fatalf("hold %v does not belong to interval %v", hold, interval)
+		}
+		seen[interval] = true
+	}
+	if len(seen) != 3 {
+		t.Fatalf("resampling drew %d distinct samples, want 3", len(seen))
+	}
+
+	if _, _, err := autoclicker.RhythmModels(autoclicker.Rhythm{}, autoclicker.RhythmReplay); err == nil {
+		t.Fatalf("expected an empty rhythm to be rejected")
+	}
+}
+
+func TestServiceReplaysRecordedRhythm(t *testing.T) {
+	rhythm := autoclicker.Rhythm{Samples: []autoclicker.RhythmSample{
+		{Interval: 80 * time.Millisecond, Hold: 15 * time.Millisecond},
+		{Interval: 140 * time.Millisecond, Hold: 40 * time.Millisecond},
+		{Interval: 110 * time.Millisecond, Hold: 25 * time.Millisecond},
+	}}
+	timing, down, err := autoclicker.RhythmModels(rhythm, autoclicker.RhythmReplay)
+	if err != nil {
+		t.Fatalf("RhythmModels() error = %v", err)
+	}
+	cfg := autoclickertest.Config(true)
+	cfg.TriggerCode = autoclicker.LeftButtonCode + 3
+	cfg.ToggleCode = autoclicker.LeftButtonCode + 5
+	cfg.Timing = timing
+	cfg.ClickDownModel = down
+
+	service, clock, injector := startVirtualService(t, cfg)
+	service.SubmitEvent("device", autoclicker.Event{Type: autoclicker.EventTypeKey, Code: cfg.TriggerCode, Value: 1})
+	stepClock(clock, 8)
+	clock.BlockUntil(1)
+
+	downs := keyTimes(injector.Records(), autoclicker.LeftButtonCode, 1)
+	ups := keyTimes(injector.Records(), autoclicker.LeftButtonCode, 0)
+	if len(downs) < 4 || len(ups) < 4 {
+		t.Fatalf("got %d downs and %d ups, want at least 4 clicks", len(downs), len(ups))
+	}
+	for i := range 4 {
+		want := rhythm.Samples[i%len(rhythm.Samples)]
+		if hold := ups[i] - downs[i]; hold != want.Hold {
+			t.Fatalf("click %d held %v, want %v", i, hold, want.Hold)
+		}
+		if i > 0 {
+			prev := rhythm.Samples[(i-1)%len(rhythm.Samples)]
+			if interval := downs[i] - downs[i-1]; interval != prev.Interval {
+				t.Fatalf("interval %d = %v, want %v", i, interval, prev.Interval)
+			}
+		}
+	}
+}
+
+func TestRhythmRoundTripsThroughCSVAndJSON(t *testing.T) {
+	rhythm := autoclicker.Rhythm{Samples: []autoclicker.RhythmSample{
+		{Interval: 123456 * time.Microsecond, Hold: 23500 * time.Microsecond},
+		{Interval: 98 * time.Millisecond, Hold: 0},
+	}}
+
+	var csvOut bytes.Buffer
+	if err := rhythm.WriteCSV(&csvOut); err != nil {
+		t.Fatalf("WriteCSV() error = %v", err)
+	}
+	if want := "interval_ms,hold_ms\n123.456,23.5\n98,0\n"; csvOut.String() != want {
+		t.Fatalf("WriteCSV() = %q, want %q", csvOut.String(), want)
+	}
+	fromCSV, err := autoclicker.ReadRhythmCSV(&csvOut)
+	if err != nil {
+		t.Fatalf("ReadRhythmCSV() error = %v", err)
+	}
+	if !reflect.DeepEqual(fromCSV, rhythm) {
+		t.Fatalf("ReadRhythmCSV() = %v, want %v", fromCSV, rhythm)
+	}
+
+	var jsonOut bytes.Buffer
+	if err := rhythm.WriteJSON(&jsonOut); err != nil {
+		t.Fatalf("WriteJSON() error = %v", err)
+	}
+	fromJSON, err := autoclicker.ReadRhythmJSON(&jsonOut)
+	if err != nil {
+		t.Fatalf("ReadRhythmJSON() error = %v", err)
+	}
+	if !reflect.DeepEqual(fromJSON, rhythm) {
+		t.Fatalf("ReadRhythmJSON() = %v, want %v", fromJSON, rhythm)
+	}
+
+	if _, err := autoclicker.ReadRhythmCSV(strings.NewReader("100,abc\n")); err == nil {
+		t.Fatalf("expected an invalid hold to be rejected")
+	}
+}
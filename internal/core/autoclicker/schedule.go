@@ -0,0 +1,60 @@
+package autoclicker
+
+import "time"
+
+const maxCompressBacklog = 5
+
+type clickSchedule struct {
+	next      time.Time
+	lastStart time.Time
+	active    bool
+}
+
+func (c *clickSchedule) reset() {
+	c.active = false
+}
+
+func (c *clickSchedule) wait(now time.Time) time.Duration {
+	if !c.active {
+		return 0
+	}
+	return c.next.Sub(now)
+}
+
+func (c *clickSchedule) begin(now time.Time) (lateness, spacing time.Duration) {
+	if !c.active {
+		c.active = true
+		c.next = now
+		c.lastStart = now
+		return 0, 0
+	}
+	spacing = now.Sub(c.lastStart)
+	c.lastStart = now
+	return max(now.Sub(c.next), 0), spacing
+}
+
+func (c *clickSchedule) advance(interval time.Duration, now time.Time, policy CatchUpPolicy) int64 {
+	c.next = c.next.Add(interval)
+	behind := now.Sub(c.next)
+	if behind < interval || interval <= 0 {
+		return 0
+	}
+
+	switch policy {
+	case CatchUpCompress:
+		// Fire the backlog back to back, dropping what is beyond the limit.
+		// Each late deadline is counted once, when it becomes the next one.
+		var dropped time.Duration
+		if limit := maxCompressBacklog * interval; behind > limit {
+			dropped = (behind - limit) / interval
+			c.next = c.next.Add(dropped * interval)
+		}
+		return int64(dropped) + 1
+	default:
+		// Drop every deadline that is a full interval or more in the past and
+		// fire the most recent one now.
+		skipped := behind / interval
+		c.next = c.next.Add(skipped * interval)
+		return int64(skipped)
+	}
+}
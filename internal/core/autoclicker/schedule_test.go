@@ -0,0 +1,90 @@
+package autoclicker
+
+import (
+	"testing"
+	"time"
+)
+
+func TestClickScheduleKeepsAbsoluteDeadlines(t *testing.T) {
+	var schedule clickSchedule
+	start := time.Unix(0, 0)
+	interval := 100 * time.Millisecond
+
+	schedule.begin(start)
+	// The click itself took 30ms; the next deadline must not move.
+	if missed := schedule.advance(interval, start.Add(30*time.Millisecond), CatchUpSkip); missed != 0 {
+		t.Fatalf("advance() missed = %d, want 0", missed)
+	}
+	if wait := schedule.wait(start.Add(30 * time.Millisecond)); wait != 70*time.Millisecond {
+		t.Fatalf("wait() = %v, want 70ms", wait)
+	}
+
+	lateness, spacing := schedule.begin(start.Add(104 * time.Millisecond))
+	if lateness != 4*time.Millisecond || spacing != 104*time.Millisecond {
+		t.Fatalf("begin() = %v, %v; want 4ms, 104ms", lateness, spacing)
+	}
+	schedule.advance(interval, start.Add(110*time.Millisecond), CatchUpSkip)
+	if wait := schedule.wait(start.Add(110 * time.Millisecond)); wait != 90*time.Millisecond {
+		t.Fatalf("lateness of one click must not delay the next deadline, wait() = %v", wait)
+	}
+}
+
+func TestClickScheduleSkipDropsMissedDeadlines(t *testing.T) {
+	var schedule clickSchedule
+	start := time.Unix(0, 0)
+	interval := 100 * time.Millisecond
+
+	schedule.begin(start)
+	// A 350ms stall puts deadlines 100ms, 200ms and 300ms in the past.
+	if missed := schedule.advance(interval, start.Add(350*time.Millisecond), CatchUpSkip); missed != 2 {
+		t.Fatalf("advance() missed = %d, want 2", missed)
+	}
+	if wait := schedule.wait(start.Add(350 * time.Millisecond)); wait != -50*time.Millisecond {
+		t.Fatalf("expected the 300ms deadline to fire now, wait() = %v", wait)
+	}
+	schedule.begin(start.Add(350 * time.Millisecond))
+	if missed := schedule.advance(interval, start.Add(351*time.Millisecond), CatchUpSkip); missed != 0 {
+		t.Fatalf("advance() missed = %d after catching up, want 0", missed)
+	}
+	if wait := schedule.wait(start.Add(351 * time.Millisecond)); wait != 49*time.Millisecond {
+		t.Fatalf("wait() = %v, want 49ms", wait)
+	}
+}
+
+func TestClickScheduleCompressFiresBacklog(t *testing.T) {
+	var schedule clickSchedule
+	start := time.Unix(0, 0)
+	interval := 100 * time.Millisecond
+
+	schedule.begin(start)
+	now := start.Add(350 * time.Millisecond)
+	var missed int64
+	missed += schedule.advance(interval, now, CatchUpCompress)
+
+	var backToBack int
+	for schedule.wait(now) <= 0 {
+		schedule.begin(now)
+		now = now.Add(time.Millisecond)
+		missed += schedule.advance(interval, now, CatchUpCompress)
+		backToBack++
+	}
+	if backToBack != 3 {
+		t.Fatalf("expected 3 back-to-back catch-up clicks, got %d", backToBack)
+	}
+	if missed != 2 {
+		t.Fatalf("expected 2 deadlines fired a full interval late, got %d", missed)
+	}
+}
+
+func TestClickScheduleCompressBoundsBacklog(t *testing.T) {
+	var schedule clickSchedule
+	start := time.Unix(0, 0)
+	interval := 10 * time.Millisecond
+
+	schedule.begin(start)
+	now := start.Add(time.Second)
+	schedule.advance(interval, now, CatchUpCompress)
+	if behind := -schedule.wait(now); behind > maxCompressBacklog*interval {
+		t.Fatalf("backlog %v exceeds %d intervals", behind, maxCompressBacklog)
+	}
+}
@@ -3,50 +3,78 @@ package autoclicker
 import (
 	"fmt"
 	"math/rand"
+	"sort"
 	"sync"
 	"sync/atomic"
 	"time"
 )
 
 type sourcedEvent struct {
-	source string
-	event  Event
+	source      string
+	event       Event
+	panics      uint64
+	debounceSeq uint64
 }
 
 type Service struct {
-	cfg      Config
+	cfg      atomic.Pointer[Config]
 	injector Injector
 	logger   Logger
+	clock    Clock
+	activity *activity
 
 	injectorMu sync.Mutex
 	stateMu    sync.Mutex
 
-	intervalNanos  atomic.Int64
-	jitterPixels   atomic.Int64
-	triggerCode    atomic.Uint32
 	toggleCode     atomic.Uint32
-	clickCount     atomic.Int64
-	enabled        atomic.Bool
-	holding        atomic.Bool
-	leftButtonDown atomic.Bool
+	triggerMode    atomic.Uint32
+	latchThreshold time.Duration
 
-	pressedSources map[string]struct{}
-	eventsCh       chan sourcedEvent
-	wakeCh         chan struct{}
-	stopCh         chan struct{}
-	stopOnce       sync.Once
-	workersWG      sync.WaitGroup
+	burstCount           atomic.Int64
+	burstCooldownNanos   atomic.Int64
+	maxCPSWindow         atomic.Int64
+	deferredClicks       atomic.Int64
+	ramp                 atomic.Pointer[Ramp]
+	ticks                atomic.Pointer[Ticks]
+	maxHoldNanos         atomic.Int64
+	holdDelayNanos       atomic.Int64
+	debouncePressNanos   atomic.Int64
+	debounceReleaseNanos atomic.Int64
+	bounces              atomic.Int64
+	pausesHeld           atomic.Int64
+	pauseUntilNanos      atomic.Int64
+	panicCode            atomic.Uint32
+	panics               atomic.Uint64
+	clickCount           atomic.Int64
+	stats                clickStats
+	injectorErrors       atomic.Int64
+	enabled              atomic.Bool
+
+	// primary is always bindings[0]; bindings is replaced under stateMu.
+	primary  *bindingState
+	bindings atomic.Pointer[[]*bindingState]
+	started  bool
+
+	// heldCodes is guarded by injectorMu.
+	heldCodes map[uint16]struct{}
+	modifiers ModifierState
+	debounce  debouncer
+	cpsWindow cpsWindow
+	pause     pauseState
+	gesture   gestureState
+	eventsCh  chan sourcedEvent
+	stopCh    chan struct{}
+	stopOnce  sync.Once
+	workersWG sync.WaitGroup
+
+	subsMu sync.Mutex
+	subs   map[*subscriber]struct{}
 }
 
 func NewService(cfg Config, injector Injector, logger Logger) (*Service, error) {
-	if cfg.CPS <= 0 {
-		return nil, fmt.Errorf("cps must be > 0")
-	}
-	if cfg.JitterPixels < 0 {
-		return nil, fmt.Errorf("jitter must be >= 0")
-	}
-	if len(cfg.TriggerSources) == 0 {
-		return nil, fmt.Errorf("no trigger-capable source devices configured")
+	cfg, err := normalizeConfig(cfg)
+	if err != nil {
+		return nil, err
 	}
 	if injector == nil {
 		return nil, fmt.Errorf("injector is nil")
@@ -55,45 +83,257 @@ func NewService(cfg Config, injector Injector, logger Logger) (*Service, error)
 		return nil, fmt.Errorf("logger is nil")
 	}
 
+	activity := newActivity()
+	bindings := make([]*bindingState, 0, len(cfg.Bindings)+1)
+	bindings = append(bindings, newBindingState(activity, primaryBinding(cfg)))
+	for _, binding := range cfg.Bindings {
+		bindings = append(bindings, newBindingState(activity, normalizeBinding(binding)))
+	}
+
 	service := &Service{
-		cfg:            cfg,
 		injector:       injector,
 		logger:         logger,
-		pressedSources: make(map[string]struct{}),
+		clock:          cfg.Clock,
+		activity:       activity,
+		latchThreshold: cfg.LatchThreshold,
+		primary:        bindings[0],
+		heldCodes:      make(map[uint16]struct{}),
+		subs:           make(map[*subscriber]struct{}),
 		eventsCh:       make(chan sourcedEvent, 256),
-		wakeCh:         make(chan struct{}, 1),
 		stopCh:         make(chan struct{}),
 	}
-	service.intervalNanos.Store(time.Duration(float64(time.Second) / cfg.CPS).Nanoseconds())
-	service.jitterPixels.Store(int64(cfg.JitterPixels))
-	service.triggerCode.Store(uint32(cfg.TriggerCode))
+	service.cfg.Store(&cfg)
+	service.bindings.Store(&bindings)
 	service.toggleCode.Store(uint32(cfg.ToggleCode))
+	service.triggerMode.Store(uint32(cfg.TriggerMode))
+	service.burstCount.Store(int64(cfg.BurstCount))
+	service.burstCooldownNanos.Store(cfg.BurstCooldown.Nanoseconds())
+	service.maxCPSWindow.Store(int64(cfg.MaxCPSWindow))
+	service.ramp.Store(&cfg.Ramp)
+	service.ticks.Store(&cfg.Ticks)
+	service.maxHoldNanos.Store(cfg.MaxHold.Nanoseconds())
+	service.holdDelayNanos.Store(cfg.HoldDelay.Nanoseconds())
+	service.debouncePressNanos.Store(cfg.DebouncePress.Nanoseconds())
+	service.debounceReleaseNanos.Store(cfg.DebounceRelease.Nanoseconds())
+	service.panicCode.Store(uint32(cfg.PanicCode))
 	service.enabled.Store(cfg.StartEnabled)
 	return service, nil
 }
 
+func normalizeConfig(cfg Config) (Config, error) {
+	if cfg.Timing == nil && cfg.CPS <= 0 {
+		return cfg, fmt.Errorf("cps must be > 0")
+	}
+	if cfg.JitterPixels < 0 {
+		return cfg, fmt.Errorf("jitter must be >= 0")
+	}
+	if len(cfg.TriggerSources) == 0 {
+		return cfg, fmt.Errorf("no trigger-capable source devices configured")
+	}
+	if cfg.OutputCode == 0 {
+		cfg.OutputCode = LeftButtonCode
+	}
+	if ChordModifiers(cfg.OutputCode) != 0 {
+		return cfg, fmt.Errorf("output code cannot be a chord")
+	}
+	if err := validatePattern(cfg.Pattern); err != nil {
+		return cfg, err
+	}
+	if !cfg.TriggerMode.valid() {
+		return cfg, fmt.Errorf("invalid trigger mode %d", cfg.TriggerMode)
+	}
+	if err := validateBurst(cfg.BurstCount, cfg.BurstCooldown); err != nil {
+		return cfg, err
+	}
+	if cfg.MaxCPSWindow < 0 {
+		return cfg, fmt.Errorf("max cps window must be >= 0")
+	}
+	if _, ok := catchUpPolicyNames[cfg.CatchUp]; !ok {
+		return cfg, fmt.Errorf("invalid catch-up policy %d", cfg.CatchUp)
+	}
+	if cfg.LatchThreshold < 0 {
+		return cfg, fmt.Errorf("latch threshold must be >= 0")
+	}
+	if cfg.LatchThreshold == 0 {
+		cfg.LatchThreshold = DefaultLatchThreshold
+	}
+	ramp, err := normalizeRamp(cfg.Ramp)
+	if err != nil {
+		return cfg, err
+	}
+	cfg.Ramp = ramp
+	ticks, err := normalizeTicks(cfg.Ticks)
+	if err != nil {
+		return cfg, err
+	}
+	cfg.Ticks = ticks
+	if cfg.MaxHold < 0 {
+		return cfg, fmt.Errorf("max hold must be >= 0")
+	}
+	if cfg.HoldDelay < 0 {
+		return cfg, fmt.Errorf("hold delay must be >= 0")
+	}
+	if err := validateDebounce(cfg.DebouncePress, cfg.DebounceRelease); err != nil {
+		return cfg, err
+	}
+	cfg, err = normalizeToggleGesture(cfg)
+	if err != nil {
+		return cfg, err
+	}
+	cfg, err = normalizePause(cfg)
+	if err != nil {
+		return cfg, err
+	}
+	if err := validatePanic(cfg); err != nil {
+		return cfg, err
+	}
+	if cfg.Clock == nil {
+		cfg.Clock = SystemClock()
+	}
+	for i, binding := range cfg.Bindings {
+		if err := validateBinding(binding); err != nil {
+			return cfg, fmt.Errorf("binding %d: %w", i+1, err)
+		}
+	}
+	return cfg, nil
+}
+
+func primaryBinding(cfg Config) Binding {
+	return normalizeBinding(Binding{
+		TriggerCode:    cfg.TriggerCode,
+		OutputCode:     cfg.OutputCode,
+		CPS:            cfg.CPS,
+		Timing:         cfg.Timing,
+		Curve:          cfg.Curve,
+		ClickDown:      cfg.ClickDown,
+		ClickDownModel: cfg.ClickDownModel,
+		JitterPixels:   cfg.JitterPixels,
+		Pattern:        cfg.Pattern,
+	})
+}
+
+func (s *Service) UpdateConfig(cfg Config) error {
+	if cfg.Clock == nil {
+		cfg.Clock = s.clock
+	}
+	if cfg.Clock != s.clock {
+		return fmt.Errorf("clock cannot be changed")
+	}
+	cfg, err := normalizeConfig(cfg)
+	if err != nil {
+		return err
+	}
+	next := make([]*bindingState, 0, len(cfg.Bindings)+1)
+	next = append(next, s.primary)
+	for _, binding := range cfg.Bindings {
+		next = append(next, newBindingState(s.activity, normalizeBinding(binding)))
+	}
+
+	s.stateMu.Lock()
+	defer s.stateMu.Unlock()
+	defer s.syncHoldStats()
+
+	if s.stopped() {
+		return fmt.Errorf("service stopped")
+	}
+
+	primary := primaryBinding(cfg)
+	s.primary.timing.Store(&timingSlot{model: primary.Timing})
+	s.primary.clickDown.Store(&clickDownSlot{model: primary.ClickDownModel})
+	s.primary.curve.Store(&curveSlot{model: primary.Curve})
+	s.primary.jitterPixels.Store(int64(primary.JitterPixels))
+	s.primary.triggerCode.Store(uint32(primary.TriggerCode))
+	s.primary.outputCode.Store(uint32(primary.OutputCode))
+	pattern := primary.Pattern.clone()
+	s.primary.pattern.Store(&pattern)
+	s.toggleCode.Store(uint32(cfg.ToggleCode))
+	s.triggerMode.Store(uint32(cfg.TriggerMode))
+	s.latchThreshold = cfg.LatchThreshold
+	s.burstCount.Store(int64(cfg.BurstCount))
+	s.burstCooldownNanos.Store(cfg.BurstCooldown.Nanoseconds())
+	s.maxCPSWindow.Store(int64(cfg.MaxCPSWindow))
+	s.ramp.Store(&cfg.Ramp)
+	s.ticks.Store(&cfg.Ticks)
+	s.maxHoldNanos.Store(cfg.MaxHold.Nanoseconds())
+	s.holdDelayNanos.Store(cfg.HoldDelay.Nanoseconds())
+	s.debouncePressNanos.Store(cfg.DebouncePress.Nanoseconds())
+	s.debounceReleaseNanos.Store(cfg.DebounceRelease.Nanoseconds())
+	s.panicCode.Store(uint32(cfg.PanicCode))
+	s.cfg.Store(&cfg)
+	s.replaceBindingsLocked(next)
+
+	for _, b := range next {
+		b.resetTrigger()
+	}
+	s.resetGesture()
+	s.releaseHeldCodes()
+	wasEnabled := s.enabled.Swap(cfg.StartEnabled)
+	for _, b := range next {
+		b.signalWake()
+	}
+
+	s.logger.Info("Config updated")
+	switch {
+	case wasEnabled && !cfg.StartEnabled:
+		s.logger.Info("Autoclicker disabled")
+		s.publish(StateEvent{Kind: StateDisabled})
+	case !wasEnabled && cfg.StartEnabled:
+		s.logger.Info("Autoclicker enabled")
+		s.publish(StateEvent{Kind: StateEnabled})
+	}
+	return nil
+}
+
+func (s *Service) config() *Config {
+	return s.cfg.Load()
+}
+
 func (s *Service) Start() {
+	s.stateMu.Lock()
+	defer s.stateMu.Unlock()
+
+	s.started = true
 	s.workersWG.Add(1)
 	go s.eventLoop()
 
-	s.workersWG.Add(1)
-	go s.clickLoop()
+	for _, b := range s.currentBindings() {
+		s.startClickLoop(b)
+	}
 }
 
 func (s *Service) Stop() {
 	s.stopOnce.Do(func() {
+		s.stateMu.Lock()
 		close(s.stopCh)
+		s.stateMu.Unlock()
+		s.activity.add(0)
 		s.workersWG.Wait()
-		s.releaseLeftButton()
+		s.releaseHeldCodes()
 		_ = s.injector.Close()
+		s.closeSubscribers()
 	})
 }
 
 func (s *Service) SubmitEvent(source string, event Event) bool {
+	if s.isPanicEvent(source, event) {
+		if s.stopped() {
+			return false
+		}
+		if event.Value == 1 {
+			s.Panic()
+		}
+		return true
+	}
+	return s.enqueue(sourcedEvent{source: source, event: event, panics: s.panics.Load()})
+}
+
+func (s *Service) enqueue(item sourcedEvent) bool {
+	s.activity.add(1)
 	select {
 	case <-s.stopCh:
+		s.activity.add(-1)
 		return false
-	case s.eventsCh <- sourcedEvent{source: source, event: event}:
+	case s.eventsCh <- item:
 		return true
 	}
 }
@@ -102,89 +342,238 @@ func (s *Service) SetCPS(cps float64) error {
 	if cps <= 0 {
 		return fmt.Errorf("cps must be > 0")
 	}
-	s.intervalNanos.Store(time.Duration(float64(time.Second) / cps).Nanoseconds())
+	s.primary.timing.Store(&timingSlot{model: fixedTiming{interval: CPSInterval(cps)}})
 	return nil
 }
 
+func (s *Service) SetTimingModel(model TimingModel) error {
+	if model == nil {
+		return fmt.Errorf("timing model is nil")
+	}
+	s.primary.timing.Store(&timingSlot{model: model})
+	return nil
+}
+
+func (s *Service) SetClickDownModel(model ClickDownModel) error {
+	if model == nil {
+		return fmt.Errorf("click down model is nil")
+	}
+	s.primary.clickDown.Store(&clickDownSlot{model: model})
+	return nil
+}
+
+func (s *Service) TimingModel() TimingModel {
+	return s.primary.currentTiming()
+}
+
 func (s *Service) SetJitter(pixels int) error {
 	if pixels < 0 {
 		return fmt.Errorf("jitter must be >= 0")
 	}
-	s.jitterPixels.Store(int64(pixels))
+	s.primary.jitterPixels.Store(int64(pixels))
 	return nil
 }
 
 func (s *Service) SetEnabled(enabled bool) {
 	s.stateMu.Lock()
 	defer s.stateMu.Unlock()
+	defer s.syncHoldStats()
 
+	if !enabled {
+		s.resetGesture()
+	}
 	if s.enabled.Load() == enabled {
 		// Defensive release in case button-up was missed.
 		if enabled {
-			s.releaseLeftButton()
+			s.releaseHeldCodes()
 		}
 		return
 	}
 	s.enabled.Store(enabled)
-	s.holding.Store(false)
-	clear(s.pressedSources)
-	s.releaseLeftButton()
+	for _, b := range s.currentBindings() {
+		b.resetTrigger()
+	}
+	s.releaseHeldCodes()
 	if !enabled {
 		s.logger.Info("Autoclicker disabled")
+		s.publish(StateEvent{Kind: StateDisabled})
 		return
 	}
 	s.logger.Info("Autoclicker enabled")
+	s.publish(StateEvent{Kind: StateEnabled})
+}
+
+func (s *Service) SetGrabEnabled(enabled bool) {
+	s.stateMu.Lock()
+	defer s.stateMu.Unlock()
+
+	cfg := *s.config()
+	cfg.GrabEnabled = enabled
+	s.cfg.Store(&cfg)
 }
 
 func (s *Service) SetToggleCode(code uint16) {
 	s.toggleCode.Store(uint32(code))
+	s.resetGesture()
 }
 
 func (s *Service) SetTriggerCode(code uint16) {
 	s.stateMu.Lock()
 	defer s.stateMu.Unlock()
+	defer s.syncHoldStats()
+
+	s.primary.triggerCode.Store(uint32(code))
+	s.primary.resetTrigger()
+	s.releaseCodes(s.primary.outputCodes()...)
+}
+
+func (s *Service) SetOutputCode(code uint16) {
+	s.stateMu.Lock()
+	defer s.stateMu.Unlock()
+
+	previous := s.primary.currentOutputCode()
+	s.primary.outputCode.Store(uint32(code))
+	s.releaseCodes(previous)
+}
+
+func (s *Service) SetTriggerMode(mode TriggerMode) error {
+	if !mode.valid() {
+		return fmt.Errorf("invalid trigger mode %d", mode)
+	}
+
+	s.stateMu.Lock()
+	defer s.stateMu.Unlock()
+	defer s.syncHoldStats()
+
+	if s.currentTriggerMode() == mode {
+		return nil
+	}
+	s.triggerMode.Store(uint32(mode))
+	for _, b := range s.currentBindings() {
+		b.resetTrigger()
+	}
+	s.releaseHeldCodes()
+	return nil
+}
 
-	s.triggerCode.Store(uint32(code))
-	s.holding.Store(false)
-	clear(s.pressedSources)
-	s.releaseLeftButton()
+func (s *Service) TriggerMode() TriggerMode {
+	return s.currentTriggerMode()
 }
 
 func (s *Service) IsEnabled() bool {
 	return s.enabled.Load()
 }
 
-func (s *Service) clickLoop() {
+// Callers must hold stateMu.
+func (s *Service) startClickLoop(b *bindingState) {
+	s.workersWG.Add(1)
+	s.activity.add(1)
+	go s.clickLoop(b)
+}
+
+func (s *Service) clickLoop(b *bindingState) {
 	defer s.workersWG.Done()
+	defer close(b.doneCh)
+	defer s.activity.add(-1)
 
-	lastProgress := time.Now()
+	var burst burstState
+	var schedule clickSchedule
+	var ramp rampState
+	var curve curveRun
+	var pattern patternRun
+	deferred := false
+	rng := rand.New(rand.NewSource(s.clock.Now().UnixNano()))
 	for {
-		if s.stopped() {
+		if s.bindingStopped(b) {
 			return
 		}
-		if !s.enabled.Load() || !s.holding.Load() {
-			if !s.waitForWake() {
+		holding := b.holding.Load()
+		if holding {
+			ramp.held()
+		}
+		// A released trigger keeps clicking through the ramp down; disabling
+		// and the max hold cutoff stop at once.
+		if !s.enabled.Load() || (!holding && (b.cutOff.Load() || !ramp.easing(s.Ramp(), s.clock.Now()))) {
+			schedule.reset()
+			ramp.reset()
+			curve.reset()
+			deferred = false
+			if !s.waitForWake(b) {
 				return
 			}
 			continue
 		}
-
-		cycleStart := time.Now()
-		if !s.clickOnce() {
-			return
+		// A pause keeps the trigger state; clicking picks up where the
+		// schedule starts over once it ends.
+		if wait, paused := s.pauseRemaining(); paused {
+			schedule.reset()
+			if wait > 0 && !s.waitWithWake(b, wait) {
+				return
+			}
+			if wait <= 0 && !s.waitForWake(b) {
+				return
+			}
+			continue
 		}
-
-		now := time.Now()
-		if now.Sub(lastProgress) >= time.Second {
-			s.logger.Info("Clicks sent", "count", s.clickCount.Load())
-			lastProgress = now
+		if wait, ok := s.nextBurstClick(b, &burst); !ok {
+			schedule.reset()
+			ramp.reset()
+			curve.reset()
+			if wait > 0 && !s.waitWithWake(b, wait) {
+				return
+			}
+			if wait <= 0 && !s.waitForWake(b) {
+				return
+			}
+			continue
 		}
+		// A wake (e.g. a new press) can end the wait early; keep waiting
+		// until the deadline so the rate never exceeds the schedule.
+		if wait := schedule.wait(s.clock.Now()); wait > 0 {
+			if !s.waitWithWake(b, wait) {
+				return
+			}
+			continue
+		}
+		// Over the cap or the tick limit, the click waits for room and its
+		// run starts over so no backlog piles up behind it.
+		if wait := s.reserveClick(s.clock.Now()); wait > 0 {
+			if !deferred {
+				deferred = true
+				s.deferredClicks.Add(1)
+			}
+			schedule.reset()
+			if !s.waitWithWake(b, wait) {
+				return
+			}
+			continue
+		}
+		deferred = false
 
-		interval := s.currentInterval()
-		sleepFor := interval - time.Since(cycleStart)
-		if sleepFor > 0 && !s.waitWithWake(sleepFor) {
+		start := s.clock.Now()
+		lateness, spacing := schedule.begin(start)
+		timing := b.currentTiming()
+		interval := curve.scale(b.currentCurve(), timing, timing.NextInterval(rng), start, rng)
+		interval = ramp.scale(s.Ramp(), interval, start)
+		down := b.currentClickDown().NextDown(rng)
+		codes := []uint16{b.currentOutputCode()}
+		if step, ok := pattern.step(b.currentPattern(), b.pressSeq.Load()); ok {
+			codes = step.Codes
+			if step.Hold > 0 {
+				down = step.Hold
+			}
+			if step.Gap > 0 {
+				interval = down + step.Gap
+			}
+		}
+		if !s.clickOnce(b, rng, codes, interval, down) {
 			return
 		}
+
+		now := s.clock.Now()
+		burst.burstClicked(now)
+		missed := schedule.advance(interval, now, s.config().CatchUp)
+		s.stats.recordClick(start, lateness, spacing, missed)
 	}
 }
 
@@ -196,74 +585,188 @@ func (s *Service) eventLoop() {
 		case <-s.stopCh:
 			return
 		case item := <-s.eventsCh:
-			s.handleEvent(item.source, item.event)
+			if (item.panics == s.panics.Load() || !s.isToggleEvent(item.source, item.event)) && s.debounceEvent(item) {
+				s.handleEvent(item.source, item.event)
+			}
+			s.activity.add(-1)
 		}
 	}
 }
 
 func (s *Service) handleEvent(source string, event Event) {
-	if event.Type == EventTypeKey && event.Code == s.currentToggleCode() && s.isKnownSource(source) {
-		if event.Value == 1 {
-			s.SetEnabled(!s.enabled.Load())
-		}
+	s.modifiers.Update(source, event)
+	s.watchPause(source, event)
+	if s.isToggleEvent(source, event) {
+		s.handleToggleEvent(event.Value)
 		return
 	}
 
-	if event.Type == EventTypeKey && event.Code == s.currentTriggerCode() && s.isTriggerSource(source) {
+	if matched := s.triggeredBindings(source, event); len(matched) > 0 {
 		enabled := s.enabled.Load()
-		if s.cfg.GrabEnabled && s.isGrabSource(source) {
-			if !enabled || s.cfg.PassThroughTrigger {
+		swallowed := s.config().GrabEnabled && s.isGrabSource(source) && !s.config().PassThroughTrigger
+		if s.config().GrabEnabled && s.isGrabSource(source) {
+			if !enabled || s.config().PassThroughTrigger {
 				s.passThroughEvent(event)
 			}
 		}
 		if !enabled {
 			return
 		}
-		s.handleTriggerEvent(source, event.Value)
+		short := false
+		for _, b := range matched {
+			if s.handleTriggerEvent(b, source, event.Value) {
+				short = true
+			}
+		}
+		if short && swallowed {
+			s.replayShortPress(event)
+		}
 		return
 	}
 
-	if s.cfg.GrabEnabled && s.isGrabSource(source) {
+	if s.config().GrabEnabled && s.isGrabSource(source) {
 		s.passThroughEvent(event)
 	}
 }
 
-func (s *Service) handleTriggerEvent(source string, value int32) {
+func (s *Service) isToggleEvent(source string, event Event) bool {
+	if event.Type != EventTypeKey || !s.isKnownSource(source) {
+		return false
+	}
+	toggle := s.currentToggleCode()
+	if s.chordHeld(toggle, event.Code) {
+		return true
+	}
+	return event.Value != 1 && ChordKey(toggle) == event.Code && s.toggleHeld()
+}
+
+func (s *Service) triggeredBindings(source string, event Event) []*bindingState {
+	if event.Type != EventTypeKey || !s.isTriggerSource(source) {
+		return nil
+	}
+	var matched []*bindingState
+	for _, b := range s.currentBindings() {
+		trigger := b.currentTriggerCode()
+		if ChordKey(trigger) != event.Code {
+			continue
+		}
+		if s.chordHeld(trigger, event.Code) || (event.Value != 1 && s.pressedBy(b, source)) {
+			matched = append(matched, b)
+		}
+	}
+	return matched
+}
+
+func (s *Service) chordHeld(chord, code uint16) bool {
+	mods := ChordModifiers(chord)
+	return ChordKey(chord) == code && s.modifiers.Held()&mods == mods
+}
+
+func (s *Service) pressedBy(b *bindingState, source string) bool {
+	s.stateMu.Lock()
+	defer s.stateMu.Unlock()
+	_, ok := b.pressedSources[source]
+	return ok
+}
+
+func (s *Service) handleTriggerEvent(b *bindingState, source string, value int32) bool {
 	s.stateMu.Lock()
 	defer s.stateMu.Unlock()
+	defer s.syncHoldStats()
 
 	switch value {
 	case 1, 2:
 		if !s.enabled.Load() {
-			return
+			return false
 		}
-		wasHolding := len(s.pressedSources) > 0
-		if _, exists := s.pressedSources[source]; !exists {
-			s.logger.Info("Trigger down", "source", source)
+		// Only a fresh press, not key repeat, restarts a cut off hold.
+		if value == 2 && b.cutOff.Load() {
+			return false
+		}
+		wasPressed := len(b.pressedSources) > 0
+		if _, exists := b.pressedSources[source]; !exists {
+			s.logger.Info("Trigger down", "source", source, "trigger", b.currentTriggerCode())
+			s.publish(StateEvent{Kind: StateTriggerDown, Source: source, Code: b.currentTriggerCode()})
+		}
+		b.pressedSources[source] = struct{}{}
+		if !wasPressed {
+			// A press ending a latch acts at once; only presses that start
+			// clicking wait out the hold delay.
+			unlatch := s.currentTriggerMode() != TriggerModeHold && b.latched
+			if delay := s.HoldDelay(); delay > 0 && !unlatch {
+				s.armHoldDelay(b, delay)
+			} else {
+				s.handleTriggerPress(b)
+			}
 		}
-		s.pressedSources[source] = struct{}{}
-		s.holding.Store(true)
-		if !wasHolding {
-			s.signalWake()
+		if !b.delaying {
+			s.maybeNeutralizeTriggerHold(b)
 		}
-		s.maybeNeutralizeLeftHold()
 	case 0:
-		if _, exists := s.pressedSources[source]; exists {
-			s.logger.Info("Trigger up", "source", source)
+		if _, exists := b.pressedSources[source]; exists {
+			s.logger.Info("Trigger up", "source", source, "trigger", b.currentTriggerCode())
+			s.publish(StateEvent{Kind: StateTriggerUp, Source: source, Code: b.currentTriggerCode()})
 		}
-		delete(s.pressedSources, source)
-		if len(s.pressedSources) == 0 {
-			s.holding.Store(false)
+		delete(b.pressedSources, source)
+		if len(b.pressedSources) == 0 {
+			b.stopMaxHold()
+			if b.delaying {
+				b.stopHoldDelay()
+				return true
+			}
+			s.handleTriggerRelease(b)
 		}
 	}
+	return false
+}
+
+// Callers must hold stateMu.
+func (s *Service) handleTriggerPress(b *bindingState) {
+	if s.currentTriggerMode() != TriggerModeHold && b.latched {
+		b.latched = false
+		b.unlatching = true
+		b.holding.Store(false)
+		s.logger.Info("Trigger unlatched", "trigger", b.currentTriggerCode())
+		return
+	}
+
+	b.pressedAt = s.clock.Now()
+	b.pressSeq.Add(1)
+	b.cutOff.Store(false)
+	s.armMaxHold(b)
+	s.stats.recordHold()
+	b.holding.Store(true)
+	b.signalWake()
+	if s.currentTriggerMode() == TriggerModeLatch {
+		b.latched = true
+		s.logger.Info("Trigger latched", "trigger", b.currentTriggerCode())
+	}
+}
+
+// Callers must hold stateMu.
+func (s *Service) handleTriggerRelease(b *bindingState) {
+	if b.unlatching {
+		b.unlatching = false
+		return
+	}
+	if b.latched {
+		return
+	}
+	if s.currentTriggerMode() == TriggerModeHoldOrLatch && b.holding.Load() && s.clock.Now().Sub(b.pressedAt) < s.latchThreshold {
+		b.latched = true
+		s.logger.Info("Trigger latched", "trigger", b.currentTriggerCode())
+		return
+	}
+	b.holding.Store(false)
 }
 
-func (s *Service) maybeNeutralizeLeftHold() {
-	if s.cfg.GrabEnabled || s.currentTriggerCode() != LeftButtonCode {
+func (s *Service) maybeNeutralizeTriggerHold(b *bindingState) {
+	output := b.currentOutputCode()
+	if s.config().GrabEnabled || ChordKey(b.currentTriggerCode()) != output {
 		return
 	}
 	_ = s.writeEvents(
-		Event{Type: EventTypeKey, Code: LeftButtonCode, Value: 0},
+		Event{Type: EventTypeKey, Code: output, Value: 0},
 		Event{Type: EventTypeSyn, Code: SynReportCode, Value: 0},
 	)
 }
@@ -279,76 +782,81 @@ func (s *Service) passThroughEvent(event Event) {
 	}
 }
 
-func (s *Service) clickOnce() bool {
-	jitterX, jitterY := s.randomJitterOffsets()
-	if (jitterX != 0 || jitterY != 0) && !s.emitJitterMove(jitterX, jitterY) {
+func (s *Service) clickOnce(b *bindingState, rng *rand.Rand, codes []uint16, interval, down time.Duration) bool {
+	jitterX, jitterY := randomJitterOffsets(rng, b.currentJitterPixels())
+	if (jitterX != 0 || jitterY != 0) && !s.emitJitterMove(b, jitterX, jitterY) {
 		return false
 	}
 
-	interval := s.currentInterval()
-	err := s.writeEvents(
-		Event{Type: EventTypeKey, Code: LeftButtonCode, Value: 1},
-		Event{Type: EventTypeSyn, Code: SynReportCode, Value: 0},
-	)
+	err := s.writeEvents(keyEvents(codes, 1)...)
 	if err != nil {
-		if s.stopped() {
+		if s.bindingStopped(b) {
 			return false
 		}
 		s.logger.Warn("Failed to emit click down", "err", err)
-		if !s.sleepWithStop(100 * time.Millisecond) {
+		if !s.sleepWithStop(b, 100*time.Millisecond) {
 			return false
 		}
 		return true
 	}
 
-	down := s.cfg.ClickDown
 	if down > interval {
 		down = interval
 	}
-	if down > 0 && !s.sleepWithStop(down) {
+	if down > 0 && !s.sleepWithStop(b, down) {
 		return false
 	}
 
-	err = s.writeEvents(
-		Event{Type: EventTypeKey, Code: LeftButtonCode, Value: 0},
-		Event{Type: EventTypeSyn, Code: SynReportCode, Value: 0},
-	)
+	err = s.writeEvents(keyEvents(codes, 0)...)
 	if err != nil {
-		if s.stopped() {
+		if s.bindingStopped(b) {
 			return false
 		}
 		s.logger.Warn("Failed to emit click up", "err", err)
-		if !s.sleepWithStop(100 * time.Millisecond) {
+		if !s.sleepWithStop(b, 100*time.Millisecond) {
 			return false
 		}
 		return true
 	}
 
-	if (jitterX != 0 || jitterY != 0) && !s.emitJitterMove(-jitterX, -jitterY) {
+	if (jitterX != 0 || jitterY != 0) && !s.emitJitterMove(b, -jitterX, -jitterY) {
 		return false
 	}
 
 	s.clickCount.Add(1)
+	for _, code := range codes {
+		s.publish(StateEvent{Kind: StateClick, Code: code})
+	}
 	return true
 }
 
+func keyEvents(codes []uint16, value int32) []Event {
+	events := make([]Event, 0, len(codes)+1)
+	for _, code := range codes {
+		events = append(events, Event{Type: EventTypeKey, Code: code, Value: value})
+	}
+	return append(events, Event{Type: EventTypeSyn, Code: SynReportCode, Value: 0})
+}
+
 func (s *Service) writeEvents(events ...Event) error {
 	s.injectorMu.Lock()
 	defer s.injectorMu.Unlock()
 	if err := s.injector.WriteEvents(events...); err != nil {
+		s.injectorErrors.Add(1)
+		s.publish(StateEvent{Kind: StateInjectorError, Err: err})
 		return err
 	}
-	s.trackLeftButtonState(events)
+	s.trackHeldCodes(events)
 	return nil
 }
 
 func (s *Service) isTriggerSource(source string) bool {
-	_, ok := s.cfg.TriggerSources[source]
+	_, ok := s.config().TriggerSources[source]
 	return ok
 }
 
 func (s *Service) isToggleSource(source string) bool {
-	_, ok := s.cfg.ToggleSources[source]
+	_, ok := s.config().ToggleSources[source]
 	return ok
 }
 
@@ -360,34 +868,18 @@ func (s *Service) isKnownSource(source string) bool {
 }
 
 func (s *Service) isGrabSource(source string) bool {
-	_, ok := s.cfg.GrabSources[source]
+	_, ok := s.config().GrabSources[source]
 	return ok
 }
 
-func (s *Service) currentInterval() time.Duration {
-	ns := s.intervalNanos.Load()
-	if ns <= 0 {
-		return time.Second
-	}
-	return time.Duration(ns)
-}
-
-func (s *Service) currentJitterPixels() int32 {
-	pixels := s.jitterPixels.Load()
-	if pixels <= 0 {
-		return 0
-	}
-	return int32(pixels)
+func (s *Service) currentTriggerMode() TriggerMode {
+	return TriggerMode(s.triggerMode.Load())
 }
 
 func (s *Service) currentToggleCode() uint16 {
 	return uint16(s.toggleCode.Load())
 }
 
-func (s *Service) currentTriggerCode() uint16 {
-	return uint16(s.triggerCode.Load())
-}
-
 func (s *Service) stopped() bool {
 	select {
 	case <-s.stopCh:
@@ -397,91 +889,141 @@ func (s *Service) stopped() bool {
 	}
 }
 
-func (s *Service) signalWake() {
+func (s *Service) bindingStopped(b *bindingState) bool {
 	select {
-	case s.wakeCh <- struct{}{}:
+	case <-s.stopCh:
+		return true
+	case <-b.stopCh:
+		return true
 	default:
+		return false
 	}
 }
 
-func (s *Service) waitForWake() bool {
-	select {
-	case <-s.stopCh:
-		return false
-	case <-s.wakeCh:
-		return true
-	}
+func (s *Service) waitForWake(b *bindingState) bool {
+	return s.sleep(b, 0, true)
 }
 
-func (s *Service) waitWithWake(duration time.Duration) bool {
+func (s *Service) waitWithWake(b *bindingState, duration time.Duration) bool {
 	if duration <= 0 {
 		return true
 	}
-	timer := time.NewTimer(duration)
-	defer timer.Stop()
-	select {
-	case <-s.stopCh:
-		return false
-	case <-s.wakeCh:
-		return true
-	case <-timer.C:
-		return true
-	}
+	return s.sleep(b, duration, true)
 }
 
-func (s *Service) sleepWithStop(duration time.Duration) bool {
+func (s *Service) sleepWithStop(b *bindingState, duration time.Duration) bool {
 	if duration <= 0 {
 		return true
 	}
-	timer := time.NewTimer(duration)
-	defer timer.Stop()
+	return s.sleep(b, duration, false)
+}
+
+func (s *Service) sleep(b *bindingState, duration time.Duration, wake bool) bool {
+	var fired chan struct{}
+	if duration > 0 {
+		fired = make(chan struct{})
+		timer := s.clock.AfterFunc(duration, func() {
+			s.activity.resume(b)
+			close(fired)
+		})
+		defer timer.Stop()
+	}
+	var wakeCh chan struct{}
+	if wake {
+		wakeCh = b.wakeCh
+	}
+
+	s.activity.park(b, wake)
+	defer s.activity.resume(b)
 	select {
+	case <-fired:
+		return true
+	case <-wakeCh:
+		return true
 	case <-s.stopCh:
 		return false
-	case <-timer.C:
-		return true
+	case <-b.stopCh:
+		return false
 	}
 }
 
-func (s *Service) trackLeftButtonState(events []Event) {
+func (s *Service) trackHeldCodes(events []Event) {
 	for _, event := range events {
-		if event.Type != EventTypeKey || event.Code != LeftButtonCode {
+		if event.Type != EventTypeKey {
 			continue
 		}
 		switch event.Value {
 		case 0:
-			s.leftButtonDown.Store(false)
+			delete(s.heldCodes, event.Code)
 		case 1, 2:
-			s.leftButtonDown.Store(true)
+			s.heldCodes[event.Code] = struct{}{}
 		}
 	}
 }
 
-func (s *Service) releaseLeftButton() {
-	if !s.leftButtonDown.Load() {
+func (s *Service) isCodeHeld(code uint16) bool {
+	s.injectorMu.Lock()
+	defer s.injectorMu.Unlock()
+	_, ok := s.heldCodes[code]
+	return ok
+}
+
+func (s *Service) releaseHeldCodes() {
+	s.releaseMatching(func(uint16) bool { return true })
+}
+
+func (s *Service) releaseCodes(codes ...uint16) {
+	s.releaseMatching(func(code uint16) bool {
+		for _, candidate := range codes {
+			if candidate == code {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+func (s *Service) releaseMatching(match func(code uint16) bool) {
+	s.injectorMu.Lock()
+	defer s.injectorMu.Unlock()
+
+	codes := make([]uint16, 0, len(s.heldCodes))
+	for code := range s.heldCodes {
+		if match(code) {
+			codes = append(codes, code)
+		}
+	}
+	if len(codes) == 0 {
+		return
+	}
+	sort.Slice(codes, func(i, j int) bool { return codes[i] < codes[j] })
+
+	events := make([]Event, 0, len(codes)+1)
+	for _, code := range codes {
+		events = append(events, Event{Type: EventTypeKey, Code: code, Value: 0})
+	}
+	events = append(events, Event{Type: EventTypeSyn, Code: SynReportCode, Value: 0})
+	if err := s.injector.WriteEvents(events...); err != nil {
+		s.logger.Warn("Failed to release held buttons", "err", err)
 		return
 	}
-	if err := s.writeEvents(
-		Event{Type: EventTypeKey, Code: LeftButtonCode, Value: 0},
-		Event{Type: EventTypeSyn, Code: SynReportCode, Value: 0},
-	); err != nil {
-		s.logger.Warn("Failed to release left button", "err", err)
+	for _, code := range codes {
+		delete(s.heldCodes, code)
 	}
 }
 
-func (s *Service) randomJitterOffsets() (int32, int32) {
-	maxOffset := s.currentJitterPixels()
+func randomJitterOffsets(rng *rand.Rand, maxOffset int32) (int32, int32) {
 	if maxOffset <= 0 {
 		return 0, 0
 	}
 
 	rangeSize := int(maxOffset*2 + 1)
-	dx := int32(rand.Intn(rangeSize)) - maxOffset
-	dy := int32(rand.Intn(rangeSize)) - maxOffset
+	dx := int32(rng.Intn(rangeSize)) - maxOffset
+	dy := int32(rng.Intn(rangeSize)) - maxOffset
 	return dx, dy
 }
 
-func (s *Service) emitJitterMove(dx, dy int32) bool {
+func (s *Service) emitJitterMove(b *bindingState, dx, dy int32) bool {
 	events := make([]Event, 0, 3)
 	if dx != 0 {
 		events = append(events, Event{Type: EventTypeRel, Code: RelXCode, Value: dx})
@@ -494,11 +1036,11 @@ func (s *Service) emitJitterMove(dx, dy int32) bool {
 	}
 	events = append(events, Event{Type: EventTypeSyn, Code: SynReportCode, Value: 0})
 	if err := s.writeEvents(events...); err != nil {
-		if s.stopped() {
+		if s.bindingStopped(b) {
 			return false
 		}
 		s.logger.Warn("Failed to emit jitter move", "dx", dx, "dy", dy, "err", err)
-		if !s.sleepWithStop(100 * time.Millisecond) {
+		if !s.sleepWithStop(b, 100*time.Millisecond) {
 			return false
 		}
 	}
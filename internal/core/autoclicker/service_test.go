@@ -1,191 +1,141 @@
-package autoclicker
+package autoclicker_test
 
 import (
-	"sync"
+	"math/rand"
+	"reflect"
 	"testing"
 	"time"
-)
-
-type recordingInjector struct {
-	mu     sync.Mutex
-	events []Event
-	closed bool
-}
-
-func (r *recordingInjector) WriteEvents(events ...Event) error {
-	r.mu.Lock()
-	defer r.mu.Unlock()
-	r.events = append(r.events, events...)
-	return nil
-}
-
-func (r *recordingInjector) Close() error {
-	r.mu.Lock()
-	defer r.mu.Unlock()
-	r.closed = true
-	return nil
-}
-
-func (r *recordingInjector) snapshot() []Event {
-	r.mu.Lock()
-	defer r.mu.Unlock()
-	out := make([]Event, len(r.events))
-	copy(out, r.events)
-	return out
-}
-
-func (r *recordingInjector) isClosed() bool {
-	r.mu.Lock()
-	defer r.mu.Unlock()
-	return r.closed
-}
-
-type noopLogger struct{}
 
-func (noopLogger) Debug(string, ...any) {}
-func (noopLogger) Info(string, ...any)  {}
-func (noopLogger) Warn(string, ...any)  {}
-func (noopLogger) Error(string, ...any) {}
-
-func testConfig(startEnabled bool) Config {
-	return Config{
-		TriggerCode:    LeftButtonCode,
-		ToggleCode:     LeftButtonCode + 1,
-		TriggerSources: map[string]struct{}{"device": {}},
-		ToggleSources:  map[string]struct{}{"device": {}},
-		GrabSources:    map[string]struct{}{},
-		GrabEnabled:    false,
-		CPS:            10,
-		StartEnabled:   startEnabled,
-	}
-}
+	"clicker/internal/core/autoclicker"
+	"clicker/internal/core/autoclicker/autoclickertest"
+)
 
-func assertReleaseSuffix(t *testing.T, events []Event) {
+func assertReleaseSuffix(t *testing.T, events []autoclicker.Event) {
 	t.Helper()
 	if len(events) < 2 {
 		t.Fatalf("expected at least 2 events, got %d", len(events))
 	}
 	up := events[len(events)-2]
 	syn := events[len(events)-1]
-	if up != (Event{Type: EventTypeKey, Code: LeftButtonCode, Value: 0}) {
+	if up != (autoclicker.Event{Type: autoclicker.EventTypeKey, Code: autoclicker.LeftButtonCode, Value: 0}) {
 		t.Fatalf("unexpected release event: %#v", up)
 	}
-	if syn != (Event{Type: EventTypeSyn, Code: SynReportCode, Value: 0}) {
+	if syn != (autoclicker.Event{Type: autoclicker.EventTypeSyn, Code: autoclicker.SynReportCode, Value: 0}) {
 		t.Fatalf("unexpected sync event: %#v", syn)
 	}
 }
 
 func TestSetEnabledFalseReleasesLeftButton(t *testing.T) {
-	injector := &recordingInjector{}
-	service, err := NewService(testConfig(true), injector, noopLogger{})
+	injector := &autoclickertest.Injector{}
+	service, err := autoclicker.NewService(autoclickertest.Config(true), injector, &autoclickertest.Logger{})
 	if err != nil {
 		t.Fatalf("NewService() error = %v", err)
 	}
 
-	if err := service.writeEvents(
-		Event{Type: EventTypeKey, Code: LeftButtonCode, Value: 1},
-		Event{Type: EventTypeSyn, Code: SynReportCode, Value: 0},
+	if err := service.WriteEvents(
+		autoclicker.Event{Type: autoclicker.EventTypeKey, Code: autoclicker.LeftButtonCode, Value: 1},
+		autoclicker.Event{Type: autoclicker.EventTypeSyn, Code: autoclicker.SynReportCode, Value: 0},
 	); err != nil {
-		t.Fatalf("writeEvents() error = %v", err)
+		t.Fatalf("WriteEvents() error = %v", err)
 	}
-	if !service.leftButtonDown.Load() {
+	if !service.IsCodeHeld(autoclicker.LeftButtonCode) {
 		t.Fatalf("expected left button to be tracked as down")
 	}
 
 	service.SetEnabled(false)
 
-	if service.leftButtonDown.Load() {
+	if service.IsCodeHeld(autoclicker.LeftButtonCode) {
 		t.Fatalf("expected left button to be tracked as up after disabling")
 	}
-	assertReleaseSuffix(t, injector.snapshot())
+	assertReleaseSuffix(t, injector.Events())
 }
 
 func TestSetEnabledTrueReleasesStaleLeftButton(t *testing.T) {
-	injector := &recordingInjector{}
-	service, err := NewService(testConfig(false), injector, noopLogger{})
+	injector := &autoclickertest.Injector{}
+	service, err := autoclicker.NewService(autoclickertest.Config(false), injector, &autoclickertest.Logger{})
 	if err != nil {
 		t.Fatalf("NewService() error = %v", err)
 	}
 
-	if err := service.writeEvents(
-		Event{Type: EventTypeKey, Code: LeftButtonCode, Value: 1},
-		Event{Type: EventTypeSyn, Code: SynReportCode, Value: 0},
+	if err := service.WriteEvents(
+		autoclicker.Event{Type: autoclicker.EventTypeKey, Code: autoclicker.LeftButtonCode, Value: 1},
+		autoclicker.Event{Type: autoclicker.EventTypeSyn, Code: autoclicker.SynReportCode, Value: 0},
 	); err != nil {
-		t.Fatalf("writeEvents() error = %v", err)
+		t.Fatalf("WriteEvents() error = %v", err)
 	}
-	if !service.leftButtonDown.Load() {
+	if !service.IsCodeHeld(autoclicker.LeftButtonCode) {
 		t.Fatalf("expected left button to be tracked as down")
 	}
 
 	service.SetEnabled(true)
 
-	if service.leftButtonDown.Load() {
+	if service.IsCodeHeld(autoclicker.LeftButtonCode) {
 		t.Fatalf("expected left button to be tracked as up after enabling")
 	}
-	assertReleaseSuffix(t, injector.snapshot())
+	assertReleaseSuffix(t, injector.Events())
 }
 
 func TestStopReleasesLeftButtonBeforeClosingInjector(t *testing.T) {
-	injector := &recordingInjector{}
-	service, err := NewService(testConfig(true), injector, noopLogger{})
+	injector := &autoclickertest.Injector{}
+	service, err := autoclicker.NewService(autoclickertest.Config(true), injector, &autoclickertest.Logger{})
 	if err != nil {
 		t.Fatalf("NewService() error = %v", err)
 	}
 
-	if err := service.writeEvents(
-		Event{Type: EventTypeKey, Code: LeftButtonCode, Value: 1},
-		Event{Type: EventTypeSyn, Code: SynReportCode, Value: 0},
+	if err := service.WriteEvents(
+		autoclicker.Event{Type: autoclicker.EventTypeKey, Code: autoclicker.LeftButtonCode, Value: 1},
+		autoclicker.Event{Type: autoclicker.EventTypeSyn, Code: autoclicker.SynReportCode, Value: 0},
 	); err != nil {
-		t.Fatalf("writeEvents() error = %v", err)
+		t.Fatalf("WriteEvents() error = %v", err)
 	}
 
 	service.Stop()
 
-	if !injector.isClosed() {
+	if !injector.Closed() {
 		t.Fatalf("expected injector to be closed")
 	}
-	assertReleaseSuffix(t, injector.snapshot())
+	assertReleaseSuffix(t, injector.Events())
 }
 
 func TestHandleTriggerEventSignalsWakeOnFirstPressOnly(t *testing.T) {
-	cfg := testConfig(true)
-	cfg.TriggerCode = LeftButtonCode + 2
+	cfg := autoclickertest.Config(true)
+	cfg.TriggerCode = autoclicker.LeftButtonCode + 2
 	cfg.ToggleCode = cfg.TriggerCode + 1
 
-	service, err := NewService(cfg, &recordingInjector{}, noopLogger{})
+	service, err := autoclicker.NewService(cfg, &autoclickertest.Injector{}, &autoclickertest.Logger{})
 	if err != nil {
 		t.Fatalf("NewService() error = %v", err)
 	}
 
-	service.handleTriggerEvent("device", 1)
+	service.HandleTriggerEvent(service.Primary(), "device", 1)
 	select {
-	case <-service.wakeCh:
+	case <-service.Primary().WakeCh():
 	default:
 		t.Fatalf("expected wake signal on first trigger press")
 	}
 
-	service.handleTriggerEvent("device", 2)
+	service.HandleTriggerEvent(service.Primary(), "device", 2)
 	select {
-	case <-service.wakeCh:
+	case <-service.Primary().WakeCh():
 		t.Fatalf("expected no wake signal for repeat press while already holding")
 	default:
 	}
 
-	service.handleTriggerEvent("device", 0)
-	service.handleTriggerEvent("device", 1)
+	service.HandleTriggerEvent(service.Primary(), "device", 0)
+	service.HandleTriggerEvent(service.Primary(), "device", 1)
 	select {
-	case <-service.wakeCh:
+	case <-service.Primary().WakeCh():
 	default:
 		t.Fatalf("expected wake signal after trigger press transition")
 	}
 }
 
 func TestWaitWithWakeReturnsOnSignal(t *testing.T) {
-	cfg := testConfig(true)
-	cfg.TriggerCode = LeftButtonCode + 2
+	cfg := autoclickertest.Config(true)
+	cfg.TriggerCode = autoclicker.LeftButtonCode + 2
 	cfg.ToggleCode = cfg.TriggerCode + 1
 
-	service, err := NewService(cfg, &recordingInjector{}, noopLogger{})
+	service, err := autoclicker.NewService(cfg, &autoclickertest.Injector{}, &autoclickertest.Logger{})
 	if err != nil {
 		t.Fatalf("NewService() error = %v", err)
 	}
@@ -193,7 +143,7 @@ func TestWaitWithWakeReturnsOnSignal(t *testing.T) {
 	done := make(chan time.Duration, 1)
 	go func() {
 		start := time.Now()
-		if !service.waitWithWake(5 * time.Second) {
+		if !service.WaitWithWake(service.Primary(), 5*time.Second) {
 			done <- -1
 			return
 		}
@@ -201,7 +151,7 @@ func TestWaitWithWakeReturnsOnSignal(t *testing.T) {
 	}()
 
 	time.Sleep(20 * time.Millisecond)
-	service.signalWake()
+	service.Primary().SignalWake()
 
 	select {
 	case elapsed := <-done:
@@ -217,66 +167,66 @@ func TestWaitWithWakeReturnsOnSignal(t *testing.T) {
 }
 
 func TestSetToggleCodeAppliesToKnownSources(t *testing.T) {
-	cfg := testConfig(true)
-	cfg.TriggerCode = LeftButtonCode + 2
+	cfg := autoclickertest.Config(true)
+	cfg.TriggerCode = autoclicker.LeftButtonCode + 2
 	cfg.ToggleCode = cfg.TriggerCode + 1
 	cfg.TriggerSources = map[string]struct{}{"trigger-device": {}}
 	cfg.ToggleSources = map[string]struct{}{"toggle-device": {}}
 
-	service, err := NewService(cfg, &recordingInjector{}, noopLogger{})
+	service, err := autoclicker.NewService(cfg, &autoclickertest.Injector{}, &autoclickertest.Logger{})
 	if err != nil {
 		t.Fatalf("NewService() error = %v", err)
 	}
 
 	newToggle := cfg.ToggleCode + 5
 
-	service.handleEvent("trigger-device", Event{Type: EventTypeKey, Code: newToggle, Value: 1})
+	service.HandleEvent("trigger-device", autoclicker.Event{Type: autoclicker.EventTypeKey, Code: newToggle, Value: 1})
 	if !service.IsEnabled() {
 		t.Fatalf("unexpected toggle before SetToggleCode")
 	}
 
 	service.SetToggleCode(newToggle)
 
-	service.handleEvent("trigger-device", Event{Type: EventTypeKey, Code: newToggle, Value: 1})
+	service.HandleEvent("trigger-device", autoclicker.Event{Type: autoclicker.EventTypeKey, Code: newToggle, Value: 1})
 	if service.IsEnabled() {
 		t.Fatalf("expected toggle after SetToggleCode on known source")
 	}
 }
 
 func TestSetTriggerCodeSwitchesHandledTrigger(t *testing.T) {
-	cfg := testConfig(true)
-	cfg.TriggerCode = LeftButtonCode + 2
+	cfg := autoclickertest.Config(true)
+	cfg.TriggerCode = autoclicker.LeftButtonCode + 2
 	cfg.ToggleCode = cfg.TriggerCode + 1
 
-	service, err := NewService(cfg, &recordingInjector{}, noopLogger{})
+	service, err := autoclicker.NewService(cfg, &autoclickertest.Injector{}, &autoclickertest.Logger{})
 	if err != nil {
 		t.Fatalf("NewService() error = %v", err)
 	}
 
-	service.handleEvent("device", Event{Type: EventTypeKey, Code: cfg.TriggerCode, Value: 1})
-	if !service.holding.Load() {
+	service.HandleEvent("device", autoclicker.Event{Type: autoclicker.EventTypeKey, Code: cfg.TriggerCode, Value: 1})
+	if !service.Primary().Holding() {
 		t.Fatalf("expected holding after initial trigger press")
 	}
 
 	newTrigger := cfg.TriggerCode + 5
 	service.SetTriggerCode(newTrigger)
-	if service.holding.Load() {
+	if service.Primary().Holding() {
 		t.Fatalf("expected holding cleared after SetTriggerCode")
 	}
 
-	service.handleEvent("device", Event{Type: EventTypeKey, Code: cfg.TriggerCode, Value: 1})
-	if service.holding.Load() {
+	service.HandleEvent("device", autoclicker.Event{Type: autoclicker.EventTypeKey, Code: cfg.TriggerCode, Value: 1})
+	if service.Primary().Holding() {
 		t.Fatalf("old trigger code should no longer activate holding")
 	}
 
-	service.handleEvent("device", Event{Type: EventTypeKey, Code: newTrigger, Value: 1})
-	if !service.holding.Load() {
+	service.HandleEvent("device", autoclicker.Event{Type: autoclicker.EventTypeKey, Code: newTrigger, Value: 1})
+	if !service.Primary().Holding() {
 		t.Fatalf("new trigger code should activate holding")
 	}
 }
 
 func TestSetJitterRejectsNegative(t *testing.T) {
-	service, err := NewService(testConfig(true), &recordingInjector{}, noopLogger{})
+	service, err := autoclicker.NewService(autoclickertest.Config(true), &autoclickertest.Injector{}, &autoclickertest.Logger{})
 	if err != nil {
 		t.Fatalf("NewService() error = %v", err)
 	}
@@ -287,25 +237,25 @@ func TestSetJitterRejectsNegative(t *testing.T) {
 	if err := service.SetJitter(3); err != nil {
 		t.Fatalf("SetJitter() error = %v", err)
 	}
-	if got := service.currentJitterPixels(); got != 3 {
+	if got := service.Primary().JitterPixels(); got != 3 {
 		t.Fatalf("currentJitterPixels() = %d, want 3", got)
 	}
 }
 
 func TestClickOnceEmitsAndRestoresJitterMotion(t *testing.T) {
-	cfg := testConfig(true)
+	cfg := autoclickertest.Config(true)
 	cfg.CPS = 100
 	cfg.ClickDown = 0
 	cfg.JitterPixels = 3
 
-	injector := &recordingInjector{}
-	service, err := NewService(cfg, injector, noopLogger{})
+	injector := &autoclickertest.Injector{}
+	service, err := autoclicker.NewService(cfg, injector, &autoclickertest.Logger{})
 	if err != nil {
 		t.Fatalf("NewService() error = %v", err)
 	}
 
 	for i := 0; i < 250; i++ {
-		if ok := service.clickOnce(); !ok {
+		if ok := service.ClickOnce(service.Primary(), rand.New(rand.NewSource(1)), []uint16{service.Primary().OutputCode()}, 100*time.Millisecond, 0); !ok {
 			t.Fatalf("clickOnce() returned false at iteration %d", i)
 		}
 	}
@@ -315,15 +265,15 @@ func TestClickOnceEmitsAndRestoresJitterMotion(t *testing.T) {
 		sumX     int32
 		sumY     int32
 	)
-	for _, event := range injector.snapshot() {
-		if event.Type != EventTypeRel {
+	for _, event := range injector.Events() {
+		if event.Type != autoclicker.EventTypeRel {
 			continue
 		}
 		relCount++
 		switch event.Code {
-		case RelXCode:
+		case autoclicker.RelXCode:
 			sumX += event.Value
-		case RelYCode:
+		case autoclicker.RelYCode:
 			sumY += event.Value
 		}
 	}
@@ -337,20 +287,434 @@ func TestClickOnceEmitsAndRestoresJitterMotion(t *testing.T) {
 }
 
 func TestGrabTriggerPassesThroughWhenConfigured(t *testing.T) {
-	cfg := testConfig(true)
+	cfg := autoclickertest.Config(true)
 	cfg.GrabEnabled = true
 	cfg.GrabSources = map[string]struct{}{"device": {}}
 	cfg.PassThroughTrigger = true
 
-	injector := &recordingInjector{}
-	service, err := NewService(cfg, injector, noopLogger{})
+	injector := &autoclickertest.Injector{}
+	service, err := autoclicker.NewService(cfg, injector, &autoclickertest.Logger{})
 	if err != nil {
 		t.Fatalf("NewService() error = %v", err)
 	}
 
-	service.handleEvent("device", Event{Type: EventTypeKey, Code: cfg.TriggerCode, Value: 1})
-	events := injector.snapshot()
+	service.HandleEvent("device", autoclicker.Event{Type: autoclicker.EventTypeKey, Code: cfg.TriggerCode, Value: 1})
+	events := injector.Events()
 	if len(events) == 0 {
 		t.Fatalf("expected trigger down to be passed through when PassThroughTrigger is enabled")
 	}
 }
+
+func TestClickOnceEmitsConfiguredOutputCode(t *testing.T) {
+	cfg := autoclickertest.Config(true)
+	cfg.ClickDown = 0
+	cfg.OutputCode = autoclicker.LeftButtonCode + 1
+	cfg.ToggleCode = autoclicker.LeftButtonCode + 2
+
+	injector := &autoclickertest.Injector{}
+	service, err := autoclicker.NewService(cfg, injector, &autoclickertest.Logger{})
+	if err != nil {
+		t.Fatalf("NewService() error = %v", err)
+	}
+
+	if ok := service.ClickOnce(service.Primary(), rand.New(rand.NewSource(1)), []uint16{service.Primary().OutputCode()}, 100*time.Millisecond, 0); !ok {
+		t.Fatalf("clickOnce() returned false")
+	}
+
+	want := []autoclicker.Event{
+		{Type: autoclicker.EventTypeKey, Code: cfg.OutputCode, Value: 1},
+		{Type: autoclicker.EventTypeSyn, Code: autoclicker.SynReportCode, Value: 0},
+		{Type: autoclicker.EventTypeKey, Code: cfg.OutputCode, Value: 0},
+		{Type: autoclicker.EventTypeSyn, Code: autoclicker.SynReportCode, Value: 0},
+	}
+	got := injector.Events()
+	if len(got) != len(want) {
+		t.Fatalf("got %d events, want %d: %#v", len(got), len(want), got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("event %d = %#v, want %#v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestSetOutputCodeReleasesPreviouslyHeldOutput(t *testing.T) {
+	injector := &autoclickertest.Injector{}
+	service, err := autoclicker.NewService(autoclickertest.Config(true), injector, &autoclickertest.Logger{})
+	if err != nil {
+		t.Fatalf("NewService() error = %v", err)
+	}
+
+	if err := service.WriteEvents(
+		autoclicker.Event{Type: autoclicker.EventTypeKey, Code: autoclicker.LeftButtonCode, Value: 1},
+		autoclicker.Event{Type: autoclicker.EventTypeSyn, Code: autoclicker.SynReportCode, Value: 0},
+	); err != nil {
+		t.Fatalf("WriteEvents() error = %v", err)
+	}
+
+	newOutput := autoclicker.LeftButtonCode + 2
+	service.SetOutputCode(newOutput)
+
+	if service.IsCodeHeld(autoclicker.LeftButtonCode) {
+		t.Fatalf("expected previous output to be released after SetOutputCode")
+	}
+	assertReleaseSuffix(t, injector.Events())
+	if got := service.Primary().OutputCode(); got != newOutput {
+		t.Fatalf("currentOutputCode() = %d, want %d", got, newOutput)
+	}
+}
+
+func TestTriggerMatchingOutputIsNeutralizedWithoutGrab(t *testing.T) {
+	cfg := autoclickertest.Config(true)
+	cfg.TriggerCode = autoclicker.LeftButtonCode + 1
+	cfg.OutputCode = cfg.TriggerCode
+	cfg.ToggleCode = autoclicker.LeftButtonCode + 2
+
+	injector := &autoclickertest.Injector{}
+	service, err := autoclicker.NewService(cfg, injector, &autoclickertest.Logger{})
+	if err != nil {
+		t.Fatalf("NewService() error = %v", err)
+	}
+
+	service.HandleTriggerEvent(service.Primary(), "device", 1)
+
+	events := injector.Events()
+	if len(events) != 2 || events[0] != (autoclicker.Event{Type: autoclicker.EventTypeKey, Code: cfg.OutputCode, Value: 0}) {
+		t.Fatalf("expected trigger hold to be neutralized on output code, got %#v", events)
+	}
+}
+
+func TestBindingsTrackTriggersIndependently(t *testing.T) {
+	cfg := autoclickertest.Config(true)
+	cfg.TriggerCode = autoclicker.LeftButtonCode + 3
+	cfg.ToggleCode = autoclicker.LeftButtonCode + 5
+	cfg.Bindings = []autoclicker.Binding{
+		{TriggerCode: autoclicker.LeftButtonCode + 4, OutputCode: autoclicker.LeftButtonCode + 1, CPS: 10},
+	}
+
+	service, err := autoclicker.NewService(cfg, &autoclickertest.Injector{}, &autoclickertest.Logger{})
+	if err != nil {
+		t.Fatalf("NewService() error = %v", err)
+	}
+	extra := service.BindingStates()[1]
+
+	service.HandleEvent("device", autoclicker.Event{Type: autoclicker.EventTypeKey, Code: autoclicker.LeftButtonCode + 4, Value: 1})
+	if !extra.Holding() {
+		t.Fatalf("expected extra binding to hold after its trigger press")
+	}
+	if service.Primary().Holding() {
+		t.Fatalf("primary binding should not react to another binding's trigger")
+	}
+
+	service.HandleEvent("device", autoclicker.Event{Type: autoclicker.EventTypeKey, Code: cfg.TriggerCode, Value: 1})
+	service.HandleEvent("device", autoclicker.Event{Type: autoclicker.EventTypeKey, Code: autoclicker.LeftButtonCode + 4, Value: 0})
+	if extra.Holding() {
+		t.Fatalf("expected extra binding to stop holding after release")
+	}
+	if !service.Primary().Holding() {
+		t.Fatalf("expected primary binding to keep holding")
+	}
+}
+
+func TestBindingClickLoopEmitsItsOwnOutput(t *testing.T) {
+	cfg := autoclickertest.Config(true)
+	cfg.TriggerCode = autoclicker.LeftButtonCode + 3
+	cfg.ToggleCode = autoclicker.LeftButtonCode + 5
+	cfg.Bindings = []autoclicker.Binding{
+		{TriggerCode: autoclicker.LeftButtonCode + 4, OutputCode: autoclicker.LeftButtonCode + 1, CPS: 200},
+	}
+
+	injector := &autoclickertest.Injector{}
+	service, err := autoclicker.NewService(cfg, injector, &autoclickertest.Logger{})
+	if err != nil {
+		t.Fatalf("NewService() error = %v", err)
+	}
+	service.Start()
+	defer service.Stop()
+
+	service.SubmitEvent("device", autoclicker.Event{Type: autoclicker.EventTypeKey, Code: autoclicker.LeftButtonCode + 4, Value: 1})
+	time.Sleep(50 * time.Millisecond)
+	service.SubmitEvent("device", autoclicker.Event{Type: autoclicker.EventTypeKey, Code: autoclicker.LeftButtonCode + 4, Value: 0})
+	time.Sleep(10 * time.Millisecond)
+
+	var downs int
+	for _, event := range injector.Events() {
+		if event.Type != autoclicker.EventTypeKey || event.Value != 1 {
+			continue
+		}
+		if event.Code != autoclicker.LeftButtonCode+1 {
+			t.Fatalf("unexpected output code %d from extra binding", event.Code)
+		}
+		downs++
+	}
+	if downs == 0 {
+		t.Fatalf("expected extra binding to emit clicks")
+	}
+}
+
+func TestSetBindingsReplacesAndReleasesRemovedOutput(t *testing.T) {
+	cfg := autoclickertest.Config(true)
+	cfg.Bindings = []autoclicker.Binding{
+		{TriggerCode: autoclicker.LeftButtonCode + 4, OutputCode: autoclicker.LeftButtonCode + 2, CPS: 10},
+	}
+
+	injector := &autoclickertest.Injector{}
+	service, err := autoclicker.NewService(cfg, injector, &autoclickertest.Logger{})
+	if err != nil {
+		t.Fatalf("NewService() error = %v", err)
+	}
+	service.Start()
+	defer service.Stop()
+
+	if err := service.WriteEvents(
+		autoclicker.Event{Type: autoclicker.EventTypeKey, Code: autoclicker.LeftButtonCode + 2, Value: 1},
+		autoclicker.Event{Type: autoclicker.EventTypeSyn, Code: autoclicker.SynReportCode, Value: 0},
+	); err != nil {
+		t.Fatalf("WriteEvents() error = %v", err)
+	}
+
+	if err := service.SetBindings([]autoclicker.Binding{{TriggerCode: autoclicker.LeftButtonCode + 4, CPS: 0}}); err == nil {
+		t.Fatalf("expected error for binding with non-positive cps")
+	}
+	if got := len(service.Bindings()); got != 1 {
+		t.Fatalf("invalid SetBindings should keep previous bindings, got %d", got)
+	}
+
+	if err := service.SetBindings(nil); err != nil {
+		t.Fatalf("SetBindings() error = %v", err)
+	}
+	if got := len(service.Bindings()); got != 0 {
+		t.Fatalf("Bindings() returned %d entries, want 0", got)
+	}
+	if service.IsCodeHeld(autoclicker.LeftButtonCode + 2) {
+		t.Fatalf("expected removed binding output to be released")
+	}
+}
+
+func TestLatchModeTogglesClickingOnEachPress(t *testing.T) {
+	cfg := autoclickertest.Config(true)
+	cfg.TriggerCode = autoclicker.LeftButtonCode + 2
+	cfg.ToggleCode = cfg.TriggerCode + 1
+	cfg.TriggerMode = autoclicker.TriggerModeLatch
+
+	service, err := autoclicker.NewService(cfg, &autoclickertest.Injector{}, &autoclickertest.Logger{})
+	if err != nil {
+		t.Fatalf("NewService() error = %v", err)
+	}
+	b := service.Primary()
+
+	service.HandleTriggerEvent(b, "device", 1)
+	service.HandleTriggerEvent(b, "device", 0)
+	if !b.Holding() {
+		t.Fatalf("expected latch to keep clicking after release")
+	}
+
+	service.HandleTriggerEvent(b, "device", 1)
+	if b.Holding() {
+		t.Fatalf("expected second press to stop clicking")
+	}
+	service.HandleTriggerEvent(b, "device", 0)
+	if b.Holding() {
+		t.Fatalf("expected release of the unlatching press to keep clicking stopped")
+	}
+
+	service.HandleTriggerEvent(b, "device", 1)
+	if !b.Holding() {
+		t.Fatalf("expected third press to latch again")
+	}
+}
+
+func TestLatchModeIgnoresRepeatsAndSecondSource(t *testing.T) {
+	cfg := autoclickertest.Config(true)
+	cfg.TriggerCode = autoclicker.LeftButtonCode + 2
+	cfg.ToggleCode = cfg.TriggerCode + 1
+	cfg.TriggerSources["other"] = struct{}{}
+	cfg.TriggerMode = autoclicker.TriggerModeLatch
+
+	service, err := autoclicker.NewService(cfg, &autoclickertest.Injector{}, &autoclickertest.Logger{})
+	if err != nil {
+		t.Fatalf("NewService() error = %v", err)
+	}
+	b := service.Primary()
+
+	service.HandleTriggerEvent(b, "device", 1)
+	service.HandleTriggerEvent(b, "device", 2)
+	service.HandleTriggerEvent(b, "other", 1)
+	if !b.Holding() {
+		t.Fatalf("expected repeats and overlapping presses not to unlatch")
+	}
+}
+
+func TestHoldOrLatchModeDependsOnPressDuration(t *testing.T) {
+	cfg := autoclickertest.Config(true)
+	cfg.TriggerCode = autoclicker.LeftButtonCode + 2
+	cfg.ToggleCode = cfg.TriggerCode + 1
+	cfg.TriggerMode = autoclicker.TriggerModeHoldOrLatch
+	cfg.LatchThreshold = time.Hour
+
+	service, err := autoclicker.NewService(cfg, &autoclickertest.Injector{}, &autoclickertest.Logger{})
+	if err != nil {
+		t.Fatalf("NewService() error = %v", err)
+	}
+	b := service.Primary()
+
+	service.HandleTriggerEvent(b, "device", 1)
+	service.HandleTriggerEvent(b, "device", 0)
+	if !b.Holding() {
+		t.Fatalf("expected short press to latch")
+	}
+	service.HandleTriggerEvent(b, "device", 1)
+	service.HandleTriggerEvent(b, "device", 0)
+	if b.Holding() {
+		t.Fatalf("expected next press to end the latch")
+	}
+
+	service.HandleTriggerEvent(b, "device", 1)
+	if !b.Holding() {
+		t.Fatalf("expected press to start clicking")
+	}
+	service.AgePress(b, 2*time.Hour)
+	service.HandleTriggerEvent(b, "device", 0)
+	if b.Holding() {
+		t.Fatalf("expected long press to behave as hold")
+	}
+}
+
+func TestSetEnabledClearsLatch(t *testing.T) {
+	cfg := autoclickertest.Config(true)
+	cfg.TriggerCode = autoclicker.LeftButtonCode + 2
+	cfg.ToggleCode = cfg.TriggerCode + 1
+	cfg.TriggerMode = autoclicker.TriggerModeLatch
+
+	service, err := autoclicker.NewService(cfg, &autoclickertest.Injector{}, &autoclickertest.Logger{})
+	if err != nil {
+		t.Fatalf("NewService() error = %v", err)
+	}
+	b := service.Primary()
+
+	service.HandleTriggerEvent(b, "device", 1)
+	service.HandleTriggerEvent(b, "device", 0)
+	service.SetEnabled(false)
+	service.SetEnabled(true)
+	if b.Holding() {
+		t.Fatalf("expected toggling off to drop the latch")
+	}
+
+	service.HandleTriggerEvent(b, "device", 1)
+	if !b.Holding() {
+		t.Fatalf("expected first press after re-enable to latch")
+	}
+}
+
+func TestSetTriggerModeRejectsUnknownMode(t *testing.T) {
+	service, err := autoclicker.NewService(autoclickertest.Config(true), &autoclickertest.Injector{}, &autoclickertest.Logger{})
+	if err != nil {
+		t.Fatalf("NewService() error = %v", err)
+	}
+	if err := service.SetTriggerMode(autoclicker.TriggerMode(99)); err == nil {
+		t.Fatalf("expected unknown trigger mode to be rejected")
+	}
+	if err := service.SetTriggerMode(autoclicker.TriggerModeLatch); err != nil {
+		t.Fatalf("SetTriggerMode() error = %v", err)
+	}
+	if got := service.TriggerMode(); got != autoclicker.TriggerModeLatch {
+		t.Fatalf("TriggerMode() = %v, want %v", got, autoclicker.TriggerModeLatch)
+	}
+}
+
+func TestParseTriggerMode(t *testing.T) {
+	for _, mode := range []autoclicker.TriggerMode{autoclicker.TriggerModeHold, autoclicker.TriggerModeLatch, autoclicker.TriggerModeHoldOrLatch} {
+		got, err := autoclicker.ParseTriggerMode(mode.String())
+		if err != nil || got != mode {
+			t.Fatalf("ParseTriggerMode(%q) = %v, %v", mode.String(), got, err)
+		}
+	}
+	if _, err := autoclicker.ParseTriggerMode("toggle"); err == nil {
+		t.Fatalf("expected unknown name to be rejected")
+	}
+}
+
+func countKeyDowns(events []autoclicker.Event, code uint16) int {
+	var downs int
+	for _, event := range events {
+		if event.Type == autoclicker.EventTypeKey && event.Code == code && event.Value == 1 {
+			downs++
+		}
+	}
+	return downs
+}
+
+func TestBurstEmitsFixedClicksPerPress(t *testing.T) {
+	cfg := autoclickertest.Config(true)
+	cfg.TriggerCode = autoclicker.LeftButtonCode + 3
+	cfg.ToggleCode = autoclicker.LeftButtonCode + 5
+	cfg.CPS = 500
+	cfg.BurstCount = 3
+
+	injector := &autoclickertest.Injector{}
+	service, err := autoclicker.NewService(cfg, injector, &autoclickertest.Logger{})
+	if err != nil {
+		t.Fatalf("NewService() error = %v", err)
+	}
+	service.Start()
+	defer service.Stop()
+
+	service.SubmitEvent("device", autoclicker.Event{Type: autoclicker.EventTypeKey, Code: cfg.TriggerCode, Value: 1})
+	time.Sleep(60 * time.Millisecond)
+	if got := countKeyDowns(injector.Events(), autoclicker.LeftButtonCode); got != 3 {
+		t.Fatalf("expected 3 clicks while trigger is still held, got %d", got)
+	}
+
+	service.SubmitEvent("device", autoclicker.Event{Type: autoclicker.EventTypeKey, Code: cfg.TriggerCode, Value: 0})
+	service.SubmitEvent("device", autoclicker.Event{Type: autoclicker.EventTypeKey, Code: cfg.TriggerCode, Value: 1})
+	time.Sleep(60 * time.Millisecond)
+	if got := countKeyDowns(injector.Events(), autoclicker.LeftButtonCode); got != 6 {
+		t.Fatalf("expected a second burst after the next press, got %d clicks", got)
+	}
+}
+
+func TestBurstCooldownDelaysNextBurst(t *testing.T) {
+	cfg := autoclickertest.Config(true)
+	cfg.BurstCount = 2
+	cfg.BurstCooldown = time.Second
+
+	result := autoclickertest.Run(t, autoclickertest.Scenario{
+		Config: cfg,
+		Steps: []autoclickertest.Step{
+			// The spent burst waits for the next press.
+			autoclickertest.Press(0, cfg.TriggerCode),
+			autoclickertest.Release(500*time.Millisecond, cfg.TriggerCode),
+			// The next press waits out the cooldown from the last click.
+			autoclickertest.Press(600*time.Millisecond, cfg.TriggerCode),
+			autoclickertest.Release(1500*time.Millisecond, cfg.TriggerCode),
+			// Once it has elapsed a press clicks right away.
+			autoclickertest.Press(2500*time.Millisecond, cfg.TriggerCode),
+			autoclickertest.Release(2800*time.Millisecond, cfg.TriggerCode),
+		},
+		Until: 3 * time.Second,
+	})
+
+	want := []time.Duration{0, 100 * time.Millisecond, 1100 * time.Millisecond, 1200 * time.Millisecond, 2500 * time.Millisecond, 2600 * time.Millisecond}
+	if got := clickStarts(result.Records, autoclicker.LeftButtonCode); !reflect.DeepEqual(got, want) {
+		t.Fatalf("clicks at %v, want %v", got, want)
+	}
+}
+
+func TestSetBurstRejectsNegativeValues(t *testing.T) {
+	service, err := autoclicker.NewService(autoclickertest.Config(true), &autoclickertest.Injector{}, &autoclickertest.Logger{})
+	if err != nil {
+		t.Fatalf("NewService() error = %v", err)
+	}
+	if err := service.SetBurst(-1, 0); err == nil {
+		t.Fatalf("expected negative burst count to be rejected")
+	}
+	if err := service.SetBurst(2, -time.Second); err == nil {
+		t.Fatalf("expected negative cooldown to be rejected")
+	}
+	if err := service.SetBurst(2, time.Second); err != nil {
+		t.Fatalf("SetBurst() error = %v", err)
+	}
+	if count, cooldown := service.Burst(); count != 2 || cooldown != time.Second {
+		t.Fatalf("Burst() = %d, %v", count, cooldown)
+	}
+}
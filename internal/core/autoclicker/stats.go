@@ -0,0 +1,155 @@
+package autoclicker
+
+import (
+	"slices"
+	"sync"
+	"time"
+)
+
+const (
+	latencySamples = 1024
+	statsWindow    = time.Second
+)
+
+type Stats struct {
+	Clicks          int64
+	ClicksInHold    int64
+	Holds           int64
+	ClickingTime    time.Duration
+	WindowCPS       float64
+	InjectorErrors  int64
+	AchievedCPS     float64
+	MeanLateness    time.Duration
+	P99Lateness     time.Duration
+	MissedDeadlines int64
+	Bounces         int64
+	DeferredClicks  int64
+}
+
+type clickStats struct {
+	mu sync.Mutex
+
+	latenessSum   time.Duration
+	latenessCount int64
+	recent        []time.Duration
+	recentNext    int
+
+	spacingSum   time.Duration
+	spacingCount int64
+
+	missed int64
+
+	window []time.Time
+
+	holds           int64
+	holding         bool
+	holdStartedAt   time.Time
+	holdStartClicks int64
+	clickingTime    time.Duration
+}
+
+func (c *clickStats) recordClick(start time.Time, lateness, spacing time.Duration, missed int64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.window = append(c.pruneWindow(start), start)
+
+	c.latenessSum += lateness
+	c.latenessCount++
+	if len(c.recent) < latencySamples {
+		c.recent = append(c.recent, lateness)
+	} else {
+		c.recent[c.recentNext] = lateness
+		c.recentNext = (c.recentNext + 1) % latencySamples
+	}
+	if spacing > 0 {
+		c.spacingSum += spacing
+		c.spacingCount++
+	}
+	c.missed += missed
+}
+
+func (c *clickStats) pruneWindow(now time.Time) []time.Time {
+	cutoff := now.Add(-statsWindow)
+	drop := 0
+	for drop < len(c.window) && !c.window[drop].After(cutoff) {
+		drop++
+	}
+	if drop > 0 {
+		c.window = append(c.window[:0], c.window[drop:]...)
+	}
+	return c.window
+}
+
+func (c *clickStats) recordHold() {
+	c.mu.Lock()
+	c.holds++
+	c.mu.Unlock()
+}
+
+func (c *clickStats) setHolding(holding bool, now time.Time, clicks int64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if holding == c.holding {
+		return
+	}
+	c.holding = holding
+	if holding {
+		c.holdStartedAt = now
+		c.holdStartClicks = clicks
+		return
+	}
+	c.clickingTime += now.Sub(c.holdStartedAt)
+}
+
+func (c *clickStats) fill(stats *Stats, now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	stats.Holds = c.holds
+	stats.ClickingTime = c.clickingTime
+	if c.holding {
+		stats.ClicksInHold = stats.Clicks - c.holdStartClicks
+		stats.ClickingTime += now.Sub(c.holdStartedAt)
+	}
+	stats.WindowCPS = float64(len(c.pruneWindow(now))) / statsWindow.Seconds()
+
+	if c.spacingSum > 0 {
+		stats.AchievedCPS = float64(c.spacingCount) / c.spacingSum.Seconds()
+	}
+	if c.latenessCount > 0 {
+		stats.MeanLateness = c.latenessSum / time.Duration(c.latenessCount)
+	}
+	if n := len(c.recent); n > 0 {
+		sorted := slices.Clone(c.recent)
+		slices.Sort(sorted)
+		stats.P99Lateness = sorted[(n*99+99)/100-1]
+	}
+	stats.MissedDeadlines = c.missed
+}
+
+func (s *Service) Stats() Stats {
+	stats := Stats{
+		Clicks:         s.clickCount.Load(),
+		InjectorErrors: s.injectorErrors.Load(),
+		Bounces:        s.bounces.Load(),
+		DeferredClicks: s.deferredClicks.Load(),
+	}
+	s.stats.fill(&stats, s.clock.Now())
+	return stats
+}
+
+// Callers must hold stateMu.
+func (s *Service) syncHoldStats() {
+	holding := false
+	if s.enabled.Load() {
+		for _, b := range s.currentBindings() {
+			if b.holding.Load() {
+				holding = true
+				break
+			}
+		}
+	}
+	s.stats.setHolding(holding, s.clock.Now(), s.clickCount.Load())
+}
@@ -0,0 +1,64 @@
+package autoclicker
+
+import (
+	"testing"
+	"time"
+)
+
+func TestStatsReportLatenessAndRate(t *testing.T) {
+	var stats clickStats
+	for i := 1; i <= 100; i++ {
+		stats.recordClick(time.Unix(0, 0), time.Duration(i)*time.Millisecond, 50*time.Millisecond, 0)
+	}
+	stats.recordClick(time.Unix(0, 0), 0, 0, 3)
+
+	var snapshot Stats
+	stats.fill(&snapshot, time.Unix(0, 0))
+	if snapshot.AchievedCPS != 20 {
+		t.Fatalf("AchievedCPS = %v, want 20", snapshot.AchievedCPS)
+	}
+	if want := 5050 * time.Millisecond / 101; snapshot.MeanLateness != want {
+		t.Fatalf("MeanLateness = %v, want %v", snapshot.MeanLateness, want)
+	}
+	if snapshot.P99Lateness != 99*time.Millisecond {
+		t.Fatalf("P99Lateness = %v, want 99ms", snapshot.P99Lateness)
+	}
+	if snapshot.MissedDeadlines != 3 {
+		t.Fatalf("MissedDeadlines = %d, want 3", snapshot.MissedDeadlines)
+	}
+}
+
+func TestStatsTrackHoldsAndClicksInHold(t *testing.T) {
+	var stats clickStats
+	start := time.Unix(100, 0)
+
+	stats.recordHold()
+	stats.setHolding(true, start, 10)
+	stats.recordClick(start.Add(100*time.Millisecond), 0, 0, 0)
+	stats.recordClick(start.Add(600*time.Millisecond), 0, 0, 0)
+
+	snapshot := Stats{Clicks: 12}
+	stats.fill(&snapshot, start.Add(time.Second))
+	if snapshot.Holds != 1 || snapshot.ClicksInHold != 2 {
+		t.Fatalf("Holds = %d, ClicksInHold = %d; want 1, 2", snapshot.Holds, snapshot.ClicksInHold)
+	}
+	if snapshot.ClickingTime != time.Second {
+		t.Fatalf("ClickingTime = %v, want 1s while holding", snapshot.ClickingTime)
+	}
+	if snapshot.WindowCPS != 2 {
+		t.Fatalf("WindowCPS = %v, want 2", snapshot.WindowCPS)
+	}
+
+	stats.setHolding(false, start.Add(2*time.Second), 12)
+	snapshot = Stats{Clicks: 12}
+	stats.fill(&snapshot, start.Add(5*time.Second))
+	if snapshot.ClicksInHold != 0 {
+		t.Fatalf("ClicksInHold = %d after release, want 0", snapshot.ClicksInHold)
+	}
+	if snapshot.ClickingTime != 2*time.Second {
+		t.Fatalf("ClickingTime = %v, want 2s after release", snapshot.ClickingTime)
+	}
+	if snapshot.WindowCPS != 0 {
+		t.Fatalf("WindowCPS = %v, want 0 once clicks leave the window", snapshot.WindowCPS)
+	}
+}
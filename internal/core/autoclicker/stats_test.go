@@ -0,0 +1,79 @@
+package autoclicker_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"clicker/internal/core/autoclicker"
+	"clicker/internal/core/autoclicker/autoclickertest"
+)
+
+func TestServiceStatsTrackClickLoop(t *testing.T) {
+	cfg := autoclickertest.Config(true)
+	cfg.TriggerCode = autoclicker.LeftButtonCode + 3
+	cfg.ToggleCode = autoclicker.LeftButtonCode + 5
+	cfg.CPS = 200
+
+	service, err := autoclicker.NewService(cfg, &autoclickertest.Injector{}, &autoclickertest.Logger{})
+	if err != nil {
+		t.Fatalf("NewService() error = %v", err)
+	}
+	service.Start()
+	defer service.Stop()
+
+	service.SubmitEvent("device", autoclicker.Event{Type: autoclicker.EventTypeKey, Code: cfg.TriggerCode, Value: 1})
+	time.Sleep(100 * time.Millisecond)
+	service.SubmitEvent("device", autoclicker.Event{Type: autoclicker.EventTypeKey, Code: cfg.TriggerCode, Value: 0})
+	time.Sleep(10 * time.Millisecond)
+
+	stats := service.Stats()
+	if stats.Clicks < 2 {
+		t.Fatalf("expected clicks to be counted, got %d", stats.Clicks)
+	}
+	if stats.AchievedCPS <= 0 {
+		t.Fatalf("expected an achieved rate, got %v", stats.AchievedCPS)
+	}
+}
+
+func TestServiceStatsFollowTriggerHolds(t *testing.T) {
+	cfg := autoclickertest.Config(true)
+	cfg.TriggerCode = autoclicker.LeftButtonCode + 2
+	cfg.ToggleCode = cfg.TriggerCode + 1
+
+	service, err := autoclicker.NewService(cfg, &autoclickertest.Injector{}, &autoclickertest.Logger{})
+	if err != nil {
+		t.Fatalf("NewService() error = %v", err)
+	}
+
+	service.HandleTriggerEvent(service.Primary(), "device", 1)
+	service.HandleTriggerEvent(service.Primary(), "device", 2)
+	service.HandleTriggerEvent(service.Primary(), "device", 0)
+	service.HandleTriggerEvent(service.Primary(), "device", 1)
+	stats := service.Stats()
+	if stats.Holds != 2 {
+		t.Fatalf("Holds = %d, want 2", stats.Holds)
+	}
+
+	service.SetEnabled(false)
+	before := service.Stats().ClickingTime
+	time.Sleep(5 * time.Millisecond)
+	if after := service.Stats().ClickingTime; after != before {
+		t.Fatalf("ClickingTime kept growing after disable: %v -> %v", before, after)
+	}
+}
+
+func TestServiceStatsCountInjectorErrors(t *testing.T) {
+	injector := &autoclickertest.Injector{}
+	injector.Fail(errors.New("injector unavailable"))
+	service, err := autoclicker.NewService(autoclickertest.Config(true), injector, &autoclickertest.Logger{})
+	if err != nil {
+		t.Fatalf("NewService() error = %v", err)
+	}
+
+	_ = service.WriteEvents(autoclicker.Event{Type: autoclicker.EventTypeKey, Code: autoclicker.LeftButtonCode, Value: 1})
+	_ = service.WriteEvents(autoclicker.Event{Type: autoclicker.EventTypeKey, Code: autoclicker.LeftButtonCode, Value: 0})
+	if got := service.Stats().InjectorErrors; got != 2 {
+		t.Fatalf("InjectorErrors = %d, want 2", got)
+	}
+}
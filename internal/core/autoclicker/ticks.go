@@ -0,0 +1,103 @@
+package autoclicker
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+const maxTickRate = 1000
+
+type Ticks struct {
+	Rate    float64
+	Phase   time.Duration
+	PerTick int
+}
+
+func (t Ticks) String() string {
+	if t.Rate <= 0 {
+		return "off"
+	}
+	return fmt.Sprintf("%g/s phase %v, %d per tick", t.Rate, t.Phase, t.PerTick)
+}
+
+func (t Ticks) Period() time.Duration {
+	if t.Rate <= 0 {
+		return 0
+	}
+	return time.Duration(float64(time.Second) / t.Rate)
+}
+
+func (t Ticks) index(now time.Time) int64 {
+	period := t.Period().Nanoseconds()
+	offset := now.UnixNano() - t.Phase.Nanoseconds()
+	index := offset / period
+	if offset%period < 0 {
+		index--
+	}
+	return index
+}
+
+func (t Ticks) start(index int64) time.Time {
+	return time.Unix(0, index*t.Period().Nanoseconds()+t.Phase.Nanoseconds())
+}
+
+func normalizeTicks(t Ticks) (Ticks, error) {
+	if math.IsNaN(t.Rate) || t.Rate < 0 || t.Rate > maxTickRate {
+		return t, fmt.Errorf("tick rate must be within [0, %d]", maxTickRate)
+	}
+	if t.PerTick < 0 {
+		return t, fmt.Errorf("clicks per tick must be >= 0")
+	}
+	if t.PerTick == 0 {
+		t.PerTick = 1
+	}
+	if t.Phase < 0 || (t.Rate > 0 && t.Phase >= t.Period()) {
+		return t, fmt.Errorf("tick phase must be within [0, tick period)")
+	}
+	return t, nil
+}
+
+func (s *Service) SetTicks(t Ticks) error {
+	t, err := normalizeTicks(t)
+	if err != nil {
+		return err
+	}
+	s.ticks.Store(&t)
+	for _, b := range s.currentBindings() {
+		b.signalWake()
+	}
+	return nil
+}
+
+func (s *Service) Ticks() Ticks {
+	return *s.ticks.Load()
+}
+
+type tickCount struct {
+	period time.Duration
+	index  int64
+	clicks int
+}
+
+func (c *tickCount) wait(t Ticks, now time.Time) time.Duration {
+	if t.Rate <= 0 {
+		return 0
+	}
+	index := t.index(now)
+	if c.period != t.Period() || c.index != index || c.clicks < t.PerTick {
+		return 0
+	}
+	return t.start(index + 1).Sub(now)
+}
+
+func (c *tickCount) claim(t Ticks, now time.Time) {
+	if t.Rate <= 0 {
+		return
+	}
+	index := t.index(now)
+	if c.period != t.Period() || c.index != index {
+		c.period, c.index, c.clicks = t.Period(), index, 0
+	}
+	c.clicks++
+}
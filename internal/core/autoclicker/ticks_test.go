@@ -0,0 +1,107 @@
+package autoclicker_test
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"clicker/internal/core/autoclicker"
+	"clicker/internal/core/autoclicker/autoclickertest"
+)
+
+func TestTicksDeferClicksToTheNextTick(t *testing.T) {
+	cfg := autoclickertest.Config(true)
+	cfg.CPS = 30
+	cfg.Ticks = autoclicker.Ticks{Rate: 20}
+
+	result := autoclickertest.Run(t, autoclickertest.Scenario{
+		Config: cfg,
+		Steps: []autoclickertest.Step{
+			autoclickertest.Press(0, cfg.TriggerCode),
+			autoclickertest.Release(480*time.Millisecond, cfg.TriggerCode),
+		},
+		Until: time.Second,
+	})
+
+	var want []time.Duration
+	for tick := range 10 {
+		want = append(want, time.Duration(tick)*50*time.Millisecond)
+	}
+	if got := clickStarts(result.Records, autoclicker.LeftButtonCode); !reflect.DeepEqual(got, want) {
+		t.Fatalf("clicks at %v, want %v", got, want)
+	}
+	if result.Stats.DeferredClicks != 9 {
+		t.Fatalf("DeferredClicks = %d, want 9", result.Stats.DeferredClicks)
+	}
+}
+
+func TestTicksLimitClicksPerTickAcrossBindings(t *testing.T) {
+	const extraTrigger = autoclicker.LeftButtonCode + 5
+	const extraOutput = autoclicker.LeftButtonCode + 1
+	cfg := autoclickertest.Config(true)
+	cfg.CPS = 50
+	cfg.Bindings = []autoclicker.Binding{{TriggerCode: extraTrigger, OutputCode: extraOutput, CPS: 25}}
+	cfg.Ticks = autoclicker.Ticks{Rate: 20, Phase: 15 * time.Millisecond, PerTick: 2}
+
+	result := autoclickertest.Run(t, autoclickertest.Scenario{
+		Config: cfg,
+		Steps: []autoclickertest.Step{
+			autoclickertest.Press(0, cfg.TriggerCode),
+			autoclickertest.Press(0, extraTrigger),
+			autoclickertest.Release(990*time.Millisecond, cfg.TriggerCode),
+			autoclickertest.Release(990*time.Millisecond, extraTrigger),
+		},
+		Until: 2 * time.Second,
+	})
+
+	// Ticks start at 15ms, 65ms, ...; the one before starts at -35ms.
+	perTick := make(map[int]int)
+	for _, code := range []uint16{autoclicker.LeftButtonCode, extraOutput} {
+		for _, start := range clickStarts(result.Records, code) {
+			perTick[int((start+35*time.Millisecond)/(50*time.Millisecond))]++
+		}
+	}
+	// Together the bindings ask for more than two clicks a tick, so every
+	// tick up to the release is full and none holds more.
+	for tick := range 21 {
+		if perTick[tick] != 2 {
+			t.Fatalf("tick %d has %d clicks, want 2 (all ticks: %v)", tick, perTick[tick], perTick)
+		}
+	}
+	if len(perTick) != 21 {
+		t.Fatalf("clicks in ticks %v, want ticks 0 to 20", perTick)
+	}
+	if result.Stats.DeferredClicks == 0 {
+		t.Fatalf("DeferredClicks = 0, want clicks deferred")
+	}
+}
+
+func TestTicksValidation(t *testing.T) {
+	for _, ticks := range []autoclicker.Ticks{
+		{Rate: -1},
+		{Rate: 5000},
+		{Rate: 20, PerTick: -1},
+		{Rate: 20, Phase: 50 * time.Millisecond},
+		{Rate: 20, Phase: -time.Millisecond},
+	} {
+		cfg := autoclickertest.Config(true)
+		cfg.Ticks = ticks
+		if _, err := autoclicker.NewService(cfg, autoclickertest.NewInjector(autoclicker.SystemClock()), &autoclickertest.Logger{}); err == nil {
+			t.Fatalf("NewService() accepted ticks %+v", ticks)
+		}
+	}
+
+	service, err := autoclicker.NewService(autoclickertest.Config(true), autoclickertest.NewInjector(autoclicker.SystemClock()), &autoclickertest.Logger{})
+	if err != nil {
+		t.Fatalf("NewService() error = %v", err)
+	}
+	if err := service.SetTicks(autoclicker.Ticks{Rate: 20}); err != nil {
+		t.Fatalf("SetTicks() error = %v", err)
+	}
+	if got, want := service.Ticks(), (autoclicker.Ticks{Rate: 20, PerTick: 1}); got != want {
+		t.Fatalf("Ticks() = %+v, want %+v", got, want)
+	}
+	if got := service.Ticks().Period(); got != 50*time.Millisecond {
+		t.Fatalf("Period() = %v, want 50ms", got)
+	}
+}
@@ -0,0 +1,150 @@
+package autoclicker
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"time"
+)
+
+const minTimingInterval = time.Millisecond
+
+type TimingModel interface {
+	NextInterval(rng *rand.Rand) time.Duration
+	Mean() time.Duration
+	String() string
+}
+
+func CPSInterval(cps float64) time.Duration {
+	return time.Duration(float64(time.Second) / cps)
+}
+
+type fixedTiming struct {
+	interval time.Duration
+}
+
+func FixedTiming(cps float64) (TimingModel, error) {
+	if cps <= 0 {
+		return nil, fmt.Errorf("cps must be > 0")
+	}
+	return fixedTiming{interval: CPSInterval(cps)}, nil
+}
+
+func (m fixedTiming) NextInterval(*rand.Rand) time.Duration {
+	return m.interval
+}
+
+func (m fixedTiming) Mean() time.Duration {
+	return m.interval
+}
+
+func (m fixedTiming) String() string {
+	return fmt.Sprintf("fixed %.2f cps", intervalCPS(m.interval))
+}
+
+type uniformTiming struct {
+	minCPS float64
+	maxCPS float64
+}
+
+func UniformTiming(minCPS, maxCPS float64) (TimingModel, error) {
+	if minCPS <= 0 || maxCPS <= 0 {
+		return nil, fmt.Errorf("cps must be > 0")
+	}
+	if maxCPS < minCPS {
+		minCPS, maxCPS = maxCPS, minCPS
+	}
+	return uniformTiming{minCPS: minCPS, maxCPS: maxCPS}, nil
+}
+
+func (m uniformTiming) NextInterval(rng *rand.Rand) time.Duration {
+	return CPSInterval(m.minCPS + rng.Float64()*(m.maxCPS-m.minCPS))
+}
+
+func (m uniformTiming) Mean() time.Duration {
+	if m.maxCPS == m.minCPS {
+		return CPSInterval(m.minCPS)
+	}
+	// Mean of 1/cps for cps uniform on [min, max].
+	seconds := math.Log(m.maxCPS/m.minCPS) / (m.maxCPS - m.minCPS)
+	return time.Duration(seconds * float64(time.Second))
+}
+
+func (m uniformTiming) String() string {
+	return fmt.Sprintf("uniform %.2f-%.2f cps", m.minCPS, m.maxCPS)
+}
+
+type gaussianTiming struct {
+	mean   time.Duration
+	stddev time.Duration
+}
+
+func GaussianTiming(meanCPS float64, stddev time.Duration) (TimingModel, error) {
+	if meanCPS <= 0 {
+		return nil, fmt.Errorf("cps must be > 0")
+	}
+	if stddev < 0 {
+		return nil, fmt.Errorf("stddev must be >= 0")
+	}
+	return gaussianTiming{mean: CPSInterval(meanCPS), stddev: stddev}, nil
+}
+
+func (m gaussianTiming) NextInterval(rng *rand.Rand) time.Duration {
+	interval := time.Duration(float64(m.mean) + rng.NormFloat64()*float64(m.stddev))
+	return max(interval, minTimingInterval)
+}
+
+func (m gaussianTiming) Mean() time.Duration {
+	return m.mean
+}
+
+func (m gaussianTiming) String() string {
+	return fmt.Sprintf("gaussian %.2f cps ±%v", intervalCPS(m.mean), m.stddev)
+}
+
+type logNormalTiming struct {
+	mean  time.Duration
+	mu    float64
+	sigma float64
+}
+
+func LogNormalTiming(meanCPS float64, stddev time.Duration) (TimingModel, error) {
+	if meanCPS <= 0 {
+		return nil, fmt.Errorf("cps must be > 0")
+	}
+	if stddev < 0 {
+		return nil, fmt.Errorf("stddev must be >= 0")
+	}
+	mean := CPSInterval(meanCPS)
+	ratio := float64(stddev) / float64(mean)
+	sigma2 := math.Log1p(ratio * ratio)
+	return logNormalTiming{
+		mean:  mean,
+		mu:    math.Log(float64(mean)) - sigma2/2,
+		sigma: math.Sqrt(sigma2),
+	}, nil
+}
+
+func (m logNormalTiming) NextInterval(rng *rand.Rand) time.Duration {
+	interval := time.Duration(math.Exp(m.mu + m.sigma*rng.NormFloat64()))
+	return max(interval, minTimingInterval)
+}
+
+func (m logNormalTiming) Mean() time.Duration {
+	return m.mean
+}
+
+func (m logNormalTiming) String() string {
+	return fmt.Sprintf("log-normal %.2f cps σ=%.2f", intervalCPS(m.mean), m.sigma)
+}
+
+func intervalCPS(interval time.Duration) float64 {
+	if interval <= 0 {
+		return 0
+	}
+	return float64(time.Second) / float64(interval)
+}
+
+type timingSlot struct {
+	model TimingModel
+}
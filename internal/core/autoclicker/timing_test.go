@@ -0,0 +1,138 @@
+package autoclicker_test
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+	"time"
+
+	"clicker/internal/core/autoclicker"
+	"clicker/internal/core/autoclicker/autoclickertest"
+)
+
+func sampleIntervals(model autoclicker.TimingModel, n int) (mean, stddev float64, lo, hi time.Duration) {
+	rng := rand.New(rand.NewSource(1))
+	values := make([]float64, n)
+	lo, hi = time.Duration(math.MaxInt64), 0
+	for i := range values {
+		interval := model.NextInterval(rng)
+		lo = min(lo, interval)
+		hi = max(hi, interval)
+		values[i] = float64(interval)
+		mean += values[i]
+	}
+	mean /= float64(n)
+	for _, v := range values {
+		stddev += (v - mean) * (v - mean)
+	}
+	return mean, math.Sqrt(stddev / float64(n)), lo, hi
+}
+
+func TestFixedTimingReturnsConstantInterval(t *testing.T) {
+	model, err := autoclicker.FixedTiming(20)
+	if err != nil {
+		t.Fatalf("FixedTiming() error = %v", err)
+	}
+	_, _, lo, hi := sampleIntervals(model, 100)
+	if lo != 50*time.Millisecond || hi != 50*time.Millisecond {
+		t.Fatalf("expected every interval to be 50ms, got [%v, %v]", lo, hi)
+	}
+}
+
+func TestUniformTimingStaysWithinRange(t *testing.T) {
+	model, err := autoclicker.UniformTiming(10, 20)
+	if err != nil {
+		t.Fatalf("UniformTiming() error = %v", err)
+	}
+	mean, _, lo, hi := sampleIntervals(model, 10000)
+	if lo < 50*time.Millisecond || hi > 100*time.Millisecond {
+		t.Fatalf("intervals outside [50ms, 100ms]: [%v, %v]", lo, hi)
+	}
+	if want := float64(model.Mean()); math.Abs(mean-want)/want > 0.02 {
+		t.Fatalf("sample mean %v far from Mean() %v", time.Duration(mean), model.Mean())
+	}
+}
+
+func TestGaussianTimingMatchesParameters(t *testing.T) {
+	model, err := autoclicker.GaussianTiming(10, 10*time.Millisecond)
+	if err != nil {
+		t.Fatalf("GaussianTiming() error = %v", err)
+	}
+	mean, stddev, _, _ := sampleIntervals(model, 20000)
+	if math.Abs(mean-float64(100*time.Millisecond)) > float64(time.Millisecond) {
+		t.Fatalf("sample mean %v, want ~100ms", time.Duration(mean))
+	}
+	if math.Abs(stddev-float64(10*time.Millisecond)) > float64(time.Millisecond) {
+		t.Fatalf("sample stddev %v, want ~10ms", time.Duration(stddev))
+	}
+}
+
+func TestGaussianTimingNeverReturnsNonPositive(t *testing.T) {
+	model, err := autoclicker.GaussianTiming(100, time.Second)
+	if err != nil {
+		t.Fatalf("GaussianTiming() error = %v", err)
+	}
+	if _, _, lo, _ := sampleIntervals(model, 1000); lo < autoclicker.MinTimingInterval {
+		t.Fatalf("interval %v below floor %v", lo, autoclicker.MinTimingInterval)
+	}
+}
+
+func TestLogNormalTimingMatchesParameters(t *testing.T) {
+	model, err := autoclicker.LogNormalTiming(10, 30*time.Millisecond)
+	if err != nil {
+		t.Fatalf("LogNormalTiming() error = %v", err)
+	}
+	mean, stddev, lo, _ := sampleIntervals(model, 50000)
+	if lo <= 0 {
+		t.Fatalf("expected positive intervals, got %v", lo)
+	}
+	if math.Abs(mean-float64(100*time.Millisecond)) > 2*float64(time.Millisecond) {
+		t.Fatalf("sample mean %v, want ~100ms", time.Duration(mean))
+	}
+	if math.Abs(stddev-float64(30*time.Millisecond)) > 3*float64(time.Millisecond) {
+		t.Fatalf("sample stddev %v, want ~30ms", time.Duration(stddev))
+	}
+}
+
+func TestTimingConstructorsRejectInvalidParameters(t *testing.T) {
+	if _, err := autoclicker.FixedTiming(0); err == nil {
+		t.Fatalf("expected FixedTiming(0) to fail")
+	}
+	if _, err := autoclicker.UniformTiming(-1, 5); err == nil {
+		t.Fatalf("expected UniformTiming with negative cps to fail")
+	}
+	if _, err := autoclicker.GaussianTiming(10, -time.Millisecond); err == nil {
+		t.Fatalf("expected GaussianTiming with negative stddev to fail")
+	}
+	if _, err := autoclicker.LogNormalTiming(0, time.Millisecond); err == nil {
+		t.Fatalf("expected LogNormalTiming(0) to fail")
+	}
+}
+
+func TestSetTimingModelReplacesPrimaryTiming(t *testing.T) {
+	service, err := autoclicker.NewService(autoclickertest.Config(true), &autoclickertest.Injector{}, &autoclickertest.Logger{})
+	if err != nil {
+		t.Fatalf("NewService() error = %v", err)
+	}
+	if err := service.SetTimingModel(nil); err == nil {
+		t.Fatalf("expected nil timing model to be rejected")
+	}
+
+	model, err := autoclicker.UniformTiming(8, 12)
+	if err != nil {
+		t.Fatalf("UniformTiming() error = %v", err)
+	}
+	if err := service.SetTimingModel(model); err != nil {
+		t.Fatalf("SetTimingModel() error = %v", err)
+	}
+	if got := service.TimingModel(); got != model {
+		t.Fatalf("TimingModel() = %v, want %v", got, model)
+	}
+
+	if err := service.SetCPS(20); err != nil {
+		t.Fatalf("SetCPS() error = %v", err)
+	}
+	if got := service.TimingModel().Mean(); got != 50*time.Millisecond {
+		t.Fatalf("SetCPS should switch to a fixed 50ms interval, got mean %v", got)
+	}
+}
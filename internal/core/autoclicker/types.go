@@ -1,6 +1,10 @@
 package autoclicker
 
-import "time"
+import (
+	"fmt"
+	"strings"
+	"time"
+)
 
 const (
 	EventTypeSyn uint16 = 0x00
@@ -23,15 +27,125 @@ type Event struct {
 type Config struct {
 	TriggerCode        uint16
 	ToggleCode         uint16
+	OutputCode         uint16
 	TriggerSources     map[string]struct{}
 	ToggleSources      map[string]struct{}
 	GrabSources        map[string]struct{}
 	GrabEnabled        bool
 	PassThroughTrigger bool
 	CPS                float64
+	Timing             TimingModel
+	Curve              RateCurve
 	ClickDown          time.Duration
+	ClickDownModel     ClickDownModel
 	JitterPixels       int
 	StartEnabled       bool
+	TriggerMode        TriggerMode
+	LatchThreshold     time.Duration
+	BurstCount         int
+	BurstCooldown      time.Duration
+	Ticks              Ticks
+	MaxCPSWindow       int
+	CatchUp            CatchUpPolicy
+	Ramp               Ramp
+	MaxHold            time.Duration
+	HoldDelay          time.Duration
+	DebouncePress      time.Duration
+	DebounceRelease    time.Duration
+	PauseCodes         []uint16
+	PauseFor           time.Duration
+	PauseWhileTyping   bool
+	TypingPause        time.Duration
+	PauseSources       map[string]struct{}
+	ToggleGesture      ToggleGesture
+	DoubleTapWindow    time.Duration
+	LongPressThreshold time.Duration
+	PanicCode          uint16
+	PanicSources       map[string]struct{}
+	PanicAction        PanicAction
+	OnPanic            func(PanicAction)
+	Pattern            Pattern
+	Bindings           []Binding
+	Clock              Clock
+}
+
+type Binding struct {
+	TriggerCode    uint16
+	OutputCode     uint16
+	CPS            float64
+	Timing         TimingModel
+	Curve          RateCurve
+	ClickDown      time.Duration
+	ClickDownModel ClickDownModel
+	JitterPixels   int
+	Pattern        Pattern
+}
+
+type TriggerMode uint8
+
+const (
+	TriggerModeHold TriggerMode = iota
+	TriggerModeLatch
+	TriggerModeHoldOrLatch
+)
+
+const DefaultLatchThreshold = 250 * time.Millisecond
+
+var triggerModeNames = map[TriggerMode]string{
+	TriggerModeHold:        "hold",
+	TriggerModeLatch:       "latch",
+	TriggerModeHoldOrLatch: "hold-or-latch",
+}
+
+func (m TriggerMode) String() string {
+	if name, ok := triggerModeNames[m]; ok {
+		return name
+	}
+	return fmt.Sprintf("TriggerMode(%d)", uint8(m))
+}
+
+func (m TriggerMode) valid() bool {
+	_, ok := triggerModeNames[m]
+	return ok
+}
+
+func ParseTriggerMode(raw string) (TriggerMode, error) {
+	value := strings.ToLower(strings.TrimSpace(raw))
+	for mode, name := range triggerModeNames {
+		if value == name {
+			return mode, nil
+		}
+	}
+	return TriggerModeHold, fmt.Errorf("unknown trigger mode %q (expected hold, latch or hold-or-latch)", raw)
+}
+
+type CatchUpPolicy uint8
+
+const (
+	CatchUpSkip CatchUpPolicy = iota
+	CatchUpCompress
+)
+
+var catchUpPolicyNames = map[CatchUpPolicy]string{
+	CatchUpSkip:     "skip",
+	CatchUpCompress: "compress",
+}
+
+func (p CatchUpPolicy) String() string {
+	if name, ok := catchUpPolicyNames[p]; ok {
+		return name
+	}
+	return fmt.Sprintf("CatchUpPolicy(%d)", uint8(p))
+}
+
+func ParseCatchUpPolicy(raw string) (CatchUpPolicy, error) {
+	value := strings.ToLower(strings.TrimSpace(raw))
+	for policy, name := range catchUpPolicyNames {
+		if value == name {
+			return policy, nil
+		}
+	}
+	return CatchUpSkip, fmt.Errorf("unknown catch-up policy %q (expected skip or compress)", raw)
 }
 
 type Injector interface {